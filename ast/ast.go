@@ -3,8 +3,10 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"strings"
 
+	"github.com/senither/zen-lang/objects/decimal"
 	"github.com/senither/zen-lang/tokens"
 )
 
@@ -59,6 +61,7 @@ func (es *EmptyStatement) String() string         { return "" }
 type VariableStatement struct {
 	Token   tokens.Token
 	Name    *Identifier
+	Pattern Expression
 	Value   Expression
 	Mutable bool
 }
@@ -75,7 +78,12 @@ func (ls *VariableStatement) String() string {
 		out.WriteString("mut ")
 	}
 
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
+
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -140,6 +148,8 @@ func (es *ExpressionStatement) String() string {
 type IntegerLiteral struct {
 	Token tokens.Token
 	Value int64
+	// Big holds the value of literals too large to fit in an int64.
+	Big *big.Int
 }
 
 func (il *IntegerLiteral) expressionNode()        {}
@@ -157,6 +167,16 @@ func (fl *FloatLiteral) GetToken() tokens.Token { return fl.Token }
 func (fl *FloatLiteral) TokenLiteral() string   { return fl.Token.Literal }
 func (fl *FloatLiteral) String() string         { return fl.Token.Literal }
 
+type DecimalLiteral struct {
+	Token tokens.Token
+	Value decimal.Decimal
+}
+
+func (dl *DecimalLiteral) expressionNode()        {}
+func (dl *DecimalLiteral) GetToken() tokens.Token { return dl.Token }
+func (dl *DecimalLiteral) TokenLiteral() string   { return dl.Token.Literal }
+func (dl *DecimalLiteral) String() string         { return dl.Token.Literal + "d" }
+
 type StringLiteral struct {
 	Token tokens.Token
 	Value string
@@ -169,6 +189,32 @@ func (sl *StringLiteral) String() string {
 	return fmt.Sprintf("%q", sl.Value)
 }
 
+type TemplateLiteral struct {
+	Token tokens.Token
+	Parts []Expression
+}
+
+func (tl *TemplateLiteral) expressionNode()        {}
+func (tl *TemplateLiteral) GetToken() tokens.Token { return tl.Token }
+func (tl *TemplateLiteral) TokenLiteral() string   { return tl.Token.Literal }
+func (tl *TemplateLiteral) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+
+	for _, part := range tl.Parts {
+		if str, ok := part.(*StringLiteral); ok {
+			out.WriteString(str.Value)
+		} else {
+			out.WriteString("${" + part.String() + "}")
+		}
+	}
+
+	out.WriteString("\"")
+
+	return out.String()
+}
+
 type NullLiteral struct {
 	Token tokens.Token
 }
@@ -212,8 +258,9 @@ func (al *ArrayLiteral) String() string {
 }
 
 type HashLiteral struct {
-	Token tokens.Token
-	Pairs map[Expression]Expression
+	Token   tokens.Token
+	Pairs   map[Expression]Expression
+	Spreads []*SpreadElement
 }
 
 func (hl *HashLiteral) expressionNode()        {}
@@ -223,6 +270,10 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
+	for _, spread := range hl.Spreads {
+		pairs = append(pairs, spread.String())
+	}
+
 	for key, value := range hl.Pairs {
 		pairs = append(pairs, fmt.Sprintf("%q: %s", key.String(), value.String()))
 	}
@@ -234,10 +285,23 @@ func (hl *HashLiteral) String() string {
 	return out.String()
 }
 
-type IndexExpression struct {
+type SpreadElement struct {
 	Token tokens.Token
-	Left  Expression
-	Index Expression
+	Value Expression
+}
+
+func (se *SpreadElement) expressionNode()        {}
+func (se *SpreadElement) GetToken() tokens.Token { return se.Token }
+func (se *SpreadElement) TokenLiteral() string   { return se.Token.Literal }
+func (se *SpreadElement) String() string         { return "..." + se.Value.String() }
+
+type IndexExpression struct {
+	Token    tokens.Token
+	Left     Expression
+	Index    Expression
+	End      Expression
+	Slice    bool
+	Optional bool
 }
 
 func (ie *IndexExpression) expressionNode()        {}
@@ -248,13 +312,46 @@ func (ie *IndexExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString("[")
-	out.WriteString(ie.Index.String())
+	if ie.Index != nil {
+		out.WriteString(ie.Index.String())
+	}
+	if ie.Slice {
+		out.WriteString(":")
+		if ie.End != nil {
+			out.WriteString(ie.End.String())
+		}
+	}
 	out.WriteString("])")
 
 	return out.String()
 }
 
+type RangeExpression struct {
+	Token     tokens.Token
+	Start     Expression
+	End       Expression
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode()        {}
+func (re *RangeExpression) GetToken() tokens.Token { return re.Token }
+func (re *RangeExpression) TokenLiteral() string   { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(re.Start.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.End.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type PrefixExpression struct {
 	Token    tokens.Token
 	Operator string
@@ -297,6 +394,30 @@ func (ie *InfixExpression) String() string {
 	return out.String()
 }
 
+type TernaryExpression struct {
+	Token       tokens.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (te *TernaryExpression) expressionNode()        {}
+func (te *TernaryExpression) GetToken() tokens.Token { return te.Token }
+func (te *TernaryExpression) TokenLiteral() string   { return te.Token.Literal }
+func (te *TernaryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(te.Condition.String())
+	out.WriteString(" ? ")
+	out.WriteString(te.Consequence.String())
+	out.WriteString(" : ")
+	out.WriteString(te.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type SuffixExpression struct {
 	Token    tokens.Token
 	Operator string
@@ -318,9 +439,10 @@ func (se *SuffixExpression) String() string {
 }
 
 type ChainExpression struct {
-	Token tokens.Token
-	Left  Expression
-	Right Expression
+	Token    tokens.Token
+	Left     Expression
+	Right    Expression
+	Optional bool
 }
 
 func (ce *ChainExpression) expressionNode()        {}
@@ -331,6 +453,9 @@ func (ce *ChainExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(ce.Left.String())
+	if ce.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString(".")
 	out.WriteString(ce.Right.String())
 	out.WriteString(")")
@@ -393,6 +518,214 @@ func (w *WhileExpression) String() string {
 	return out.String()
 }
 
+type ForExpression struct {
+	Token    tokens.Token
+	Key      *Identifier
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (f *ForExpression) expressionNode()        {}
+func (f *ForExpression) GetToken() tokens.Token { return f.Token }
+func (f *ForExpression) TokenLiteral() string   { return f.Token.Literal }
+func (f *ForExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+
+	if f.Key != nil {
+		out.WriteString(f.Key.String())
+		out.WriteString(", ")
+	}
+
+	out.WriteString(f.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(f.Iterable.String())
+	out.WriteString(") { ")
+	out.WriteString(f.Body.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type MatchExpression struct {
+	Token   tokens.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) expressionNode()        {}
+func (me *MatchExpression) GetToken() tokens.Token { return me.Token }
+func (me *MatchExpression) TokenLiteral() string   { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// EnumCoverage returns the name of the enum every pattern of the match is a
+// variant of, along with the variants used by the patterns, which are true if
+// an arm always matches the variant, arms with guards, or patterns that only
+// match some values, don't cover the variant. It returns false when there are
+// other patterns, like a catch-all identifier.
+func (me *MatchExpression) EnumCoverage() (string, map[string]bool, bool) {
+	enum := ""
+	covered := map[string]bool{}
+
+	for _, arm := range me.Arms {
+		for _, pattern := range arm.Patterns {
+			variant, ok := pattern.(*VariantPattern)
+			if !ok || (enum != "" && variant.Enum.Value != enum) {
+				return "", nil, false
+			}
+
+			enum = variant.Enum.Value
+			name := variant.Variant.Value
+
+			covered[name] = covered[name] || (arm.Guard == nil && variant.matchesAllValues())
+		}
+	}
+
+	return enum, covered, enum != ""
+}
+
+type MatchArm struct {
+	Token    tokens.Token
+	Patterns []Expression
+	Guard    Expression
+	Body     *BlockStatement
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	patterns := []string{}
+	for _, pattern := range ma.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+
+	out.WriteString(strings.Join(patterns, ", "))
+
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+
+	out.WriteString(" => { ")
+	out.WriteString(ma.Body.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type ArrayPattern struct {
+	Token    tokens.Token
+	Elements []Expression
+}
+
+func (ap *ArrayPattern) expressionNode()        {}
+func (ap *ArrayPattern) GetToken() tokens.Token { return ap.Token }
+func (ap *ArrayPattern) TokenLiteral() string   { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+type HashPattern struct {
+	Token  tokens.Token
+	Keys   []*StringLiteral
+	Values []Expression
+}
+
+func (hp *HashPattern) expressionNode()        {}
+func (hp *HashPattern) GetToken() tokens.Token { return hp.Token }
+func (hp *HashPattern) TokenLiteral() string   { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		if ident, ok := hp.Values[i].(*Identifier); ok && ident.Value == key.Value {
+			pairs = append(pairs, ident.Value)
+			continue
+		}
+
+		if def, ok := hp.Values[i].(*DefaultPattern); ok {
+			if ident, ok := def.Pattern.(*Identifier); ok && ident.Value == key.Value {
+				pairs = append(pairs, def.String())
+				continue
+			}
+		}
+
+		pairs = append(pairs, fmt.Sprintf("%s: %s", key.String(), hp.Values[i].String()))
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+type VariantPattern struct {
+	Token    tokens.Token
+	Enum     *Identifier
+	Variant  *Identifier
+	Patterns []Expression
+}
+
+func (vp *VariantPattern) expressionNode()        {}
+func (vp *VariantPattern) GetToken() tokens.Token { return vp.Token }
+func (vp *VariantPattern) TokenLiteral() string   { return vp.Token.Literal }
+func (vp *VariantPattern) String() string {
+	name := vp.Enum.String() + "." + vp.Variant.String()
+	if len(vp.Patterns) == 0 {
+		return name
+	}
+
+	patterns := []string{}
+	for _, pattern := range vp.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+
+	return name + "(" + strings.Join(patterns, ", ") + ")"
+}
+
+// matchesAllValues reports whether every value of the variant is matched,
+// which is only the case when its values are bound to identifiers.
+func (vp *VariantPattern) matchesAllValues() bool {
+	for _, pattern := range vp.Patterns {
+		if _, ok := pattern.(*Identifier); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+type DefaultPattern struct {
+	Token   tokens.Token
+	Pattern Expression
+	Default Expression
+}
+
+func (dp *DefaultPattern) expressionNode()        {}
+func (dp *DefaultPattern) GetToken() tokens.Token { return dp.Token }
+func (dp *DefaultPattern) TokenLiteral() string   { return dp.Token.Literal }
+func (dp *DefaultPattern) String() string {
+	return dp.Pattern.String() + " = " + dp.Default.String()
+}
+
 type BlockStatement struct {
 	Token      tokens.Token
 	Statements []Statement
@@ -414,8 +747,11 @@ func (bs *BlockStatement) String() string {
 type FunctionLiteral struct {
 	Token      tokens.Token
 	Name       *Identifier
-	Parameters []*Identifier
+	Parameters []Expression
+	Rest       *Identifier
 	Body       *BlockStatement
+	Generator  bool
+	Async      bool
 }
 
 func (fl *FunctionLiteral) expressionNode()        {}
@@ -429,7 +765,19 @@ func (fl *FunctionLiteral) String() string {
 		params = append(params, param.String())
 	}
 
+	if fl.Rest != nil {
+		params = append(params, "..."+fl.Rest.String())
+	}
+
+	if fl.Async {
+		out.WriteString("async ")
+	}
+
 	out.WriteString(fl.TokenLiteral())
+	if fl.Generator {
+		out.WriteString("*")
+	}
+
 	out.WriteString(" ")
 
 	if fl.Name != nil {
@@ -445,6 +793,48 @@ func (fl *FunctionLiteral) String() string {
 	return out.String()
 }
 
+type MethodLiteral struct {
+	Token    tokens.Token
+	Receiver *Identifier
+	Struct   *Identifier
+	Function *FunctionLiteral
+}
+
+func (ml *MethodLiteral) expressionNode()        {}
+func (ml *MethodLiteral) GetToken() tokens.Token { return ml.Token }
+func (ml *MethodLiteral) TokenLiteral() string   { return ml.Token.Literal }
+func (ml *MethodLiteral) String() string {
+	var out bytes.Buffer
+
+	if ml.Function.Async {
+		out.WriteString("async ")
+	}
+
+	out.WriteString(ml.TokenLiteral())
+	out.WriteString(" (")
+	out.WriteString(ml.Receiver.String())
+	out.WriteString(" ")
+	out.WriteString(ml.Struct.String())
+	out.WriteString(") ")
+	function := strings.TrimPrefix(ml.Function.String(), "async ")
+	out.WriteString(strings.TrimPrefix(function, ml.Function.TokenLiteral()+" "))
+
+	return out.String()
+}
+
+// RequiredParameters returns the number of arguments that must be given when
+// calling a function with the given parameters, which includes every
+// parameter up to and including the last one without a default.
+func RequiredParameters(params []Expression) int {
+	for i := len(params) - 1; i >= 0; i-- {
+		if _, ok := params[i].(*DefaultPattern); !ok {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
 type CallExpression struct {
 	Token     tokens.Token
 	Function  Expression
@@ -470,6 +860,30 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+type SpawnExpression struct {
+	Token tokens.Token
+	Call  *CallExpression
+}
+
+func (se *SpawnExpression) expressionNode()        {}
+func (se *SpawnExpression) GetToken() tokens.Token { return se.Token }
+func (se *SpawnExpression) TokenLiteral() string   { return se.Token.Literal }
+func (se *SpawnExpression) String() string {
+	return "spawn " + se.Call.String()
+}
+
+type AwaitExpression struct {
+	Token tokens.Token
+	Value Expression
+}
+
+func (ae *AwaitExpression) expressionNode()        {}
+func (ae *AwaitExpression) GetToken() tokens.Token { return ae.Token }
+func (ae *AwaitExpression) TokenLiteral() string   { return ae.Token.Literal }
+func (ae *AwaitExpression) String() string {
+	return "await " + ae.Value.String()
+}
+
 type AssignmentExpression struct {
 	Token tokens.Token
 	Left  Expression
@@ -548,3 +962,140 @@ func (cs *ContinueStatement) statementNode()         {}
 func (cs *ContinueStatement) GetToken() tokens.Token { return cs.Token }
 func (cs *ContinueStatement) TokenLiteral() string   { return cs.Token.Literal }
 func (cs *ContinueStatement) String() string         { return "continue;" }
+
+type TryStatement struct {
+	Token     tokens.Token
+	Block     *BlockStatement
+	Parameter *Identifier
+	Catch     *BlockStatement
+}
+
+func (ts *TryStatement) statementNode()         {}
+func (ts *TryStatement) GetToken() tokens.Token { return ts.Token }
+func (ts *TryStatement) TokenLiteral() string   { return ts.Token.Literal }
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(ts.Block.String())
+	out.WriteString(" catch ")
+
+	if ts.Parameter != nil {
+		out.WriteString("(" + ts.Parameter.String() + ") ")
+	}
+
+	out.WriteString(ts.Catch.String())
+
+	return out.String()
+}
+
+type ThrowStatement struct {
+	Token tokens.Token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()         {}
+func (ts *ThrowStatement) GetToken() tokens.Token { return ts.Token }
+func (ts *ThrowStatement) TokenLiteral() string   { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("throw ")
+	out.WriteString(ts.Value.String())
+	out.WriteString(";")
+
+	return out.String()
+}
+
+type YieldStatement struct {
+	Token tokens.Token
+	Value Expression
+}
+
+func (ys *YieldStatement) statementNode()         {}
+func (ys *YieldStatement) GetToken() tokens.Token { return ys.Token }
+func (ys *YieldStatement) TokenLiteral() string   { return ys.Token.Literal }
+func (ys *YieldStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("yield")
+
+	if ys.Value != nil {
+		out.WriteString(" ")
+		out.WriteString(ys.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+type StructStatement struct {
+	Token  tokens.Token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ss *StructStatement) statementNode()         {}
+func (ss *StructStatement) GetToken() tokens.Token { return ss.Token }
+func (ss *StructStatement) TokenLiteral() string   { return ss.Token.Literal }
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, field := range ss.Fields {
+		fields = append(fields, field.String())
+	}
+
+	out.WriteString("struct ")
+	out.WriteString(ss.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type EnumStatement struct {
+	Token    tokens.Token
+	Name     *Identifier
+	Variants []*EnumVariant
+}
+
+func (es *EnumStatement) statementNode()         {}
+func (es *EnumStatement) GetToken() tokens.Token { return es.Token }
+func (es *EnumStatement) TokenLiteral() string   { return es.Token.Literal }
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, variant := range es.Variants {
+		variants = append(variants, variant.String())
+	}
+
+	out.WriteString("enum ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type EnumVariant struct {
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ev *EnumVariant) String() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name.String()
+	}
+
+	fields := []string{}
+	for _, field := range ev.Fields {
+		fields = append(fields, field.String())
+	}
+
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/senither/zen-lang/compiler"
 	"github.com/senither/zen-lang/evaluator"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/timer"
 	"github.com/senither/zen-lang/vm"
 	"github.com/spf13/cobra"
 )
@@ -113,12 +114,19 @@ func runAndReturnEvaluated(verbose bool, program *ast.Program, path any) string
 
 	var evaluated objects.Object = nil
 
+	evaluate := func() objects.Object {
+		evaluated := evaluator.Eval(program, env)
+		if !objects.IsError(evaluated) {
+			timer.RunEventLoop()
+		}
+
+		return evaluated
+	}
+
 	if verbose {
-		evaluated = evaluator.Eval(program, env)
+		evaluated = evaluate()
 	} else {
-		evaluated = evaluator.Stdout.Mute(func() objects.Object {
-			return evaluator.Eval(program, env)
-		})
+		evaluated = evaluator.Stdout.Mute(evaluate)
 	}
 
 	objects.RestoreObjectsState()
@@ -158,6 +166,8 @@ func runAndReturnVirtualMachineResult(verbose bool, bytecode *compiler.Bytecode,
 			return objects.NativeErrorToErrorObject(err)
 		}
 
+		timer.RunEventLoop()
+
 		return machine.LastPoppedStackElem()
 	})
 
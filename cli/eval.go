@@ -5,6 +5,7 @@ import (
 
 	"github.com/senither/zen-lang/evaluator"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/timer"
 	"github.com/spf13/cobra"
 )
 
@@ -31,9 +32,19 @@ var evalCommand = &cobra.Command{
 			}
 
 			evaluated := evaluator.Eval(program, env)
+			if path != nil && !objects.IsError(evaluated) {
+				timer.RunEventLoop()
+			}
+
 			if evaluated != nil && evaluated.Type() != objects.NULL_OBJ {
 				fmt.Printf("%s\n", evaluated.Inspect())
 			}
+
+			// The interactive REPL doesn't run the event loop, so the timers
+			// that have become due are run after each input instead.
+			if path == nil {
+				timer.RunDueTimers()
+			}
 		})
 	},
 }
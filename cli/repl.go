@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 
+	"github.com/senither/zen-lang/objects/timer"
 	"github.com/senither/zen-lang/vm"
 	"github.com/spf13/cobra"
 )
@@ -35,10 +36,20 @@ var replCommand = &cobra.Command{
 				return
 			}
 
+			if path != nil {
+				timer.RunEventLoop()
+			}
+
 			stackTop := vm.LastPoppedStackElem()
 			if stackTop != nil {
 				fmt.Printf("%s\n", stackTop.Inspect())
 			}
+
+			// The interactive REPL doesn't run the event loop, so the timers
+			// that have become due are run after each input instead.
+			if path == nil {
+				timer.RunDueTimers()
+			}
 		})
 	},
 }
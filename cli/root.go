@@ -10,6 +10,7 @@ import (
 	"github.com/senither/zen-lang/compiler"
 	"github.com/senither/zen-lang/lexer"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/timer"
 	"github.com/senither/zen-lang/parser"
 	"github.com/senither/zen-lang/vm"
 	"github.com/spf13/cobra"
@@ -51,6 +52,8 @@ var rootCommand = &cobra.Command{
 			return
 		}
 
+		timer.RunEventLoop()
+
 		stackTop := vm.LastPoppedStackElem()
 		if stackTop != nil && stackTop != objects.NULL {
 			fmt.Printf("%s\n", stackTop.Inspect())
@@ -7,6 +7,7 @@ import (
 	"github.com/senither/zen-lang/ast"
 	"github.com/senither/zen-lang/evaluator"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/timer"
 )
 
 func (tr *TestRunner) runEvaluatorTest(test *Test, program *ast.Program, fullPath, file string) {
@@ -16,7 +17,13 @@ func (tr *TestRunner) runEvaluatorTest(test *Test, program *ast.Program, fullPat
 	start := time.Now()
 	evaluated := evaluator.Stdout.Mute(func() objects.Object {
 		env := objects.NewEnvironment(file)
-		return evaluator.Eval(program, env)
+
+		evaluated := evaluator.Eval(program, env)
+		if !objects.IsError(evaluated) {
+			timer.RunEventLoop()
+		}
+
+		return evaluated
 	})
 	timeTaken := time.Since(start)
 
@@ -7,6 +7,7 @@ import (
 	"github.com/senither/zen-lang/ast"
 	"github.com/senither/zen-lang/compiler"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/timer"
 	"github.com/senither/zen-lang/vm"
 )
 
@@ -67,6 +68,8 @@ func (tr *TestRunner) runCompiledVMTest(test *Test, bytecode *compiler.Bytecode,
 			return objects.NativeErrorToErrorObject(runErr)
 		}
 
+		timer.RunEventLoop()
+
 		return runner.LastPoppedStackElem()
 	})
 	timeTaken := time.Since(start)
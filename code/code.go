@@ -16,6 +16,8 @@ const (
 	// Jumps
 	OpJump
 	OpJumpNotTruthy
+	OpJumpNotNull
+	OpJumpNull
 
 	// Globals
 	OpSetGlobal
@@ -54,6 +56,14 @@ const (
 	OpMinus
 	OpBang
 
+	// Bitwise
+	OpBitAnd
+	OpBitOr
+	OpBitXor
+	OpBitNot
+	OpShiftLeft
+	OpShiftRight
+
 	// Suffixes
 	OpIndex
 	OpIndexAssign
@@ -61,14 +71,42 @@ const (
 	// Objects
 	OpArray
 	OpHash
+	OpConcat
+	OpSpread
+	OpRange
+	OpSlice
+
+	// Structs
+	OpStruct
+	OpMethod
+	OpGetField
+	OpSetField
 
 	// Loop control
 	OpLoopEnd
+	OpIterator
+	OpIterNext
+
+	// Exceptions
+	OpTry
+	OpEndTry
+	OpThrow
+
+	// Pattern matching
+	OpMatchArray
+	OpMatchHash
+	OpMatchVariant
+	OpDestructureArray
+	OpDestructureHash
 
 	// Functions
 	OpCall
+	OpTailCall
 	OpReturnValue
 	OpReturn
+	OpYield
+	OpSpawn
+	OpAwait
 
 	// Internal Functions
 	OpGetBuiltin
@@ -77,6 +115,12 @@ const (
 	// Closures
 	OpClosure
 	OpGetFree
+	OpSetFree
+	OpCaptureLocal
+	OpCaptureFree
+	OpIncFree
+	OpDecFree
+	OpCloseUpvalues
 	OpCurrentClosure
 
 	// Import/Export
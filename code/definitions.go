@@ -17,6 +17,8 @@ var definitions = map[Opcode]*Definition{
 	// Jumps
 	OpJump:          {"OpJump", []int{2}},
 	OpJumpNotTruthy: {"OpJumpNotTruthy", []int{2}},
+	OpJumpNotNull:   {"OpJumpNotNull", []int{2}},
+	OpJumpNull:      {"OpJumpNull", []int{2}},
 	// Globals
 	OpSetGlobal: {"OpSetGlobal", []int{2}},
 	OpGetGlobal: {"OpGetGlobal", []int{2}},
@@ -48,24 +50,62 @@ var definitions = map[Opcode]*Definition{
 	// Prefixes
 	OpMinus: {"OpMinus", []int{}},
 	OpBang:  {"OpBang", []int{}},
+	// Bitwise
+	OpBitAnd:     {"OpBitAnd", []int{}},
+	OpBitOr:      {"OpBitOr", []int{}},
+	OpBitXor:     {"OpBitXor", []int{}},
+	OpBitNot:     {"OpBitNot", []int{}},
+	OpShiftLeft:  {"OpShiftLeft", []int{}},
+	OpShiftRight: {"OpShiftRight", []int{}},
 	// Suffixes
 	OpIndex:       {"OpIndex", []int{}},
 	OpIndexAssign: {"OpIndexAssign", []int{}},
 	// Objects
-	OpArray: {"OpArray", []int{2}},
-	OpHash:  {"OpHash", []int{2}},
+	OpArray:  {"OpArray", []int{2}},
+	OpHash:   {"OpHash", []int{2}},
+	OpConcat: {"OpConcat", []int{2}},
+	OpSpread: {"OpSpread", []int{}},
+	OpRange:  {"OpRange", []int{1}},
+	OpSlice:  {"OpSlice", []int{}},
+	// Structs
+	OpStruct:   {"OpStruct", []int{2, 1}},
+	OpMethod:   {"OpMethod", []int{2}},
+	OpGetField: {"OpGetField", []int{2}},
+	OpSetField: {"OpSetField", []int{2}},
 	// Loop control
-	OpLoopEnd: {"OpLoopEnd", []int{}},
+	OpLoopEnd:  {"OpLoopEnd", []int{}},
+	OpIterator: {"OpIterator", []int{}},
+	OpIterNext: {"OpIterNext", []int{2}},
+	// Exceptions
+	OpTry:    {"OpTry", []int{2}},
+	OpEndTry: {"OpEndTry", []int{}},
+	OpThrow:  {"OpThrow", []int{}},
+	// Pattern matching
+	OpMatchArray:       {"OpMatchArray", []int{2}},
+	OpMatchHash:        {"OpMatchHash", []int{2}},
+	OpMatchVariant:     {"OpMatchVariant", []int{2}},
+	OpDestructureArray: {"OpDestructureArray", []int{}},
+	OpDestructureHash:  {"OpDestructureHash", []int{}},
 	// Functions
 	OpCall:        {"OpCall", []int{1}},
+	OpTailCall:    {"OpTailCall", []int{1}},
 	OpReturnValue: {"OpReturnValue", []int{}},
 	OpReturn:      {"OpReturn", []int{}},
+	OpYield:       {"OpYield", []int{}},
+	OpSpawn:       {"OpSpawn", []int{1}},
+	OpAwait:       {"OpAwait", []int{}},
 	// Internal Functions
 	OpGetBuiltin:       {"OpGetBuiltin", []int{1}},
 	OpGetGlobalBuiltin: {"OpGetGlobalBuiltin", []int{2}},
 	// Closures
 	OpClosure:        {"OpClosure", []int{2, 1}},
 	OpGetFree:        {"OpGetFree", []int{1}},
+	OpSetFree:        {"OpSetFree", []int{1}},
+	OpCaptureLocal:   {"OpCaptureLocal", []int{1}},
+	OpCaptureFree:    {"OpCaptureFree", []int{1}},
+	OpIncFree:        {"OpIncFree", []int{1}},
+	OpDecFree:        {"OpDecFree", []int{1}},
+	OpCloseUpvalues:  {"OpCloseUpvalues", []int{1}},
 	OpCurrentClosure: {"OpCurrentClosure", []int{}},
 	// Import/Export
 	OpImport: {"OpImport", []int{2}},
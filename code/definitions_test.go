@@ -16,6 +16,8 @@ func TestMake(t *testing.T) {
 		// Jumps
 		{"OpJump", OpJump, []int{1024}, []byte{byte(OpJump), 4, 0}},
 		{"OpJumpNotTruthy", OpJumpNotTruthy, []int{1024}, []byte{byte(OpJumpNotTruthy), 4, 0}},
+		{"OpJumpNotNull", OpJumpNotNull, []int{1024}, []byte{byte(OpJumpNotNull), 4, 0}},
+		{"OpJumpNull", OpJumpNull, []int{1024}, []byte{byte(OpJumpNull), 4, 0}},
 		// Globals
 		{"OpSetGlobal", OpSetGlobal, []int{255}, []byte{byte(OpSetGlobal), 0, 255}},
 		{"OpGetGlobal", OpGetGlobal, []int{255}, []byte{byte(OpGetGlobal), 0, 255}},
@@ -47,24 +49,55 @@ func TestMake(t *testing.T) {
 		// Prefixes
 		{"OpMinus", OpMinus, []int{}, []byte{byte(OpMinus)}},
 		{"OpBang", OpBang, []int{}, []byte{byte(OpBang)}},
+		// Bitwise
+		{"OpBitAnd", OpBitAnd, []int{}, []byte{byte(OpBitAnd)}},
+		{"OpBitOr", OpBitOr, []int{}, []byte{byte(OpBitOr)}},
+		{"OpBitXor", OpBitXor, []int{}, []byte{byte(OpBitXor)}},
+		{"OpBitNot", OpBitNot, []int{}, []byte{byte(OpBitNot)}},
+		{"OpShiftLeft", OpShiftLeft, []int{}, []byte{byte(OpShiftLeft)}},
+		{"OpShiftRight", OpShiftRight, []int{}, []byte{byte(OpShiftRight)}},
 		// Suffixes
 		{"OpIndex", OpIndex, []int{}, []byte{byte(OpIndex)}},
 		{"OpIndexAssign", OpIndexAssign, []int{}, []byte{byte(OpIndexAssign)}},
 		// Objects
 		{"OpArray", OpArray, []int{255}, []byte{byte(OpArray), 0, 255}},
 		{"OpHash", OpHash, []int{255}, []byte{byte(OpHash), 0, 255}},
+		{"OpSpread", OpSpread, []int{}, []byte{byte(OpSpread)}},
+		{"OpRange", OpRange, []int{1}, []byte{byte(OpRange), 1}},
+		{"OpSlice", OpSlice, []int{}, []byte{byte(OpSlice)}},
+		// Structs
+		{"OpStruct", OpStruct, []int{65534, 3}, []byte{byte(OpStruct), 255, 254, 3}},
+		{"OpMethod", OpMethod, []int{65534}, []byte{byte(OpMethod), 255, 254}},
+		{"OpGetField", OpGetField, []int{65534}, []byte{byte(OpGetField), 255, 254}},
+		{"OpSetField", OpSetField, []int{65534}, []byte{byte(OpSetField), 255, 254}},
 		// Loop control
 		{"OpLoopEnd", OpLoopEnd, []int{}, []byte{byte(OpLoopEnd)}},
+		// Pattern matching
+		{"OpMatchArray", OpMatchArray, []int{255}, []byte{byte(OpMatchArray), 0, 255}},
+		{"OpMatchHash", OpMatchHash, []int{255}, []byte{byte(OpMatchHash), 0, 255}},
+		{"OpMatchVariant", OpMatchVariant, []int{2}, []byte{byte(OpMatchVariant), 0, 2}},
+		{"OpDestructureArray", OpDestructureArray, []int{}, []byte{byte(OpDestructureArray)}},
+		{"OpDestructureHash", OpDestructureHash, []int{}, []byte{byte(OpDestructureHash)}},
 		// Functions
 		{"OpCall", OpCall, []int{255}, []byte{byte(OpCall), 255}},
+		{"OpTailCall", OpTailCall, []int{255}, []byte{byte(OpTailCall), 255}},
 		{"OpReturnValue", OpReturnValue, []int{}, []byte{byte(OpReturnValue)}},
 		{"OpReturn", OpReturn, []int{}, []byte{byte(OpReturn)}},
+		{"OpYield", OpYield, []int{}, []byte{byte(OpYield)}},
+		{"OpSpawn", OpSpawn, []int{255}, []byte{byte(OpSpawn), 255}},
+		{"OpAwait", OpAwait, []int{}, []byte{byte(OpAwait)}},
 		// Internal Functions
 		{"OpGetBuiltin", OpGetBuiltin, []int{255}, []byte{byte(OpGetBuiltin), 255}},
 		{"OpGetGlobalBuiltin", OpGetGlobalBuiltin, []int{65535}, []byte{byte(OpGetGlobalBuiltin), 255, 255}},
 		// Closures
 		{"OpClosure", OpClosure, []int{65534, 255}, []byte{byte(OpClosure), 255, 254, 255}},
 		{"OpGetFree", OpGetFree, []int{255}, []byte{byte(OpGetFree), 255}},
+		{"OpSetFree", OpSetFree, []int{255}, []byte{byte(OpSetFree), 255}},
+		{"OpCaptureLocal", OpCaptureLocal, []int{255}, []byte{byte(OpCaptureLocal), 255}},
+		{"OpCaptureFree", OpCaptureFree, []int{255}, []byte{byte(OpCaptureFree), 255}},
+		{"OpIncFree", OpIncFree, []int{255}, []byte{byte(OpIncFree), 255}},
+		{"OpDecFree", OpDecFree, []int{255}, []byte{byte(OpDecFree), 255}},
+		{"OpCloseUpvalues", OpCloseUpvalues, []int{255}, []byte{byte(OpCloseUpvalues), 255}},
 		{"OpCurrentClosure", OpCurrentClosure, []int{}, []byte{byte(OpCurrentClosure)}},
 		// Import/Export
 		{"OpImport", OpImport, []int{65534}, []byte{byte(OpImport), 255, 254}},
@@ -6,14 +6,16 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 
 	"github.com/senither/zen-lang/code"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/decimal"
 )
 
 const (
 	SERIES_HEADER  = "ZENB"
-	SERIES_VERSION = uint8(1)
+	SERIES_VERSION = uint8(10)
 
 	NULL_CONST = uint8(1)
 
@@ -21,21 +23,27 @@ const (
 	FLOAT_CONST   = uint8(11)
 	BOOLEAN_CONST = uint8(12)
 	STRING_CONST  = uint8(13)
+	BIGINT_CONST  = uint8(14)
+	DECIMAL_CONST = uint8(15)
 
 	COMPILED_FUNCTION_CONST    = uint8(20)
 	COMPILED_ZEN_IMPORT_CONST  = uint8(21)
 	COMPILED_JSON_IMPORT_CONST = uint8(22)
+
+	ENUM_CONST = uint8(30)
 )
 
 type Bytecode struct {
 	Instructions code.Instructions
 	Constants    []objects.Object
+	NumLocals    int
 }
 
 func (c *Compiler) Bytecode() *Bytecode {
 	return &Bytecode{
 		Instructions: c.currentInstructions(),
 		Constants:    c.constants,
+		NumLocals:    c.symbolTable.NumLocals(),
 	}
 }
 
@@ -129,6 +137,7 @@ func (b *Bytecode) Serialize() []byte {
 
 	write(uint32(len(b.Instructions)))
 	buf.Write(b.Instructions)
+	write(uint32(b.NumLocals))
 
 	b.writeSerializedConstants(buf, write, b.Constants)
 
@@ -144,6 +153,16 @@ func (b *Bytecode) writeSerializedConstants(buf *bytes.Buffer, write func(data a
 		case *objects.Integer:
 			buf.WriteByte(INTEGER_CONST)
 			write(v.Value)
+		case *objects.BigInt:
+			buf.WriteByte(BIGINT_CONST)
+			text := v.Value.String()
+			write(uint32(len(text)))
+			buf.WriteString(text)
+		case *objects.Decimal:
+			buf.WriteByte(DECIMAL_CONST)
+			text := v.Value.String()
+			write(uint32(len(text)))
+			buf.WriteString(text)
 		case *objects.Float:
 			buf.WriteByte(FLOAT_CONST)
 			write(math.Float64bits(v.Value))
@@ -164,6 +183,22 @@ func (b *Bytecode) writeSerializedConstants(buf *bytes.Buffer, write func(data a
 			buf.WriteString(v.Name)
 			write(uint32(v.NumLocals))
 			write(uint32(v.NumParameters))
+			write(uint32(v.NumRequiredParameters))
+			if v.Variadic {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+			if v.Generator {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+			if v.Async {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
 			write(uint32(len(v.Instructions())))
 			write(v.Instructions())
 		case *objects.CompiledZenFileImport:
@@ -172,6 +207,7 @@ func (b *Bytecode) writeSerializedConstants(buf *bytes.Buffer, write func(data a
 			buf.WriteString(v.Name)
 			write(uint32(len(v.Instructions())))
 			write(v.Instructions())
+			write(uint32(v.NumLocals))
 			b.writeSerializedConstants(buf, write, v.Constants)
 		case *objects.CompiledJsonFileImport:
 			buf.WriteByte(COMPILED_JSON_IMPORT_CONST)
@@ -179,6 +215,20 @@ func (b *Bytecode) writeSerializedConstants(buf *bytes.Buffer, write func(data a
 			buf.WriteString(v.Name)
 			write(uint32(len(v.Json)))
 			buf.WriteString(v.Json)
+		case *objects.Enum:
+			buf.WriteByte(ENUM_CONST)
+			write(uint32(len(v.Name)))
+			buf.WriteString(v.Name)
+			write(uint32(len(v.Variants)))
+			for _, variant := range v.Variants {
+				write(uint32(len(variant.Name)))
+				buf.WriteString(variant.Name)
+				write(uint32(len(variant.Fields)))
+				for _, field := range variant.Fields {
+					write(uint32(len(field)))
+					buf.WriteString(field)
+				}
+			}
 
 		default:
 			panic(fmt.Sprintf("unsupported constant type: %T", v))
@@ -206,6 +256,11 @@ func Deserialize(data []byte) (*Bytecode, error) {
 		return nil, err
 	}
 
+	var numLocals uint32
+	if err := read(&numLocals); err != nil {
+		return nil, err
+	}
+
 	consts, err := deserializeConstants(r, read)
 	if err != nil {
 		return nil, err
@@ -214,6 +269,7 @@ func Deserialize(data []byte) (*Bytecode, error) {
 	return &Bytecode{
 		Instructions: code.Instructions(ins),
 		Constants:    consts,
+		NumLocals:    int(numLocals),
 	}, nil
 }
 
@@ -239,6 +295,38 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 				return nil, err
 			}
 			consts = append(consts, &objects.Integer{Value: v})
+		case BIGINT_CONST:
+			var textLen uint32
+			if err := read(&textLen); err != nil {
+				return nil, err
+			}
+
+			text := make([]byte, textLen)
+			if _, err := io.ReadFull(r, text); err != nil {
+				return nil, err
+			}
+
+			value, ok := new(big.Int).SetString(string(text), 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid big integer constant: %q", text)
+			}
+			consts = append(consts, &objects.BigInt{Value: value})
+		case DECIMAL_CONST:
+			var textLen uint32
+			if err := read(&textLen); err != nil {
+				return nil, err
+			}
+
+			text := make([]byte, textLen)
+			if _, err := io.ReadFull(r, text); err != nil {
+				return nil, err
+			}
+
+			value, err := decimal.Parse(string(text))
+			if err != nil {
+				return nil, fmt.Errorf("invalid decimal constant: %q", text)
+			}
+			consts = append(consts, &objects.Decimal{Value: value})
 		case FLOAT_CONST:
 			var bits uint64
 			if err := read(&bits); err != nil {
@@ -284,6 +372,26 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 				return nil, err
 			}
 
+			var numRequiredParameters uint32
+			if err := read(&numRequiredParameters); err != nil {
+				return nil, err
+			}
+
+			variadic, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+
+			generator, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+
+			async, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+
 			var insLen uint32
 			if err := read(&insLen); err != nil {
 				return nil, err
@@ -295,10 +403,14 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 			}
 
 			consts = append(consts, &objects.CompiledFunction{
-				Name:               string(nameBytes),
-				NumLocals:          int(numLocals),
-				NumParameters:      int(numParameters),
-				OpcodeInstructions: instructions,
+				Name:                  string(nameBytes),
+				NumLocals:             int(numLocals),
+				NumParameters:         int(numParameters),
+				NumRequiredParameters: int(numRequiredParameters),
+				Variadic:              variadic == 1,
+				Generator:             generator == 1,
+				Async:                 async == 1,
+				OpcodeInstructions:    instructions,
 			})
 		case COMPILED_ZEN_IMPORT_CONST:
 			var nameLen uint32
@@ -321,6 +433,11 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 				return nil, err
 			}
 
+			var numLocals uint32
+			if err := read(&numLocals); err != nil {
+				return nil, err
+			}
+
 			nestedConst, err := deserializeConstants(r, read)
 			if err != nil {
 				return nil, err
@@ -330,6 +447,7 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 				Name:               string(nameBytes),
 				OpcodeInstructions: instructions,
 				Constants:          nestedConst,
+				NumLocals:          int(numLocals),
 			})
 		case COMPILED_JSON_IMPORT_CONST:
 			var nameLen uint32
@@ -356,6 +474,13 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 				Name: string(nameBytes),
 				Json: string(jsonBytes),
 			})
+		case ENUM_CONST:
+			enum, err := deserializeEnum(r, read)
+			if err != nil {
+				return nil, err
+			}
+
+			consts = append(consts, enum)
 
 		default:
 			return nil, fmt.Errorf("unknown constant tag: %d", tag)
@@ -365,6 +490,64 @@ func deserializeConstants(r *bytes.Reader, read func(data any) error) ([]objects
 	return consts, nil
 }
 
+func deserializeEnum(r *bytes.Reader, read func(data any) error) (*objects.Enum, error) {
+	name, err := deserializeString(r, read)
+	if err != nil {
+		return nil, err
+	}
+
+	var variantCount uint32
+	if err := read(&variantCount); err != nil {
+		return nil, err
+	}
+
+	enum := &objects.Enum{Name: name, Variants: make([]*objects.EnumVariant, 0, variantCount)}
+	for i := uint32(0); i < variantCount; i++ {
+		variantName, err := deserializeString(r, read)
+		if err != nil {
+			return nil, err
+		}
+
+		var fieldCount uint32
+		if err := read(&fieldCount); err != nil {
+			return nil, err
+		}
+
+		fields := make([]string, 0, fieldCount)
+		for j := uint32(0); j < fieldCount; j++ {
+			field, err := deserializeString(r, read)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, field)
+		}
+
+		enum.Variants = append(enum.Variants, &objects.EnumVariant{
+			Enum:   enum,
+			Name:   variantName,
+			Fields: fields,
+		})
+	}
+
+	return enum, nil
+}
+
+// deserializeString reads a string prefixed with its length.
+func deserializeString(r *bytes.Reader, read func(data any) error) (string, error) {
+	var strLen uint32
+	if err := read(&strLen); err != nil {
+		return "", err
+	}
+
+	str := make([]byte, strLen)
+	if _, err := io.ReadFull(r, str); err != nil {
+		return "", err
+	}
+
+	return string(str), nil
+}
+
 func verifyBytecodeHeaders(r *bytes.Reader) error {
 	header := make([]byte, 4)
 	if _, err := io.ReadFull(r, header); err != nil {
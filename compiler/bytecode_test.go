@@ -206,7 +206,9 @@ func TestBytecodeSerializeDeserialize(t *testing.T) {
 		input string
 	}{
 		{"integer addition", "1 + 2"},
+		{"big integer literal", "99999999999999999999 + 1"},
 		{"float addition", "2.5 + 3f"},
+		{"decimal literal", "19.99d + 0.010d"},
 		{"string literal", "'Hello, World!'"},
 		{"array literal", "[1, 2, 3]"},
 		{"object literal", "{ 'key': 'value' }"},
@@ -221,6 +223,11 @@ func TestBytecodeSerializeDeserialize(t *testing.T) {
 		{"function with implicit return", "func () { 5 + 10 }"},
 		{"function with one parameter", "func (a) { a + 10 }(5)"},
 		{"function with two parameters", "func (a, b) { a + b }(5, 10)"},
+		{"function with default and rest parameters", "func (a, b = 2, ...c) { a + b }(5)"},
+		{"generator function", "func* () { yield 1; yield 2; }"},
+		{"async function", "async func () { await 1; }"},
+		{"enum declaration", "enum Status { Active, Banned(reason, until) }; Status.Active"},
+		{"top-level block variables", "for (i in [1, 2]) { var v = i * 2; func() { v } }"},
 	}
 
 	for _, tt := range tests {
@@ -247,6 +254,10 @@ func TestBytecodeSerializeDeserialize(t *testing.T) {
 				)
 			}
 
+			if deserialized.NumLocals != bytecode.NumLocals {
+				t.Errorf("NumLocals mismatch. got %d, want %d", deserialized.NumLocals, bytecode.NumLocals)
+			}
+
 			if len(deserialized.Constants) != len(bytecode.Constants) {
 				t.Fatalf("Constants length mismatch. got %d, want %d", len(deserialized.Constants), len(bytecode.Constants))
 			}
@@ -267,6 +278,20 @@ func TestBytecodeSerializeDeserialize(t *testing.T) {
 							i, deserializedConstant.(*objects.Integer).Value, v.Value,
 						)
 					}
+				case *objects.BigInt:
+					if v.Value.Cmp(deserializedConstant.(*objects.BigInt).Value) != 0 {
+						t.Errorf(
+							"BigInt constant %d value mismatch. got %s, want %s",
+							i, deserializedConstant.(*objects.BigInt).Value, v.Value,
+						)
+					}
+				case *objects.Decimal:
+					if v.Inspect() != deserializedConstant.Inspect() {
+						t.Errorf(
+							"Decimal constant %d value mismatch. got %s, want %s",
+							i, deserializedConstant.Inspect(), v.Inspect(),
+						)
+					}
 				case *objects.Float:
 					if v.Value != deserializedConstant.(*objects.Float).Value {
 						t.Errorf(
@@ -295,6 +320,13 @@ func TestBytecodeSerializeDeserialize(t *testing.T) {
 							i, deserializedConstant, v,
 						)
 					}
+				case *objects.Enum:
+					if v.Inspect() != deserializedConstant.Inspect() {
+						t.Errorf(
+							"Enum constant %d value mismatch. got %v, want %v",
+							i, deserializedConstant.Inspect(), v.Inspect(),
+						)
+					}
 
 				default:
 					t.Errorf("Unsupported constant type %T", v)
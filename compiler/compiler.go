@@ -24,6 +24,7 @@ type CompilationScope struct {
 type CompilationLoop struct {
 	startJumpIdx   int
 	breakPositions []int
+	tryDepth       int
 }
 
 type Compiler struct {
@@ -38,6 +39,21 @@ type Compiler struct {
 	loops     []CompilationLoop
 	loopIndex int
 
+	tryDepth int
+
+	optionalJumps []int
+
+	matchIndex       int
+	destructureIndex int
+
+	// receiver is the name of the receiver parameter for the method that is
+	// being compiled, it's marked as a struct instance once it's defined.
+	receiver string
+
+	// enums holds the enums declared in the program by name, so matches on
+	// their variants can be checked for the variants they don't cover.
+	enums map[string]*objects.Enum
+
 	file *objects.FileDescriptorContext
 }
 
@@ -66,6 +82,7 @@ func New(path interface{}) *Compiler {
 		symbolTable: symbolTable,
 		scopes:      []CompilationScope{mainScope},
 		scopeIndex:  0,
+		enums:       map[string]*objects.Enum{},
 		file:        file,
 	}
 }
@@ -122,33 +139,29 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 			c.emit(code.OpPop)
 		}
 	case *ast.VariableStatement:
-		symbol := c.symbolTable.Define(n.Name.Value, n.Mutable)
-
-		if fn, ok := n.Value.(*ast.FunctionLiteral); ok {
-			if fn.Name != nil {
-				return objects.NewError(
-					n.Token, c.file,
-					"cannot use named function literal in variable statement",
-				)
-			}
+		if n.Pattern != nil {
+			return c.compileDestructuringStatement(n)
+		}
 
-			funcLit := &ast.FunctionLiteral{
-				Parameters: fn.Parameters,
-				Body:       fn.Body,
-				Name:       &ast.Identifier{Value: n.Name.Value},
-			}
+		if _, ok := n.Value.(*ast.MethodLiteral); ok {
+			return objects.NewError(
+				n.Token, c.file,
+				"cannot use method literal in variable statement",
+			)
+		}
 
-			err := c.compileFunctionLiteral(funcLit, false)
-			if err != nil {
-				return err
-			}
-		} else {
-			err := c.compileInstruction(n.Value)
-			if err != nil {
-				return err
-			}
+		// The value is compiled after the symbol is defined so functions within
+		// it can refer to the variable, while any other references within the
+		// value still refer to the variable it shadows, if there is one.
+		table := c.symbolTable
+		table.StartDefinition(n.Name.Value)
+		symbol := c.defineSymbol(n.Name.Value, n.Mutable)
 
-			c.setSymbolKind(symbol, n.Value)
+		err := c.compileVariableValue(n, symbol)
+		table.FinishDefinition(n.Name.Value)
+
+		if err != nil {
+			return err
 		}
 
 		c.setSymbol(symbol)
@@ -193,15 +206,21 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 
 		switch n.Operator {
 		case "++":
-			if symbol.Scope == GlobalScope {
+			switch symbol.Scope {
+			case GlobalScope:
 				c.emit(code.OpIncGlobal, symbol.Index)
-			} else {
+			case FreeScope:
+				c.emit(code.OpIncFree, symbol.Index)
+			default:
 				c.emit(code.OpIncLocal, symbol.Index)
 			}
 		case "--":
-			if symbol.Scope == GlobalScope {
+			switch symbol.Scope {
+			case GlobalScope:
 				c.emit(code.OpDecGlobal, symbol.Index)
-			} else {
+			case FreeScope:
+				c.emit(code.OpDecFree, symbol.Index)
+			default:
 				c.emit(code.OpDecLocal, symbol.Index)
 			}
 
@@ -218,12 +237,25 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 			return err
 		}
 
-		err = c.compileInstruction(n.Index)
+		jumpNullPos := -1
+		if n.Optional {
+			jumpNullPos = c.emit(code.OpJumpNull, 9999)
+		}
+
+		if n.Slice {
+			err = c.compileSliceBounds(n)
+		} else {
+			err = c.compileInstruction(n.Index)
+			c.emit(code.OpIndex)
+		}
+
 		if err != nil {
 			return err
 		}
 
-		c.emit(code.OpIndex)
+		if jumpNullPos >= 0 {
+			c.changeInstructionOperandAt(jumpNullPos, len(c.currentInstructions()))
+		}
 	case *ast.ChainExpression:
 		err := c.compileChainExpression(n, false)
 		if err != nil {
@@ -251,10 +283,21 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 		c.emit(code.OpNull)
 	case *ast.StringLiteral:
 		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: n.Value}))
+	case *ast.TemplateLiteral:
+		err := c.compileTemplateLiteral(n)
+		if err != nil {
+			return err
+		}
 	case *ast.IntegerLiteral:
-		c.emit(code.OpConstant, c.addConstant(&objects.Integer{Value: n.Value}))
+		if n.Big != nil {
+			c.emit(code.OpConstant, c.addConstant(&objects.BigInt{Value: n.Big}))
+		} else {
+			c.emit(code.OpConstant, c.addConstant(&objects.Integer{Value: n.Value}))
+		}
 	case *ast.FloatLiteral:
 		c.emit(code.OpConstant, c.addConstant(&objects.Float{Value: n.Value}))
+	case *ast.DecimalLiteral:
+		c.emit(code.OpConstant, c.addConstant(&objects.Decimal{Value: n.Value}))
 	case *ast.BooleanLiteral:
 		if n.Value {
 			c.emit(code.OpTrue)
@@ -280,6 +323,17 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 			return keys[i].String() < keys[j].String()
 		})
 
+		// Spread hashes are added before the other pairs with a null value to
+		// keep the stack in pairs, the VM merges them into the new hash.
+		for _, spread := range n.Spreads {
+			err := c.compileInstruction(spread)
+			if err != nil {
+				return err
+			}
+
+			c.emit(code.OpNull)
+		}
+
 		for _, key := range keys {
 			err := c.compileInstruction(key)
 			if err != nil {
@@ -292,22 +346,72 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 			}
 		}
 
-		c.emit(code.OpHash, len(n.Pairs)*2)
+		c.emit(code.OpHash, (len(n.Spreads)+len(n.Pairs))*2)
+	case *ast.SpreadElement:
+		err := c.compileInstruction(n.Value)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpSpread)
+	case *ast.RangeExpression:
+		err := c.compileInstruction(n.Start)
+		if err != nil {
+			return err
+		}
+
+		err = c.compileInstruction(n.End)
+		if err != nil {
+			return err
+		}
+
+		inclusive := 0
+		if n.Inclusive {
+			inclusive = 1
+		}
+
+		c.emit(code.OpRange, inclusive)
 	case *ast.IfExpression:
 		err := c.compileConditionalIfExpression(n)
 		if err != nil {
 			return err
 		}
+	case *ast.TernaryExpression:
+		err := c.compileTernaryExpression(n)
+		if err != nil {
+			return err
+		}
 	case *ast.FunctionLiteral:
 		err := c.compileFunctionLiteral(n, true)
 		if err != nil {
 			return err
 		}
-	case *ast.ReturnStatement:
-		err := c.compileInstruction(n.ReturnValue)
+	case *ast.MethodLiteral:
+		err := c.compileMethodLiteral(n)
+		if err != nil {
+			return err
+		}
+	case *ast.StructStatement:
+		err := c.compileStructStatement(n)
 		if err != nil {
 			return err
 		}
+	case *ast.EnumStatement:
+		c.compileEnumStatement(n)
+	case *ast.ReturnStatement:
+		// Calls in tail position reuse the frame of the function, unless they're
+		// within a try statement, as the frame is needed to catch their errors.
+		if call, ok := n.ReturnValue.(*ast.CallExpression); ok && c.scopeIndex > 0 && c.tryDepth == 0 {
+			err := c.compileTailCall(call)
+			if err != nil {
+				return err
+			}
+		} else {
+			err := c.compileInstruction(n.ReturnValue)
+			if err != nil {
+				return err
+			}
+		}
 
 		c.emit(code.OpReturnValue)
 	case *ast.CallExpression:
@@ -321,11 +425,33 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 			return err
 		}
 
+	case *ast.SpawnExpression:
+		err := c.compileSpawnExpression(n)
+		if err != nil {
+			return err
+		}
+	case *ast.AwaitExpression:
+		err := c.compileInstruction(n.Value)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpAwait)
 	case *ast.WhileExpression:
 		err := c.compileWhileExpression(n)
 		if err != nil {
 			return err
 		}
+	case *ast.ForExpression:
+		err := c.compileForExpression(n)
+		if err != nil {
+			return err
+		}
+	case *ast.MatchExpression:
+		err := c.compileMatchExpression(n)
+		if err != nil {
+			return err
+		}
 	case *ast.BreakStatement:
 		if c.loopIndex == 0 {
 			return objects.NewError(
@@ -335,6 +461,8 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 		}
 
 		loop := &c.loops[c.loopIndex-1]
+		c.leaveTryBlocks(loop)
+
 		pos := c.emit(code.OpJump, 9999)
 		loop.breakPositions = append(loop.breakPositions, pos)
 	case *ast.ContinueStatement:
@@ -345,10 +473,40 @@ func (c *Compiler) compileInstruction(node ast.Node) *objects.Error {
 			)
 		}
 
-		loop := c.loops[c.loopIndex-1]
+		loop := &c.loops[c.loopIndex-1]
+		c.leaveTryBlocks(loop)
+
 		c.emit(code.OpJump, loop.startJumpIdx)
 
-	// Import & Export statements
+	// Exceptions
+	case *ast.TryStatement:
+		err := c.compileTryStatement(n)
+		if err != nil {
+			return err
+		}
+	case *ast.ThrowStatement:
+		err := c.compileInstruction(n.Value)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpThrow)
+
+	// Generators
+	case *ast.YieldStatement:
+		if n.Value == nil {
+			c.emit(code.OpNull)
+		} else {
+			err := c.compileInstruction(n.Value)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The yield leaves null behind as its result, which is popped so
+		// blocks ending in a yield are balanced like expression statements.
+		c.emit(code.OpYield)
+		c.emit(code.OpPop)
 	case *ast.ImportStatement:
 		err := c.compileImportStatement(n)
 		if err != nil {
@@ -388,10 +546,43 @@ func (c *Compiler) leaveScope() code.Instructions {
 	return instructions
 }
 
+// enterBlock enters a new block scope, symbols defined within the block
+// are only visible to the block and shadow symbols from the outer scopes.
+func (c *Compiler) enterBlock() {
+	c.symbolTable = NewBlockSymbolTable(c.symbolTable)
+}
+
+func (c *Compiler) leaveBlock() {
+	c.symbolTable = c.symbolTable.Outer
+}
+
+// compileBlock compiles the block statement within a block scope of its own.
+func (c *Compiler) compileBlock(block *ast.BlockStatement) *objects.Error {
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	return c.compileInstruction(block)
+}
+
+// defineSymbol defines the symbol in the current scope, if the local slot
+// given to the symbol may already have been captured by a closure, the
+// upvalue is closed so the closure keeps its own binding of the variable.
+func (c *Compiler) defineSymbol(name string, mutable bool) Symbol {
+	reused := c.symbolTable.reusesLocal()
+
+	symbol := c.symbolTable.Define(name, mutable)
+	if reused {
+		c.emit(code.OpCloseUpvalues, symbol.Index)
+	}
+
+	return symbol
+}
+
 func (c *Compiler) enterLoop() int {
 	loop := CompilationLoop{
 		startJumpIdx:   len(c.currentInstructions()),
 		breakPositions: []int{},
+		tryDepth:       c.tryDepth,
 	}
 
 	c.loops = append(c.loops, loop)
@@ -413,6 +604,15 @@ func (c *Compiler) leaveLoop(endIdx int) CompilationLoop {
 	return loop
 }
 
+// leaveTryBlocks removes the error handlers for the try blocks that were
+// entered within the given loop, so jumping out of the loop doesn't leave
+// them behind on the VM.
+func (c *Compiler) leaveTryBlocks(loop *CompilationLoop) {
+	for i := loop.tryDepth; i < c.tryDepth; i++ {
+		c.emit(code.OpEndTry)
+	}
+}
+
 func (c *Compiler) addConstant(obj objects.Object) int {
 	c.constants = append(c.constants, obj)
 	return len(c.constants) - 1
@@ -488,21 +688,34 @@ func (c *Compiler) removeLastPop() {
 
 func (c *Compiler) shouldPopExpression(expr ast.Expression) bool {
 	switch expr := expr.(type) {
-	case *ast.AssignmentExpression, *ast.WhileExpression:
+	case *ast.AssignmentExpression, *ast.WhileExpression, *ast.ForExpression:
 		return false
 	case *ast.ChainExpression:
-		if _, ok := expr.Right.(*ast.AssignmentExpression); ok {
-			return false
-		}
-		return true
+		return !isChainAssignment(expr)
 	case *ast.FunctionLiteral:
 		return expr.Name == nil
+	case *ast.MethodLiteral:
+		return false
 
 	default:
 		return true
 	}
 }
 
+// isChainAssignment reports whether the chain ends in an assignment, nested
+// chains like a.b.c = 1 wrap the assignment in one chain for each property.
+func isChainAssignment(chain *ast.ChainExpression) bool {
+	switch right := chain.Right.(type) {
+	case *ast.AssignmentExpression:
+		return true
+	case *ast.ChainExpression:
+		return isChainAssignment(right)
+
+	default:
+		return false
+	}
+}
+
 func (c *Compiler) compilePrefixExpression(node *ast.PrefixExpression) *objects.Error {
 	err := c.compileInstruction(node.Right)
 	if err != nil {
@@ -514,6 +727,8 @@ func (c *Compiler) compilePrefixExpression(node *ast.PrefixExpression) *objects.
 		c.emit(code.OpBang)
 	case "-":
 		c.emit(code.OpMinus)
+	case "~":
+		c.emit(code.OpBitNot)
 
 	default:
 		return objects.NewError(
@@ -528,6 +743,8 @@ func (c *Compiler) compilePrefixExpression(node *ast.PrefixExpression) *objects.
 
 func (c *Compiler) compileInfixExpression(node *ast.InfixExpression) *objects.Error {
 	switch node.Operator {
+	case "??":
+		return c.compileNullCoalescingExpression(node)
 	case ">", "<", ">=", "<=":
 		if c.isArrayOrHashExpression(node.Left) || c.isArrayOrHashExpression(node.Right) {
 			return objects.NewError(
@@ -568,6 +785,16 @@ func (c *Compiler) compileInfixExpression(node *ast.InfixExpression) *objects.Er
 		c.emit(code.OpAnd)
 	case "||":
 		c.emit(code.OpOr)
+	case "&":
+		c.emit(code.OpBitAnd)
+	case "|":
+		c.emit(code.OpBitOr)
+	case "~":
+		c.emit(code.OpBitXor)
+	case "<<":
+		c.emit(code.OpShiftLeft)
+	case ">>":
+		c.emit(code.OpShiftRight)
 
 	default:
 		return objects.NewError(
@@ -610,6 +837,52 @@ func (c *Compiler) compileInfixExpressionOperands(node *ast.InfixExpression) *ob
 	return nil
 }
 
+func (c *Compiler) compileNullCoalescingExpression(node *ast.InfixExpression) *objects.Error {
+	err := c.compileInstruction(node.Left)
+	if err != nil {
+		return err
+	}
+
+	// The right side is only evaluated when the left side is null,
+	// otherwise we jump past it and keep the left side on the stack.
+	jumpNotNullPos := c.emit(code.OpJumpNotNull, 9999)
+
+	err = c.compileInstruction(node.Right)
+	if err != nil {
+		return err
+	}
+
+	c.changeInstructionOperandAt(jumpNotNullPos, len(c.currentInstructions()))
+
+	return nil
+}
+
+func (c *Compiler) compileTernaryExpression(node *ast.TernaryExpression) *objects.Error {
+	err := c.compileInstruction(node.Condition)
+	if err != nil {
+		return err
+	}
+
+	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
+
+	err = c.compileInstruction(node.Consequence)
+	if err != nil {
+		return err
+	}
+
+	jumpPos := c.emit(code.OpJump, 9999)
+	c.changeInstructionOperandAt(jumpNotTruthyPos, len(c.currentInstructions()))
+
+	err = c.compileInstruction(node.Alternative)
+	if err != nil {
+		return err
+	}
+
+	c.changeInstructionOperandAt(jumpPos, len(c.currentInstructions()))
+
+	return nil
+}
+
 func (c *Compiler) compileConditionalIfExpression(node *ast.IfExpression) *objects.Error {
 	err := c.compileInstruction(node.Condition)
 	if err != nil {
@@ -620,14 +893,12 @@ func (c *Compiler) compileConditionalIfExpression(node *ast.IfExpression) *objec
 	// later on when we know where in the stack to jump to.
 	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
 
-	err = c.compileInstruction(node.Consequence)
+	err = c.compileBlock(node.Consequence)
 	if err != nil {
 		return err
 	}
 
-	if c.lastInstructionIs(code.OpPop) {
-		c.removeLastPop()
-	}
+	c.keepBlockValue(node.Consequence)
 
 	var jumpPos int = -1
 	if !c.lastInstructionIs(code.OpJump) {
@@ -645,14 +916,12 @@ func (c *Compiler) compileConditionalIfExpression(node *ast.IfExpression) *objec
 	} else if node.Alternative == nil {
 		c.emit(code.OpNull)
 	} else {
-		err := c.compileInstruction(node.Alternative)
+		err := c.compileBlock(node.Alternative)
 		if err != nil {
 			return err
 		}
 
-		if c.lastInstructionIs(code.OpPop) {
-			c.removeLastPop()
-		}
+		c.keepBlockValue(node.Alternative)
 	}
 
 	if jumpPos >= 0 {
@@ -666,7 +935,7 @@ func (c *Compiler) compileConditionalIfExpression(node *ast.IfExpression) *objec
 func (c *Compiler) compileFunctionLiteral(node *ast.FunctionLiteral, constructNamed bool) *objects.Error {
 	var symbol *Symbol
 	if constructNamed && node.Name != nil {
-		sym := c.symbolTable.Define(node.Name.Value, false)
+		sym := c.defineSymbol(node.Name.Value, false)
 		symbol = &sym
 	}
 
@@ -676,8 +945,37 @@ func (c *Compiler) compileFunctionLiteral(node *ast.FunctionLiteral, constructNa
 		c.symbolTable.DefineFunctionName(node.Name.Value, false)
 	}
 
-	for _, param := range node.Parameters {
-		c.symbolTable.Define(param.Value, false)
+	// Parameters using patterns are stored in hidden symbols first, so the
+	// arguments are placed in the right locals before being destructured.
+	patternSymbols := map[int]Symbol{}
+	for i, param := range node.Parameters {
+		if ident, ok := param.(*ast.Identifier); ok {
+			c.defineSymbol(ident.Value, false)
+			continue
+		}
+
+		patternSymbols[i] = c.defineSymbol(fmt.Sprintf("@param%d", i), false)
+	}
+
+	if c.receiver != "" {
+		c.symbolTable.UpdateKind(c.receiver, InstanceKind)
+		c.receiver = ""
+	}
+
+	if node.Rest != nil {
+		c.defineSymbol(node.Rest.Value, false)
+	}
+
+	for i, param := range node.Parameters {
+		symbol, ok := patternSymbols[i]
+		if !ok {
+			continue
+		}
+
+		err := c.compileDestructuringPattern(param, func() { c.loadSymbol(symbol) }, false)
+		if err != nil {
+			return err
+		}
 	}
 
 	err := c.compileInstruction(node.Body)
@@ -694,11 +992,11 @@ func (c *Compiler) compileFunctionLiteral(node *ast.FunctionLiteral, constructNa
 	}
 
 	freeSymbols := c.symbolTable.FreeSymbols
-	numLocals := c.symbolTable.numDefinitions
+	numLocals := c.symbolTable.NumLocals()
 	instructions := c.leaveScope()
 
 	for _, sym := range freeSymbols {
-		c.loadSymbol(sym)
+		c.captureSymbol(sym)
 	}
 
 	cfName := ""
@@ -707,10 +1005,14 @@ func (c *Compiler) compileFunctionLiteral(node *ast.FunctionLiteral, constructNa
 	}
 
 	compiledFn := &objects.CompiledFunction{
-		Name:               cfName,
-		OpcodeInstructions: instructions,
-		NumLocals:          numLocals,
-		NumParameters:      len(node.Parameters),
+		Name:                  cfName,
+		OpcodeInstructions:    instructions,
+		NumLocals:             numLocals,
+		NumParameters:         len(node.Parameters),
+		NumRequiredParameters: ast.RequiredParameters(node.Parameters),
+		Variadic:              node.Rest != nil,
+		Generator:             node.Generator,
+		Async:                 node.Async,
 	}
 
 	c.emit(code.OpClosure, c.addConstant(compiledFn), len(freeSymbols))
@@ -722,6 +1024,90 @@ func (c *Compiler) compileFunctionLiteral(node *ast.FunctionLiteral, constructNa
 	return nil
 }
 
+func (c *Compiler) compileStructStatement(node *ast.StructStatement) *objects.Error {
+	if len(node.Fields) > 255 {
+		return objects.NewError(
+			node.Token, c.file,
+			"too many fields in struct %s: %d",
+			node.Name.Value, len(node.Fields),
+		)
+	}
+
+	symbol := c.defineSymbol(node.Name.Value, false)
+
+	for _, field := range node.Fields {
+		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: field.Value}))
+	}
+
+	c.emit(code.OpStruct, c.addConstant(&objects.String{Value: node.Name.Value}), len(node.Fields))
+	c.symbolTable.UpdateKind(symbol.Name, StructKind)
+	c.setSymbol(symbol)
+
+	return nil
+}
+
+// compileEnumStatement builds the enum while compiling, since its variants are
+// known ahead of time, and stores it as a constant in the symbol.
+func (c *Compiler) compileEnumStatement(node *ast.EnumStatement) {
+	enum := &objects.Enum{Name: node.Name.Value, Variants: []*objects.EnumVariant{}}
+
+	for _, variant := range node.Variants {
+		fields := []string{}
+		for _, field := range variant.Fields {
+			fields = append(fields, field.Value)
+		}
+
+		enum.Variants = append(enum.Variants, &objects.EnumVariant{
+			Enum:   enum,
+			Name:   variant.Name.Value,
+			Fields: fields,
+		})
+	}
+
+	symbol := c.defineSymbol(node.Name.Value, false)
+	c.symbolTable.UpdateKind(symbol.Name, EnumKind)
+	c.enums[enum.Name] = enum
+
+	c.emit(code.OpConstant, c.addConstant(enum))
+	c.setSymbol(symbol)
+}
+
+// compileMethodLiteral compiles the method as a function that takes the
+// receiver as its first parameter, and attaches it to the struct.
+func (c *Compiler) compileMethodLiteral(node *ast.MethodLiteral) *objects.Error {
+	symbol, ok := c.symbolTable.Resolve(node.Struct.Value)
+	if !ok {
+		return objects.NewError(
+			node.Struct.Token, c.file,
+			"undefined variable %s",
+			node.Struct.Value,
+		)
+	}
+
+	c.loadSymbol(symbol)
+
+	funcLit := &ast.FunctionLiteral{
+		Token:      node.Function.Token,
+		Name:       &ast.Identifier{Value: node.Struct.Value + "." + node.Function.Name.Value},
+		Parameters: append([]ast.Expression{node.Receiver}, node.Function.Parameters...),
+		Rest:       node.Function.Rest,
+		Body:       node.Function.Body,
+		Generator:  node.Function.Generator,
+		Async:      node.Function.Async,
+	}
+
+	c.receiver = node.Receiver.Value
+
+	err := c.compileFunctionLiteral(funcLit, false)
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpMethod, c.addConstant(&objects.String{Value: node.Function.Name.Value}))
+
+	return nil
+}
+
 func (c *Compiler) compileChainExpression(node *ast.ChainExpression, inner bool) *objects.Error {
 	leftIdent, ok := node.Left.(*ast.Identifier)
 	if !ok {
@@ -735,15 +1121,42 @@ func (c *Compiler) compileChainExpression(node *ast.ChainExpression, inner bool)
 	symbol, symbolExists := c.symbolTable.Resolve(leftIdent.Value)
 	symbolIsBuiltin := ok && (symbol.Scope == BuiltinScope || symbol.Scope == GlobalBuiltinScope)
 
+	// Optional steps in the chain jumps to the end of the outermost chain
+	// expression when their value is null, so the jumps are collected
+	// while compiling the chain and patched once the whole chain is done.
+	if !inner {
+		previousJumps := c.optionalJumps
+		c.optionalJumps = []int{}
+
+		defer func() {
+			for _, pos := range c.optionalJumps {
+				c.changeInstructionOperandAt(pos, len(c.currentInstructions()))
+			}
+
+			c.optionalJumps = previousJumps
+		}()
+	}
+
 	if inner {
 		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: leftIdent.Value}))
 		c.emit(code.OpIndex)
+		c.emitOptionalChainJump(node.Optional)
 	} else if symbolExists && !symbolIsBuiltin {
 		c.loadSymbol(symbol)
+		c.emitOptionalChainJump(node.Optional)
 	}
 
+	// Fields of values that are known to be struct instances are accessed
+	// directly by name, instead of going through the generic index lookup.
+	field := !inner && symbolExists && symbol.Kind == InstanceKind
+
 	switch right := node.Right.(type) {
 	case *ast.Identifier:
+		if field {
+			c.emit(code.OpGetField, c.addConstant(&objects.String{Value: right.Value}))
+			return nil
+		}
+
 		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: right.Value}))
 	case *ast.ChainExpression:
 		return c.compileChainExpression(right, true)
@@ -757,8 +1170,12 @@ func (c *Compiler) compileChainExpression(node *ast.ChainExpression, inner bool)
 			)
 		}
 
-		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: ident.Value}))
-		c.emit(code.OpIndex)
+		c.emitFieldAccess(ident.Value, field)
+		c.emitOptionalChainJump(right.Optional)
+
+		if right.Slice {
+			return c.compileSliceBounds(right)
+		}
 
 		index, ok := right.Index.(*ast.IntegerLiteral)
 		if !ok {
@@ -782,8 +1199,7 @@ func (c *Compiler) compileChainExpression(node *ast.ChainExpression, inner bool)
 
 		if !inner {
 			if symbolExists && !symbolIsBuiltin {
-				c.emit(code.OpConstant, c.addConstant(&objects.String{Value: ident.Value}))
-				c.emit(code.OpIndex)
+				c.emitFieldAccess(ident.Value, field)
 
 				return c.compileFunctionArguments(right)
 			}
@@ -800,7 +1216,7 @@ func (c *Compiler) compileChainExpression(node *ast.ChainExpression, inner bool)
 
 		return c.compileFunctionArguments(right)
 	case *ast.AssignmentExpression:
-		return c.compileChainAssignment(right)
+		return c.compileChainAssignment(right, field)
 
 	default:
 		return objects.NewError(
@@ -814,38 +1230,86 @@ func (c *Compiler) compileChainExpression(node *ast.ChainExpression, inner bool)
 	return nil
 }
 
-func (c *Compiler) compileChainAssignment(assign *ast.AssignmentExpression) *objects.Error {
-	innerAssign, ok := assign.Right.(*ast.AssignmentExpression)
-	if !ok {
-		if index, ok := assign.Left.(*ast.IndexExpression); ok {
-			return c.compileChainIndexAssignment(assign, index)
+// compileSliceBounds compiles the start and end bounds of the slice followed
+// by the slice instruction, bounds that are left out are compiled as null.
+func (c *Compiler) compileSliceBounds(node *ast.IndexExpression) *objects.Error {
+	for _, bound := range []ast.Expression{node.Index, node.End} {
+		if bound == nil {
+			c.emit(code.OpNull)
+			continue
 		}
 
-		return objects.NewError(
-			assign.Token, c.file,
-			"unsupported chain assignment structure: %T",
-			assign.Right,
-		)
+		err := c.compileInstruction(bound)
+		if err != nil {
+			return err
+		}
 	}
 
-	assignmentPath, err := c.retrieveAssignmentKeys(innerAssign.Left, innerAssign)
-	if err != nil {
-		return err
-	}
+	c.emit(code.OpSlice)
 
-	if len(assignmentPath) == 0 {
-		return objects.NewError(
+	return nil
+}
+
+// emitFieldAccess emits the instructions to look up the given key on the value
+// at the top of the stack, using a field lookup if the value is a struct.
+func (c *Compiler) emitFieldAccess(name string, field bool) {
+	if field {
+		c.emit(code.OpGetField, c.addConstant(&objects.String{Value: name}))
+		return
+	}
+
+	c.emit(code.OpConstant, c.addConstant(&objects.String{Value: name}))
+	c.emit(code.OpIndex)
+}
+
+func (c *Compiler) emitOptionalChainJump(optional bool) {
+	if optional {
+		c.optionalJumps = append(c.optionalJumps, c.emit(code.OpJumpNull, 9999))
+	}
+}
+
+func (c *Compiler) compileChainAssignment(assign *ast.AssignmentExpression, field bool) *objects.Error {
+	innerAssign, ok := assign.Right.(*ast.AssignmentExpression)
+	if !ok {
+		if index, ok := assign.Left.(*ast.IndexExpression); ok {
+			return c.compileChainIndexAssignment(assign, index, field)
+		}
+
+		return objects.NewError(
+			assign.Token, c.file,
+			"unsupported chain assignment structure: %T",
+			assign.Right,
+		)
+	}
+
+	assignmentPath, err := c.retrieveAssignmentKeys(innerAssign.Left, innerAssign)
+	if err != nil {
+		return err
+	}
+
+	if len(assignmentPath) == 0 {
+		return objects.NewError(
 			innerAssign.Token, c.file,
 			"empty assignment key path in chain",
 		)
 	}
 
 	for i := 0; i < len(assignmentPath)-1; i++ {
-		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: assignmentPath[i]}))
-		c.emit(code.OpIndex)
+		c.emitFieldAccess(assignmentPath[i], field && i == 0)
 	}
 
 	finalKey := assignmentPath[len(assignmentPath)-1]
+
+	if field && len(assignmentPath) == 1 {
+		err = c.compileInstruction(innerAssign.Right)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpSetField, c.addConstant(&objects.String{Value: finalKey}))
+		return nil
+	}
+
 	c.emit(code.OpConstant, c.addConstant(&objects.String{Value: finalKey}))
 
 	err = c.compileInstruction(innerAssign.Right)
@@ -857,7 +1321,11 @@ func (c *Compiler) compileChainAssignment(assign *ast.AssignmentExpression) *obj
 	return nil
 }
 
-func (c *Compiler) compileChainIndexAssignment(assign *ast.AssignmentExpression, index *ast.IndexExpression) *objects.Error {
+func (c *Compiler) compileChainIndexAssignment(
+	assign *ast.AssignmentExpression,
+	index *ast.IndexExpression,
+	field bool,
+) *objects.Error {
 	propIdent, ok := index.Left.(*ast.Identifier)
 	if !ok {
 		return objects.NewError(
@@ -867,8 +1335,7 @@ func (c *Compiler) compileChainIndexAssignment(assign *ast.AssignmentExpression,
 		)
 	}
 
-	c.emit(code.OpConstant, c.addConstant(&objects.String{Value: propIdent.Value}))
-	c.emit(code.OpIndex)
+	c.emitFieldAccess(propIdent.Value, field)
 
 	err := c.compileInstruction(index.Index)
 	if err != nil {
@@ -971,6 +1438,33 @@ func (c *Compiler) compileAssignmentExpression(node *ast.AssignmentExpression) *
 }
 
 func (c *Compiler) compileFunctionArguments(node *ast.CallExpression) *objects.Error {
+	err := c.compileCallArguments(node)
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpCall, len(node.Arguments))
+
+	return nil
+}
+
+func (c *Compiler) compileTailCall(node *ast.CallExpression) *objects.Error {
+	err := c.compileInstruction(node.Function)
+	if err != nil {
+		return err
+	}
+
+	err = c.compileCallArguments(node)
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpTailCall, len(node.Arguments))
+
+	return nil
+}
+
+func (c *Compiler) compileCallArguments(node *ast.CallExpression) *objects.Error {
 	err := c.validateCallSchemaFromLastLoadedSymbol(node)
 	if err != nil {
 		return err
@@ -982,7 +1476,21 @@ func (c *Compiler) compileFunctionArguments(node *ast.CallExpression) *objects.E
 		}
 	}
 
-	c.emit(code.OpCall, len(node.Arguments))
+	return nil
+}
+
+func (c *Compiler) compileSpawnExpression(node *ast.SpawnExpression) *objects.Error {
+	err := c.compileInstruction(node.Call.Function)
+	if err != nil {
+		return err
+	}
+
+	err = c.compileCallArguments(node.Call)
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpSpawn, len(node.Call.Arguments))
 
 	return nil
 }
@@ -993,6 +1501,14 @@ func (c *Compiler) validateCallSchemaFromLastLoadedSymbol(node *ast.CallExpressi
 		return nil
 	}
 
+	// The number of arguments given isn't known until runtime
+	// when any of the arguments are being spread.
+	for _, arg := range node.Arguments {
+		if _, ok := arg.(*ast.SpreadElement); ok {
+			return nil
+		}
+	}
+
 	requiredArguments := 0
 	for _, argDef := range definition.Schema {
 		if argDef.IsRequired() {
@@ -1051,7 +1567,7 @@ func (c *Compiler) compileWhileExpression(node *ast.WhileExpression) *objects.Er
 
 	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
 
-	err = c.compileInstruction(node.Body)
+	err = c.compileBlock(node.Body)
 	if err != nil {
 		return err
 	}
@@ -1068,6 +1584,571 @@ func (c *Compiler) compileWhileExpression(node *ast.WhileExpression) *objects.Er
 	return nil
 }
 
+func (c *Compiler) compileTemplateLiteral(node *ast.TemplateLiteral) *objects.Error {
+	numParts := 0
+
+	for _, part := range node.Parts {
+		// Empty strings doesn't change the result, so there's no
+		// reason to add them to the constant pool.
+		if str, ok := part.(*ast.StringLiteral); ok && str.Value == "" {
+			continue
+		}
+
+		err := c.compileInstruction(part)
+		if err != nil {
+			return err
+		}
+
+		numParts++
+	}
+
+	c.emit(code.OpConcat, numParts)
+
+	return nil
+}
+
+func (c *Compiler) compileForExpression(node *ast.ForExpression) *objects.Error {
+	err := c.compileInstruction(node.Iterable)
+	if err != nil {
+		return objects.NewEmptyErrorWithParent(err, node.Token, c.file)
+	}
+
+	// The iterator is stored in a symbol that can't be referenced from user
+	// code, so nested loops and assignments within the body can't clobber it.
+	iteratorSymbol := c.defineSymbol(fmt.Sprintf("@iterator%d", c.loopIndex), false)
+
+	c.emit(code.OpIterator)
+	c.setSymbol(iteratorSymbol)
+
+	// The key and value are bound again on every iteration, so they're defined
+	// within the block of the loop body, and any upvalues are closed before the
+	// next iteration binds them to new values.
+	c.enterBlock()
+
+	reused := c.symbolTable.reusesLocal()
+
+	var keySymbol *Symbol
+	if node.Key != nil {
+		symbol := c.symbolTable.Define(node.Key.Value, false)
+		keySymbol = &symbol
+	}
+
+	valueSymbol := c.symbolTable.Define(node.Value.Value, false)
+
+	startJumpIdx := c.enterLoop()
+
+	c.loadSymbol(iteratorSymbol)
+	iterNextPos := c.emit(code.OpIterNext, 9999)
+
+	if reused && keySymbol != nil {
+		c.emit(code.OpCloseUpvalues, keySymbol.Index)
+	} else if reused {
+		c.emit(code.OpCloseUpvalues, valueSymbol.Index)
+	}
+
+	c.setSymbol(valueSymbol)
+	if keySymbol != nil {
+		c.setSymbol(*keySymbol)
+	} else {
+		c.emit(code.OpPop)
+	}
+
+	err = c.compileInstruction(node.Body)
+
+	c.leaveBlock()
+
+	if err != nil {
+		return err
+	}
+
+	c.emit(code.OpJump, startJumpIdx)
+
+	endJumpIdx := len(c.currentInstructions())
+
+	c.emit(code.OpLoopEnd)
+	c.leaveLoop(endJumpIdx)
+
+	c.changeInstructionOperandAt(iterNextPos, endJumpIdx)
+
+	return nil
+}
+
+func (c *Compiler) compileMatchExpression(node *ast.MatchExpression) *objects.Error {
+	err := c.checkEnumCoverage(node)
+	if err != nil {
+		return err
+	}
+
+	err = c.compileInstruction(node.Subject)
+	if err != nil {
+		return objects.NewEmptyErrorWithParent(err, node.Token, c.file)
+	}
+
+	// The subject is stored in a hidden symbol so the patterns are able to load
+	// it, and the values nested within it, as many times as they need to.
+	c.matchIndex++
+	subjectSymbol := c.defineSymbol(fmt.Sprintf("@match%d", c.matchIndex), false)
+	c.setSymbol(subjectSymbol)
+
+	loadSubject := func() { c.loadSymbol(subjectSymbol) }
+
+	endJumps := []int{}
+	for _, arm := range node.Arms {
+		nextArmJumps := []int{}
+
+		err := c.compileMatchArm(arm, loadSubject, &nextArmJumps)
+		if err != nil {
+			return err
+		}
+
+		endJumps = append(endJumps, c.emit(code.OpJump, 9999))
+
+		for _, pos := range nextArmJumps {
+			c.changeInstructionOperandAt(pos, len(c.currentInstructions()))
+		}
+	}
+
+	// None of the arms matched the subject, so we throw an error
+	// that can be caught by the user with a try-catch block.
+	c.emit(code.OpConstant, c.addConstant(&objects.String{Value: "unhandled match value: "}))
+	loadSubject()
+	c.emit(code.OpConcat, 2)
+	c.emit(code.OpThrow)
+
+	for _, pos := range endJumps {
+		c.changeInstructionOperandAt(pos, len(c.currentInstructions()))
+	}
+
+	return nil
+}
+
+// checkEnumCoverage fails matches on the variants of an enum declared in the
+// program that don't cover every variant of the enum.
+func (c *Compiler) checkEnumCoverage(node *ast.MatchExpression) *objects.Error {
+	name, covered, ok := node.EnumCoverage()
+	if !ok {
+		return nil
+	}
+
+	symbol, ok := c.symbolTable.Resolve(name)
+	enum, declared := c.enums[name]
+	if !ok || !declared || symbol.Kind != EnumKind {
+		return nil
+	}
+
+	if err := enum.CheckCoverage(covered); err != nil {
+		return objects.NewError(node.Token, c.file, "%s", err)
+	}
+
+	return nil
+}
+
+// compileMatchArm compiles the patterns, guard and body of the arm within a
+// block scope of its own, the positions of the jumps taken when the arm doesn't
+// match the subject are added to nextArmJumps. The guard is checked for each of
+// the patterns, so the remaining patterns are tried when the guard fails.
+func (c *Compiler) compileMatchArm(arm *ast.MatchArm, loadSubject func(), nextArmJumps *[]int) *objects.Error {
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	// Every pattern binds its identifiers to the same symbols, so the guard and
+	// the body read the bindings of whichever pattern matched the subject.
+	bindings := map[string]Symbol{}
+	symbols := []Symbol{}
+
+	for _, pattern := range arm.Patterns {
+		for _, name := range patternBindings(pattern) {
+			if _, ok := bindings[name]; !ok {
+				bindings[name] = c.defineSymbol(name, false)
+				symbols = append(symbols, bindings[name])
+			}
+		}
+	}
+
+	bodyJumps := []int{}
+
+	for i, pattern := range arm.Patterns {
+		failJumps := []int{}
+
+		// Bindings left over from the patterns that failed before this one are
+		// cleared, since this pattern may not bind all of the same identifiers.
+		if len(arm.Patterns) > 1 {
+			for _, symbol := range symbols {
+				c.emit(code.OpNull)
+				c.setSymbol(symbol)
+			}
+		}
+
+		err := c.compilePattern(pattern, loadSubject, &failJumps, bindings)
+		if err != nil {
+			return err
+		}
+
+		if arm.Guard != nil {
+			err := c.compileInstruction(arm.Guard)
+			if err != nil {
+				return err
+			}
+
+			failJumps = append(failJumps, c.emit(code.OpJumpNotTruthy, 9999))
+		}
+
+		if i == len(arm.Patterns)-1 {
+			*nextArmJumps = append(*nextArmJumps, failJumps...)
+			break
+		}
+
+		bodyJumps = append(bodyJumps, c.emit(code.OpJump, 9999))
+
+		for _, pos := range failJumps {
+			c.changeInstructionOperandAt(pos, len(c.currentInstructions()))
+		}
+	}
+
+	for _, pos := range bodyJumps {
+		c.changeInstructionOperandAt(pos, len(c.currentInstructions()))
+	}
+
+	return c.compileMatchArmBody(arm.Body)
+}
+
+func (c *Compiler) compileMatchArmBody(body *ast.BlockStatement) *objects.Error {
+	err := c.compileInstruction(body)
+	if err != nil {
+		return err
+	}
+
+	c.keepBlockValue(body)
+
+	return nil
+}
+
+// keepBlockValue leaves the value of the last expression in the compiled block
+// on the stack as the value of the block, blocks that don't end with an
+// expression push null instead, so the block always produces one value.
+func (c *Compiler) keepBlockValue(body *ast.BlockStatement) {
+	if c.lastInstructionIs(code.OpPop) {
+		c.removeLastPop()
+		return
+	}
+
+	// Assignments leave their value on the stack already, so it becomes the
+	// value of the block, and blocks that jump away never reach the end, any
+	// other statement results in the block being null.
+	if len(body.Statements) > 0 {
+		switch stmt := body.Statements[len(body.Statements)-1].(type) {
+		case *ast.BreakStatement, *ast.ContinueStatement, *ast.ReturnStatement, *ast.ThrowStatement:
+			return
+		case *ast.ExpressionStatement:
+			switch expr := stmt.Expression.(type) {
+			case *ast.AssignmentExpression:
+				return
+			case *ast.ChainExpression:
+				if isChainAssignment(expr) {
+					return
+				}
+			}
+		}
+	}
+
+	c.emit(code.OpNull)
+}
+
+// compilePattern emits the instructions needed to test the value loaded by the
+// load function against the pattern, binding any identifiers along the way,
+// the positions of the jumps taken when the pattern fails are added to fails.
+func (c *Compiler) compilePattern(
+	pattern ast.Expression,
+	load func(),
+	fails *[]int,
+	bindings map[string]Symbol,
+) *objects.Error {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value == "_" {
+			return nil
+		}
+
+		load()
+		c.setSymbol(bindings[pattern.Value])
+	case *ast.ArrayPattern:
+		load()
+		c.emit(code.OpMatchArray, len(pattern.Elements))
+		*fails = append(*fails, c.emit(code.OpJumpNotTruthy, 9999))
+
+		for i, element := range pattern.Elements {
+			loadElement := func() {
+				load()
+				c.emit(code.OpConstant, c.addConstant(&objects.Integer{Value: int64(i)}))
+				c.emit(code.OpIndex)
+			}
+
+			err := c.compilePattern(element, loadElement, fails, bindings)
+			if err != nil {
+				return err
+			}
+		}
+	case *ast.HashPattern:
+		load()
+		for _, key := range pattern.Keys {
+			c.emit(code.OpConstant, c.addConstant(&objects.String{Value: key.Value}))
+		}
+
+		c.emit(code.OpMatchHash, len(pattern.Keys))
+		*fails = append(*fails, c.emit(code.OpJumpNotTruthy, 9999))
+
+		for i, key := range pattern.Keys {
+			loadValue := func() {
+				load()
+				c.emit(code.OpConstant, c.addConstant(&objects.String{Value: key.Value}))
+				c.emit(code.OpIndex)
+			}
+
+			err := c.compilePattern(pattern.Values[i], loadValue, fails, bindings)
+			if err != nil {
+				return err
+			}
+		}
+	case *ast.VariantPattern:
+		load()
+
+		err := c.compileInstruction(pattern.Enum)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpConstant, c.addConstant(&objects.String{Value: pattern.Variant.Value}))
+		c.emit(code.OpIndex)
+		c.emit(code.OpMatchVariant, len(pattern.Patterns))
+		*fails = append(*fails, c.emit(code.OpJumpNotTruthy, 9999))
+
+		for i, element := range pattern.Patterns {
+			loadValue := func() {
+				load()
+				c.emit(code.OpConstant, c.addConstant(&objects.Integer{Value: int64(i)}))
+				c.emit(code.OpIndex)
+			}
+
+			err := c.compilePattern(element, loadValue, fails, bindings)
+			if err != nil {
+				return err
+			}
+		}
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.DecimalLiteral, *ast.StringLiteral,
+		*ast.BooleanLiteral, *ast.NullLiteral, *ast.PrefixExpression:
+		load()
+
+		err := c.compileInstruction(pattern)
+		if err != nil {
+			return err
+		}
+
+		c.emit(code.OpEqual)
+		*fails = append(*fails, c.emit(code.OpJumpNotTruthy, 9999))
+
+	default:
+		return objects.NewError(
+			pattern.GetToken(), c.file,
+			"unsupported match pattern: %s",
+			pattern.String(),
+		)
+	}
+
+	return nil
+}
+
+// patternBindings returns the names of the identifiers bound by the pattern,
+// in the order they appear in the pattern.
+func patternBindings(pattern ast.Expression) []string {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			return []string{pattern.Value}
+		}
+	case *ast.ArrayPattern:
+		return nestedPatternBindings(pattern.Elements)
+	case *ast.HashPattern:
+		return nestedPatternBindings(pattern.Values)
+	case *ast.VariantPattern:
+		return nestedPatternBindings(pattern.Patterns)
+	}
+
+	return nil
+}
+
+func nestedPatternBindings(patterns []ast.Expression) []string {
+	names := []string{}
+	for _, pattern := range patterns {
+		names = append(names, patternBindings(pattern)...)
+	}
+
+	return names
+}
+
+func (c *Compiler) compileVariableValue(n *ast.VariableStatement, symbol Symbol) *objects.Error {
+	fn, ok := n.Value.(*ast.FunctionLiteral)
+	if !ok {
+		err := c.compileInstruction(n.Value)
+		if err != nil {
+			return err
+		}
+
+		c.setSymbolKind(symbol, n.Value)
+
+		return nil
+	}
+
+	if fn.Name != nil {
+		return objects.NewError(
+			n.Token, c.file,
+			"cannot use named function literal in variable statement",
+		)
+	}
+
+	funcLit := &ast.FunctionLiteral{
+		Parameters: fn.Parameters,
+		Rest:       fn.Rest,
+		Body:       fn.Body,
+		Name:       &ast.Identifier{Value: n.Name.Value},
+		Generator:  fn.Generator,
+		Async:      fn.Async,
+	}
+
+	return c.compileFunctionLiteral(funcLit, false)
+}
+
+func (c *Compiler) compileDestructuringStatement(node *ast.VariableStatement) *objects.Error {
+	err := c.compileInstruction(node.Value)
+	if err != nil {
+		return err
+	}
+
+	c.destructureIndex++
+	symbol := c.defineSymbol(fmt.Sprintf("@destructure%d", c.destructureIndex), false)
+	c.setSymbol(symbol)
+
+	return c.compileDestructuringPattern(node.Pattern, func() { c.loadSymbol(symbol) }, node.Mutable)
+}
+
+// compileDestructuringPattern emits the instructions needed to bind the values
+// within the value loaded by the load function to the identifiers found in
+// the pattern, every identifier is defined using the given mutability.
+func (c *Compiler) compileDestructuringPattern(pattern ast.Expression, load func(), mutable bool) *objects.Error {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value == "_" {
+			return nil
+		}
+
+		symbol := c.defineSymbol(pattern.Value, mutable)
+		load()
+		c.setSymbol(symbol)
+	case *ast.DefaultPattern:
+		load()
+		jumpPos := c.emit(code.OpJumpNotNull, 9999)
+
+		err := c.compileInstruction(pattern.Default)
+		if err != nil {
+			return err
+		}
+
+		c.changeInstructionOperandAt(jumpPos, len(c.currentInstructions()))
+
+		if ident, ok := pattern.Pattern.(*ast.Identifier); ok && ident.Value != "_" {
+			c.setSymbol(c.defineSymbol(ident.Value, mutable))
+			return nil
+		}
+
+		c.destructureIndex++
+		symbol := c.defineSymbol(fmt.Sprintf("@destructure%d", c.destructureIndex), false)
+		c.setSymbol(symbol)
+
+		return c.compileDestructuringPattern(pattern.Pattern, func() { c.loadSymbol(symbol) }, mutable)
+	case *ast.ArrayPattern:
+		load()
+		c.emit(code.OpDestructureArray)
+
+		for i, element := range pattern.Elements {
+			loadElement := func() {
+				load()
+				c.emit(code.OpConstant, c.addConstant(&objects.Integer{Value: int64(i)}))
+				c.emit(code.OpIndex)
+			}
+
+			err := c.compileDestructuringPattern(element, loadElement, mutable)
+			if err != nil {
+				return err
+			}
+		}
+	case *ast.HashPattern:
+		load()
+		c.emit(code.OpDestructureHash)
+
+		for i, key := range pattern.Keys {
+			loadValue := func() {
+				load()
+				c.emit(code.OpConstant, c.addConstant(&objects.String{Value: key.Value}))
+				c.emit(code.OpIndex)
+			}
+
+			err := c.compileDestructuringPattern(pattern.Values[i], loadValue, mutable)
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		return objects.NewError(
+			pattern.GetToken(), c.file,
+			"unsupported binding pattern: %s",
+			pattern.String(),
+		)
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileTryStatement(node *ast.TryStatement) *objects.Error {
+	tryPos := c.emit(code.OpTry, 9999)
+
+	c.tryDepth++
+	defer func() { c.tryDepth-- }()
+
+	err := c.compileBlock(node.Block)
+	if err != nil {
+		return err
+	}
+
+	jumpPos := c.emit(code.OpJump, 9999)
+
+	// The VM jumps to the catch block with the caught error on the stack, the
+	// error handler is kept until the OpEndTry instruction so errors thrown
+	// from within the catch block are passed on to the outer handlers.
+	c.changeInstructionOperandAt(tryPos, len(c.currentInstructions()))
+
+	c.enterBlock()
+
+	if node.Parameter != nil {
+		symbol := c.defineSymbol(node.Parameter.Value, false)
+		c.setSymbol(symbol)
+	} else {
+		c.emit(code.OpPop)
+	}
+
+	err = c.compileInstruction(node.Catch)
+
+	c.leaveBlock()
+
+	if err != nil {
+		return err
+	}
+
+	c.changeInstructionOperandAt(jumpPos, len(c.currentInstructions()))
+	c.emit(code.OpEndTry)
+
+	return nil
+}
+
 func (c *Compiler) compileImportStatement(node *ast.ImportStatement) *objects.Error {
 	if c.file == nil {
 		return objects.NewError(
@@ -1141,7 +2222,7 @@ func (c *Compiler) compileImportZenFileContents(node *ast.ImportStatement, conte
 		name = filepath.Base(cleanFilename)
 	}
 
-	symbol := c.symbolTable.Define(name, false)
+	symbol := c.defineSymbol(name, false)
 
 	importCompiler := New(path)
 	err := importCompiler.Compile(program)
@@ -1153,6 +2234,7 @@ func (c *Compiler) compileImportZenFileContents(node *ast.ImportStatement, conte
 		Name:               name,
 		Constants:          importCompiler.constants,
 		OpcodeInstructions: importCompiler.currentInstructions(),
+		NumLocals:          importCompiler.symbolTable.NumLocals(),
 	}))
 
 	c.setSymbol(symbol)
@@ -1207,7 +2289,7 @@ func (c *Compiler) compileImportJSONFileContents(node *ast.ImportStatement, cont
 		name = filepath.Base(cleanFilename)
 	}
 
-	symbol := c.symbolTable.Define(name, false)
+	symbol := c.defineSymbol(name, false)
 
 	c.emit(code.OpImport, c.addConstant(&objects.CompiledJsonFileImport{
 		Name: name,
@@ -1236,7 +2318,7 @@ func (c *Compiler) compileExportStatement(node *ast.ExportStatement) *objects.Er
 			)
 		}
 
-		symbol := c.symbolTable.Define(v.Name.Value, false)
+		symbol := c.defineSymbol(v.Name.Value, false)
 
 		err := c.compileFunctionLiteral(v, false)
 		if err != nil {
@@ -1297,18 +2379,35 @@ func (c *Compiler) loadSymbol(symbol Symbol) {
 }
 
 func (c *Compiler) setSymbol(symbol Symbol) {
-	if symbol.Scope == GlobalScope {
+	switch symbol.Scope {
+	case GlobalScope:
 		c.emit(code.OpSetGlobal, symbol.Index)
-	} else {
+	case FreeScope:
+		c.emit(code.OpSetFree, symbol.Index)
+	default:
 		c.emit(code.OpSetLocal, symbol.Index)
 	}
 }
 
+// captureSymbol pushes a reference to the variable onto the stack so the
+// closure being created shares the variable with the enclosing scope,
+// rather than getting a copy of its current value.
+func (c *Compiler) captureSymbol(symbol Symbol) {
+	switch symbol.Scope {
+	case LocalScope:
+		c.emit(code.OpCaptureLocal, symbol.Index)
+	case FreeScope:
+		c.emit(code.OpCaptureFree, symbol.Index)
+	default:
+		c.loadSymbol(symbol)
+	}
+}
+
 func (c *Compiler) setSymbolKind(symbol Symbol, node ast.Expression) error {
-	switch node.(type) {
-	case *ast.IntegerLiteral, *ast.FloatLiteral:
+	switch node := node.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.DecimalLiteral:
 		return c.symbolTable.UpdateKind(symbol.Name, NumberKind)
-	case *ast.StringLiteral:
+	case *ast.StringLiteral, *ast.TemplateLiteral:
 		return c.symbolTable.UpdateKind(symbol.Name, StringKind)
 	case *ast.BooleanLiteral:
 		return c.symbolTable.UpdateKind(symbol.Name, BooleanKind)
@@ -1316,8 +2415,26 @@ func (c *Compiler) setSymbolKind(symbol Symbol, node ast.Expression) error {
 		return c.symbolTable.UpdateKind(symbol.Name, ArrayKind)
 	case *ast.HashLiteral:
 		return c.symbolTable.UpdateKind(symbol.Name, HashKind)
+	case *ast.CallExpression:
+		return c.setCallResultKind(symbol, node)
 
 	default:
 		return nil
 	}
 }
+
+// setCallResultKind marks the symbol as a struct instance if it's assigned the
+// result of calling a struct, which constructs a new instance of the struct.
+func (c *Compiler) setCallResultKind(symbol Symbol, node *ast.CallExpression) error {
+	ident, ok := node.Function.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	callee, ok := c.symbolTable.Resolve(ident.Value)
+	if !ok || callee.Kind != StructKind {
+		return nil
+	}
+
+	return c.symbolTable.UpdateKind(symbol.Name, InstanceKind)
+}
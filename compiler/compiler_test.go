@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/senither/zen-lang/code"
+	"github.com/senither/zen-lang/objects"
 )
 
 type compilerTestCase struct {
@@ -163,6 +164,111 @@ func BenchmarkIntegerArithmetic(b *testing.B) {
 	})
 }
 
+func TestBitwiseExpressions(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "bitwise and",
+			input:             "12 & 10",
+			expectedConstants: []any{12, 10},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpBitAnd),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "bitwise or",
+			input:             "12 | 10",
+			expectedConstants: []any{12, 10},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpBitOr),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "bitwise xor",
+			input:             "12 ~ 10",
+			expectedConstants: []any{12, 10},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpBitXor),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "bitwise not",
+			input:             "~12",
+			expectedConstants: []any{12},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpBitNot),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "shift left",
+			input:             "1 << 4",
+			expectedConstants: []any{1, 4},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpShiftLeft),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "shift right",
+			input:             "256 >> 4",
+			expectedConstants: []any{256, 4},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpShiftRight),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "compound bitwise assignment",
+			input:             "var mut flags = 1; flags |= 4;",
+			expectedConstants: []any{1, 4},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpConstant, 0),
+				// 0003
+				code.Make(code.OpSetGlobal, 0),
+				// 0006
+				code.Make(code.OpGetGlobal, 0),
+				// 0009
+				code.Make(code.OpConstant, 1),
+				// 0012
+				code.Make(code.OpBitOr),
+				// 0013
+				code.Make(code.OpSetGlobal, 0),
+				// 0016
+				code.Make(code.OpGetGlobal, 0),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkBitwiseExpressions(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"12 & 10",
+		"12 | 10",
+		"12 ~ 10",
+		"~12",
+		"1 << 4",
+		"256 >> 4",
+		"var mut flags = 1; flags |= 4;",
+	})
+}
+
 func TestBooleanExpressions(t *testing.T) {
 	tests := []compilerTestCase{
 		{
@@ -333,6 +439,29 @@ func TestStringExpressions(t *testing.T) {
 				code.Make(code.OpPop),
 			},
 		},
+		{
+			name:              "string interpolation",
+			input:             `"hello ${1} world";`,
+			expectedConstants: []any{"hello ", 1, " world"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpConstant, 2),
+				code.Make(code.OpConcat, 3),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "string interpolation without surrounding text",
+			input:             `"${1}${2}";`,
+			expectedConstants: []any{1, 2},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpConcat, 2),
+				code.Make(code.OpPop),
+			},
+		},
 	}
 
 	runCompilationTests(t, tests)
@@ -343,6 +472,7 @@ func BenchmarkStringExpressions(b *testing.B) {
 		`"hello world";`,
 		`'hello world';`,
 		`"hello" + 'world';`,
+		`"hello ${1} world";`,
 	})
 }
 
@@ -519,6 +649,402 @@ func BenchmarkIndexExpressions(b *testing.B) {
 	})
 }
 
+func TestRangeAndSliceExpressions(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "inclusive range",
+			input:             "1..10",
+			expectedConstants: []any{1, 10},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpRange, 1),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "exclusive range",
+			input:             "1..<10",
+			expectedConstants: []any{1, 10},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpRange, 0),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "slice with both bounds",
+			input:             "[1, 2, 3][1:2]",
+			expectedConstants: []any{1, 2, 3, 1, 2},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpConstant, 2),
+				code.Make(code.OpArray, 3),
+				code.Make(code.OpConstant, 3),
+				code.Make(code.OpConstant, 4),
+				code.Make(code.OpSlice),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "slice without bounds",
+			input:             `"zen"[:]`,
+			expectedConstants: []any{"zen"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpNull),
+				code.Make(code.OpNull),
+				code.Make(code.OpSlice),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "slice with negative end",
+			input:             `"zen"[:-1]`,
+			expectedConstants: []any{"zen", 1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpNull),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpMinus),
+				code.Make(code.OpSlice),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "chained slice",
+			input:             "var test = {}; test.items[1:]",
+			expectedConstants: []any{"items", 1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpHash, 0),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpIndex),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpNull),
+				code.Make(code.OpSlice),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkRangeAndSliceExpressions(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"1..10",
+		"1..<10",
+		"[1, 2, 3][1:2]",
+		`"zen"[:]`,
+		`"zen"[:-1]`,
+		"var test = {}; test.items[1:]",
+	})
+}
+
+func TestStructs(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "struct statement",
+			input:             "struct User { name, age }",
+			expectedConstants: []any{"name", "age", "User"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpStruct, 2, 2),
+				code.Make(code.OpSetGlobal, 0),
+			},
+		},
+		{
+			name:              "struct field access",
+			input:             `struct User { name }; var u = User("Alice"); u.name`,
+			expectedConstants: []any{"name", "User", "Alice", "name"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpStruct, 1, 1),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpConstant, 2),
+				code.Make(code.OpCall, 1),
+				code.Make(code.OpSetGlobal, 1),
+				code.Make(code.OpGetGlobal, 1),
+				code.Make(code.OpGetField, 3),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "struct field assignment",
+			input:             `struct User { name }; var u = User(); u.name = "Bob"`,
+			expectedConstants: []any{"name", "User", "Bob", "name"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpStruct, 1, 1),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpCall, 0),
+				code.Make(code.OpSetGlobal, 1),
+				code.Make(code.OpGetGlobal, 1),
+				code.Make(code.OpConstant, 2),
+				code.Make(code.OpSetField, 3),
+			},
+		},
+		{
+			name:  "struct method",
+			input: "struct User { name }; func (u User) greet() { u.name }",
+			expectedConstants: []any{
+				"name",
+				"User",
+				"name",
+				[]code.Instructions{
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpGetField, 2),
+					code.Make(code.OpReturnValue),
+				},
+				"greet",
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpStruct, 1, 1),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpClosure, 3, 0),
+				code.Make(code.OpMethod, 4),
+			},
+		},
+		{
+			name:              "struct method call",
+			input:             "struct User { name }; var u = User(); u.greet(1)",
+			expectedConstants: []any{"name", "User", "greet", 1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpStruct, 1, 1),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpCall, 0),
+				code.Make(code.OpSetGlobal, 1),
+				code.Make(code.OpGetGlobal, 1),
+				code.Make(code.OpGetField, 2),
+				code.Make(code.OpConstant, 3),
+				code.Make(code.OpCall, 1),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkStructs(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"struct User { name, age }",
+		`struct User { name }; var u = User("Alice"); u.name`,
+		`struct User { name }; var u = User(); u.name = "Bob"`,
+		"struct User { name }; func (u User) greet() { u.name }",
+		"struct User { name }; var u = User(); u.greet(1)",
+	})
+}
+
+func TestEnums(t *testing.T) {
+	status := &objects.Enum{Name: "Status"}
+	status.Variants = []*objects.EnumVariant{
+		{Enum: status, Name: "Active", Fields: []string{}},
+		{Enum: status, Name: "Banned", Fields: []string{"reason"}},
+	}
+
+	tests := []compilerTestCase{
+		{
+			name:              "enum statement",
+			input:             "enum Status { Active, Banned(reason) }",
+			expectedConstants: []any{status},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetGlobal, 0),
+			},
+		},
+		{
+			name:              "enum variant access",
+			input:             "enum Status { Active, Banned(reason) }; Status.Active",
+			expectedConstants: []any{status, "Active"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpIndex),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "enum variant with values",
+			input:             `enum Status { Active, Banned(reason) }; Status.Banned("spam")`,
+			expectedConstants: []any{status, "Banned", "spam"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpIndex),
+				code.Make(code.OpConstant, 2),
+				code.Make(code.OpCall, 1),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkEnums(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"enum Status { Active, Banned(reason) }",
+		"enum Status { Active, Banned(reason) }; Status.Active",
+		`enum Status { Active, Banned(reason) }; Status.Banned("spam")`,
+	})
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:  "generator function",
+			input: "func* () { yield 1; yield 2; }",
+			expectedConstants: []any{
+				1,
+				2,
+				[]code.Instructions{
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpYield),
+					code.Make(code.OpPop),
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpYield),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 2, 0),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:  "generator function with empty yield",
+			input: "func* () { yield; null }",
+			expectedConstants: []any{
+				[]code.Instructions{
+					code.Make(code.OpNull),
+					code.Make(code.OpYield),
+					code.Make(code.OpPop),
+					code.Make(code.OpNull),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 0, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkGenerators(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"func* () { yield 1; yield 2; }",
+		"func* numbers(n) { var mut i = 0; while (i < n) { yield i; i++; } }",
+	})
+}
+
+func TestSpawnExpressions(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:  "spawn function call",
+			input: "var f = func(a) { a }; spawn f(1)",
+			expectedConstants: []any{
+				[]code.Instructions{
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpReturnValue),
+				},
+				1,
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 0, 0),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpSpawn, 1),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:  "spawn function literal",
+			input: "spawn func() { 5 }()",
+			expectedConstants: []any{
+				5,
+				[]code.Instructions{
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 1, 0),
+				code.Make(code.OpSpawn, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkSpawnExpressions(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"var f = func(a) { a }; spawn f(1)",
+		"spawn func() { 5 }()",
+	})
+}
+
+func TestAwaitExpressions(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "await value",
+			input:             "await 1",
+			expectedConstants: []any{1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpAwait),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:  "await inside async function",
+			input: "async func() { await 1 }",
+			expectedConstants: []any{
+				1,
+				[]code.Instructions{
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpAwait),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 1, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkAwaitExpressions(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"await 1",
+		"async func() { await 1 }",
+	})
+}
+
 func TestChainIndexExpressions(t *testing.T) {
 	tests := []compilerTestCase{
 		{
@@ -569,6 +1095,54 @@ func TestChainIndexExpressions(t *testing.T) {
 				code.Make(code.OpPop),
 			},
 		},
+		{
+			name:              "optional chain expression with multiple keys",
+			input:             "var test = {}; test?.another?.key",
+			expectedConstants: []any{"another", "key"},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpHash, 0),
+				// 0003
+				code.Make(code.OpSetGlobal, 0),
+				// 0006
+				code.Make(code.OpGetGlobal, 0),
+				// 0009
+				code.Make(code.OpJumpNull, 23),
+				// 0012
+				code.Make(code.OpConstant, 0),
+				// 0015
+				code.Make(code.OpIndex),
+				// 0016
+				code.Make(code.OpJumpNull, 23),
+				// 0019
+				code.Make(code.OpConstant, 1),
+				// 0022
+				code.Make(code.OpIndex),
+				// 0023
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "optional index expression",
+			input:             "var items = []; items?[0]",
+			expectedConstants: []any{0},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpArray, 0),
+				// 0003
+				code.Make(code.OpSetGlobal, 0),
+				// 0006
+				code.Make(code.OpGetGlobal, 0),
+				// 0009
+				code.Make(code.OpJumpNull, 16),
+				// 0012
+				code.Make(code.OpConstant, 0),
+				// 0015
+				code.Make(code.OpIndex),
+				// 0016
+				code.Make(code.OpPop),
+			},
+		},
 	}
 
 	runCompilationTests(t, tests)
@@ -579,6 +1153,8 @@ func BenchmarkChainIndexExpressions(b *testing.B) {
 		"var test = {}; test.key",
 		"var test = {}; test.another.key",
 		"var obj = {'key': [1, 2, 3]}; obj.key[0]",
+		"var test = {}; test?.another?.key",
+		"var items = []; items?[0]",
 	})
 }
 
@@ -650,7 +1226,6 @@ func TestChainIndexAssignments(t *testing.T) {
 				code.Make(code.OpConstant, 4),
 				code.Make(code.OpConstant, 5),
 				code.Make(code.OpIndexAssign),
-				code.Make(code.OpPop),
 			},
 		},
 	}
@@ -877,9 +1452,51 @@ func TestConditionals(t *testing.T) {
 				code.Make(code.OpConstant, 2),
 				// 0023
 				code.Make(code.OpPop),
-				// 0024
-				code.Make(code.OpConstant, 3),
-				// 0027
+				// 0024
+				code.Make(code.OpConstant, 3),
+				// 0027
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "ternary expression",
+			input:             "true ? 10 : 20; 5;",
+			expectedConstants: []any{10, 20, 5},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpTrue),
+				// 0001
+				code.Make(code.OpJumpNotTruthy, 10),
+				// 0004
+				code.Make(code.OpConstant, 0),
+				// 0007
+				code.Make(code.OpJump, 13),
+				// 0010
+				code.Make(code.OpConstant, 1),
+				// 0013
+				code.Make(code.OpPop),
+				// 0014
+				code.Make(code.OpConstant, 2),
+				// 0017
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "null coalescing expression",
+			input:             "null ?? 10; 5;",
+			expectedConstants: []any{10, 5},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpNull),
+				// 0001
+				code.Make(code.OpJumpNotNull, 7),
+				// 0004
+				code.Make(code.OpConstant, 0),
+				// 0007
+				code.Make(code.OpPop),
+				// 0008
+				code.Make(code.OpConstant, 1),
+				// 0011
 				code.Make(code.OpPop),
 			},
 		},
@@ -893,6 +1510,8 @@ func BenchmarkConditionals(b *testing.B) {
 		"if (true) { 10 }; 5;",
 		"if (true) { 10 } else { 20 }; 5;",
 		"if (false) { 10 } else if (true) { 20 } else { 30 }; 5;",
+		"true ? 10 : 20; 5;",
+		"null ?? 10; 5;",
 	})
 }
 
@@ -1107,6 +1726,105 @@ func BenchmarkVarStatementScopes(b *testing.B) {
 	})
 }
 
+func TestDestructuringVarStatements(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "array destructuring",
+			input:             "var [a, b] = [1, 2];",
+			expectedConstants: []any{1, 2, 0, 1},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpConstant, 0),
+				// 0003
+				code.Make(code.OpConstant, 1),
+				// 0006
+				code.Make(code.OpArray, 2),
+				// 0009
+				code.Make(code.OpSetGlobal, 0),
+				// 0012
+				code.Make(code.OpGetGlobal, 0),
+				// 0015
+				code.Make(code.OpDestructureArray),
+				// 0016
+				code.Make(code.OpGetGlobal, 0),
+				// 0019
+				code.Make(code.OpConstant, 2),
+				// 0022
+				code.Make(code.OpIndex),
+				// 0023
+				code.Make(code.OpSetGlobal, 1),
+				// 0026
+				code.Make(code.OpGetGlobal, 0),
+				// 0029
+				code.Make(code.OpConstant, 3),
+				// 0032
+				code.Make(code.OpIndex),
+				// 0033
+				code.Make(code.OpSetGlobal, 2),
+			},
+		},
+		{
+			name:              "hash destructuring with default value",
+			input:             `var { name = "zen" } = {};`,
+			expectedConstants: []any{"name", "zen"},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpHash, 0),
+				// 0003
+				code.Make(code.OpSetGlobal, 0),
+				// 0006
+				code.Make(code.OpGetGlobal, 0),
+				// 0009
+				code.Make(code.OpDestructureHash),
+				// 0010
+				code.Make(code.OpGetGlobal, 0),
+				// 0013
+				code.Make(code.OpConstant, 0),
+				// 0016
+				code.Make(code.OpIndex),
+				// 0017
+				code.Make(code.OpJumpNotNull, 23),
+				// 0020
+				code.Make(code.OpConstant, 1),
+				// 0023
+				code.Make(code.OpSetGlobal, 1),
+			},
+		},
+		{
+			name:  "function parameter destructuring",
+			input: "func([a]) { a }",
+			expectedConstants: []any{
+				0,
+				[]code.Instructions{
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpDestructureArray),
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpIndex),
+					code.Make(code.OpSetLocal, 1),
+					code.Make(code.OpGetLocal, 1),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 1, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkDestructuringVarStatements(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		`var [a, b] = [1, 2];`,
+		`var { name = "zen" } = {};`,
+		`var mut { user: { name, tags: [first, second = 2] } } = {};`,
+		`func([a]) { a }`,
+	})
+}
+
 func TestVarIncDec(t *testing.T) {
 	tests := []compilerTestCase{
 		{
@@ -1367,6 +2085,105 @@ func BenchmarkFunctions(b *testing.B) {
 	})
 }
 
+func TestFunctionDefaultAndRestParameters(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:  "function with default and rest parameters",
+			input: "func(a, b = 1, ...c) { c }",
+			expectedConstants: []any{
+				1,
+				[]code.Instructions{
+					// 0000
+					code.Make(code.OpGetLocal, 1),
+					// 0002
+					code.Make(code.OpJumpNotNull, 8),
+					// 0005
+					code.Make(code.OpConstant, 0),
+					// 0008
+					code.Make(code.OpSetLocal, 3),
+					// 0010
+					code.Make(code.OpGetLocal, 2),
+					// 0012
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 1, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkFunctionDefaultAndRestParameters(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		`func(a, b = 1, ...c) { c }`,
+		`func greet(name, greeting = "Hello") { greeting + name }; greet("zen");`,
+	})
+}
+
+func TestSpreadExpressions(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "array literal with spread",
+			input:             "[...[1], 2]",
+			expectedConstants: []any{1, 2},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpArray, 1),
+				code.Make(code.OpSpread),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpArray, 2),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "hash literal with spread",
+			input:             `{...{}, "a": 1}`,
+			expectedConstants: []any{"a", 1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpHash, 0),
+				code.Make(code.OpSpread),
+				code.Make(code.OpNull),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpHash, 4),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:  "function call with spread arguments",
+			input: "func() { }(...[1])",
+			expectedConstants: []any{
+				[]code.Instructions{
+					code.Make(code.OpReturn),
+				},
+				1,
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 0, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpArray, 1),
+				code.Make(code.OpSpread),
+				code.Make(code.OpCall, 1),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkSpreadExpressions(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		`[...[1], 2]`,
+		`{...{}, "a": 1}`,
+		`func() { }(...[1])`,
+	})
+}
+
 func TestClosures(t *testing.T) {
 	tests := []compilerTestCase{
 		{
@@ -1386,7 +2203,7 @@ func TestClosures(t *testing.T) {
 					code.Make(code.OpReturnValue),
 				},
 				[]code.Instructions{
-					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCaptureLocal, 0),
 					code.Make(code.OpClosure, 0, 1),
 					code.Make(code.OpReturnValue),
 				},
@@ -1417,13 +2234,13 @@ func TestClosures(t *testing.T) {
 					code.Make(code.OpReturnValue),
 				},
 				[]code.Instructions{
-					code.Make(code.OpGetFree, 0),
-					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCaptureFree, 0),
+					code.Make(code.OpCaptureLocal, 0),
 					code.Make(code.OpClosure, 0, 2),
 					code.Make(code.OpReturnValue),
 				},
 				[]code.Instructions{
-					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCaptureLocal, 0),
 					code.Make(code.OpClosure, 1, 1),
 					code.Make(code.OpReturnValue),
 				},
@@ -1472,23 +2289,155 @@ func TestClosures(t *testing.T) {
 				[]code.Instructions{
 					code.Make(code.OpConstant, 2),
 					code.Make(code.OpSetLocal, 0),
-					code.Make(code.OpGetFree, 0),
-					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCaptureFree, 0),
+					code.Make(code.OpCaptureLocal, 0),
 					code.Make(code.OpClosure, 4, 2),
 					code.Make(code.OpReturnValue),
 				},
 				[]code.Instructions{
 					code.Make(code.OpConstant, 1),
 					code.Make(code.OpSetLocal, 0),
-					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCaptureLocal, 0),
 					code.Make(code.OpClosure, 5, 1),
 					code.Make(code.OpReturnValue),
 				},
 			},
 			expectedInstructions: []code.Instructions{
-				code.Make(code.OpConstant, 0),
-				code.Make(code.OpSetGlobal, 0),
-				code.Make(code.OpClosure, 6, 0),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpClosure, 6, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func TestBlockScopes(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:              "global block variables",
+			input:             "if (true) { var a = 1; }; var b = 2;",
+			expectedConstants: []any{1, 2},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpTrue),
+				code.Make(code.OpJumpNotTruthy, 15),
+				code.Make(code.OpCloseUpvalues, 0),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetLocal, 0),
+				code.Make(code.OpNull),
+				code.Make(code.OpJump, 16),
+				code.Make(code.OpNull),
+				code.Make(code.OpPop),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpSetGlobal, 0),
+			},
+		},
+		{
+			name:  "local block variables",
+			input: "func() { var a = 1; if (true) { var a = 2; a }; var b = 3; }",
+			expectedConstants: []any{
+				1,
+				2,
+				3,
+				[]code.Instructions{
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpSetLocal, 0),
+					code.Make(code.OpTrue),
+					code.Make(code.OpJumpNotTruthy, 21),
+					code.Make(code.OpCloseUpvalues, 1),
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpSetLocal, 1),
+					code.Make(code.OpGetLocal, 1),
+					code.Make(code.OpJump, 22),
+					code.Make(code.OpNull),
+					code.Make(code.OpPop),
+					code.Make(code.OpCloseUpvalues, 1),
+					code.Make(code.OpConstant, 2),
+					code.Make(code.OpSetLocal, 1),
+					code.Make(code.OpReturn),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 3, 0),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkBlockScopes(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"if (true) { var a = 1; }; var b = 2;",
+		"func() { var a = 1; if (true) { var a = 2; a }; var b = 3; }",
+	})
+}
+
+func TestMutableFreeVariables(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name: "assigning and incrementing a free variable",
+			input: `
+				func() {
+					var mut a = 1;
+					func() { a = 2; a++ }
+				}
+			`,
+			expectedConstants: []any{
+				1,
+				2,
+				[]code.Instructions{
+					code.Make(code.OpConstant, 1),
+					code.Make(code.OpSetFree, 0),
+					code.Make(code.OpGetFree, 0),
+					code.Make(code.OpIncFree, 0),
+					code.Make(code.OpReturnValue),
+				},
+				[]code.Instructions{
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpSetLocal, 0),
+					code.Make(code.OpCaptureLocal, 0),
+					code.Make(code.OpClosure, 2, 1),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 3, 0),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name: "decrementing a free variable from a nested closure",
+			input: `
+				func() {
+					var mut a = 1;
+					func() { func() { a-- } }
+				}
+			`,
+			expectedConstants: []any{
+				1,
+				[]code.Instructions{
+					code.Make(code.OpDecFree, 0),
+					code.Make(code.OpReturnValue),
+				},
+				[]code.Instructions{
+					code.Make(code.OpCaptureFree, 0),
+					code.Make(code.OpClosure, 1, 1),
+					code.Make(code.OpReturnValue),
+				},
+				[]code.Instructions{
+					code.Make(code.OpConstant, 0),
+					code.Make(code.OpSetLocal, 0),
+					code.Make(code.OpCaptureLocal, 0),
+					code.Make(code.OpClosure, 2, 1),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 3, 0),
 				code.Make(code.OpPop),
 			},
 		},
@@ -1603,17 +2552,17 @@ func TestNamedFunctions(t *testing.T) {
 					code.Make(code.OpReturnValue),
 				},
 				[]code.Instructions{
-					code.Make(code.OpGetFree, 0),
-					code.Make(code.OpGetFree, 1),
-					code.Make(code.OpGetLocal, 0),
-					code.Make(code.OpGetLocal, 1),
+					code.Make(code.OpCaptureFree, 0),
+					code.Make(code.OpCaptureFree, 1),
+					code.Make(code.OpCaptureLocal, 0),
+					code.Make(code.OpCaptureLocal, 1),
 					code.Make(code.OpClosure, 0, 4),
 					code.Make(code.OpSetLocal, 2),
 					code.Make(code.OpReturnValue),
 				},
 				[]code.Instructions{
-					code.Make(code.OpGetLocal, 0),
-					code.Make(code.OpGetLocal, 1),
+					code.Make(code.OpCaptureLocal, 0),
+					code.Make(code.OpCaptureLocal, 1),
 					code.Make(code.OpClosure, 1, 2),
 					code.Make(code.OpSetLocal, 2),
 					code.Make(code.OpReturnValue),
@@ -1742,6 +2691,74 @@ func BenchmarkRecursiveFunctions(b *testing.B) {
 	})
 }
 
+func TestTailCalls(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name:  "returning a call",
+			input: "func f(n) { return f(n); }",
+			expectedConstants: []any{
+				[]code.Instructions{
+					code.Make(code.OpCurrentClosure),
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpTailCall, 1),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 0, 0),
+				code.Make(code.OpSetGlobal, 0),
+			},
+		},
+		{
+			name:  "returning a call inside a try statement",
+			input: "func f(n) { try { return f(n); } catch (e) { } }",
+			expectedConstants: []any{
+				[]code.Instructions{
+					code.Make(code.OpTry, 12),
+					code.Make(code.OpCurrentClosure),
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCall, 1),
+					code.Make(code.OpReturnValue),
+					code.Make(code.OpJump, 16),
+					code.Make(code.OpCloseUpvalues, 1),
+					code.Make(code.OpSetLocal, 1),
+					code.Make(code.OpEndTry),
+					code.Make(code.OpReturn),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 0, 0),
+				code.Make(code.OpSetGlobal, 0),
+			},
+		},
+		{
+			name:  "returning a call as the last expression",
+			input: "func f(n) { f(n) }",
+			expectedConstants: []any{
+				[]code.Instructions{
+					code.Make(code.OpCurrentClosure),
+					code.Make(code.OpGetLocal, 0),
+					code.Make(code.OpCall, 1),
+					code.Make(code.OpReturnValue),
+				},
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpClosure, 0, 0),
+				code.Make(code.OpSetGlobal, 0),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkTailCalls(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"func f(n) { return f(n); }",
+		"func f(n) { try { return f(n); } catch (e) { } }",
+	})
+}
+
 func TestFunctionCalls(t *testing.T) {
 	tests := []compilerTestCase{
 		{
@@ -2104,6 +3121,334 @@ func BenchmarkWhileLoop(b *testing.B) {
 	})
 }
 
+func TestForLoop(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name: "for loop over values",
+			input: `
+				for (v in [1, 2]) { v }
+			`,
+			expectedConstants: []any{1, 2},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpArray, 2),
+				code.Make(code.OpIterator),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpIterNext, 30),
+				code.Make(code.OpCloseUpvalues, 0),
+				code.Make(code.OpSetLocal, 0),
+				code.Make(code.OpPop),
+				code.Make(code.OpGetLocal, 0),
+				code.Make(code.OpPop),
+				code.Make(code.OpJump, 13),
+				code.Make(code.OpLoopEnd),
+			},
+		},
+		{
+			name: "for loop over keys and values",
+			input: `
+				for (k, v in [1]) { k }
+			`,
+			expectedConstants: []any{1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpArray, 1),
+				code.Make(code.OpIterator),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpIterNext, 28),
+				code.Make(code.OpCloseUpvalues, 0),
+				code.Make(code.OpSetLocal, 1),
+				code.Make(code.OpSetLocal, 0),
+				code.Make(code.OpGetLocal, 0),
+				code.Make(code.OpPop),
+				code.Make(code.OpJump, 10),
+				code.Make(code.OpLoopEnd),
+			},
+		},
+		{
+			name: "for loop with break statement",
+			input: `
+				for (v in []) {
+					break;
+				}
+			`,
+			expectedConstants: []any{},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpArray, 0),
+				code.Make(code.OpIterator),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpIterNext, 24),
+				code.Make(code.OpCloseUpvalues, 0),
+				code.Make(code.OpSetLocal, 0),
+				code.Make(code.OpPop),
+				code.Make(code.OpJump, 24),
+				code.Make(code.OpJump, 7),
+				code.Make(code.OpLoopEnd),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkForLoop(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		`
+			for (v in [1, 2]) { v }
+		`,
+		`
+			for (k, v in [1]) { k }
+		`,
+		`
+			for (v in []) {
+				break;
+			}
+		`,
+	})
+}
+
+func TestTryStatements(t *testing.T) {
+	tests := []compilerTestCase{
+		{
+			name: "try statement with catch parameter",
+			input: `
+				try { 1 } catch (e) { 2 }
+			`,
+			expectedConstants: []any{1, 2},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpTry, 10),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpPop),
+				code.Make(code.OpJump, 18),
+				code.Make(code.OpCloseUpvalues, 0),
+				code.Make(code.OpSetLocal, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpPop),
+				code.Make(code.OpEndTry),
+			},
+		},
+		{
+			name: "try statement without catch parameter",
+			input: `
+				try { 1 } catch { }
+			`,
+			expectedConstants: []any{1},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpTry, 10),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpPop),
+				code.Make(code.OpJump, 11),
+				code.Make(code.OpPop),
+				code.Make(code.OpEndTry),
+			},
+		},
+		{
+			name: "break statement within try statement",
+			input: `
+				while (true) {
+					try { break; } catch { }
+				}
+			`,
+			expectedConstants: []any{},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpTrue),
+				code.Make(code.OpJumpNotTruthy, 19),
+				code.Make(code.OpTry, 14),
+				code.Make(code.OpEndTry),
+				code.Make(code.OpJump, 19),
+				code.Make(code.OpJump, 15),
+				code.Make(code.OpPop),
+				code.Make(code.OpEndTry),
+				code.Make(code.OpJump, 0),
+				code.Make(code.OpLoopEnd),
+			},
+		},
+		{
+			name: "throw statement",
+			input: `
+				throw "error";
+			`,
+			expectedConstants: []any{"error"},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpThrow),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkTryStatements(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		`
+			try { 1 } catch (e) { 2 }
+		`,
+		`
+			while (true) {
+				try { break; } catch { }
+			}
+		`,
+		`
+			throw "error";
+		`,
+	})
+}
+
+func TestMatchExpressions(t *testing.T) {
+	s := &objects.Enum{Name: "S"}
+	s.Variants = []*objects.EnumVariant{{Enum: s, Name: "A", Fields: []string{"x"}}}
+
+	tests := []compilerTestCase{
+		{
+			name:              "match expression with literal and wildcard patterns",
+			input:             "match (1) { 1 => 10, _ => 20 }",
+			expectedConstants: []any{1, 1, 10, 20, "unhandled match value: "},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpConstant, 0),
+				// 0003
+				code.Make(code.OpSetGlobal, 0),
+				// 0006
+				code.Make(code.OpGetGlobal, 0),
+				// 0009
+				code.Make(code.OpConstant, 1),
+				// 0012
+				code.Make(code.OpEqual),
+				// 0013
+				code.Make(code.OpJumpNotTruthy, 22),
+				// 0016
+				code.Make(code.OpConstant, 2),
+				// 0019
+				code.Make(code.OpJump, 38),
+				// 0022
+				code.Make(code.OpConstant, 3),
+				// 0025
+				code.Make(code.OpJump, 38),
+				// 0028
+				code.Make(code.OpConstant, 4),
+				// 0031
+				code.Make(code.OpGetGlobal, 0),
+				// 0034
+				code.Make(code.OpConcat, 2),
+				// 0037
+				code.Make(code.OpThrow),
+				// 0038
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "match expression with array pattern",
+			input:             "match ([1]) { [a] => a }",
+			expectedConstants: []any{1, 0, "unhandled match value: "},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpConstant, 0),
+				// 0003
+				code.Make(code.OpArray, 1),
+				// 0006
+				code.Make(code.OpSetGlobal, 0),
+				// 0009
+				code.Make(code.OpCloseUpvalues, 0),
+				// 0011
+				code.Make(code.OpGetGlobal, 0),
+				// 0014
+				code.Make(code.OpMatchArray, 1),
+				// 0017
+				code.Make(code.OpJumpNotTruthy, 34),
+				// 0020
+				code.Make(code.OpGetGlobal, 0),
+				// 0023
+				code.Make(code.OpConstant, 1),
+				// 0026
+				code.Make(code.OpIndex),
+				// 0027
+				code.Make(code.OpSetLocal, 0),
+				// 0029
+				code.Make(code.OpGetLocal, 0),
+				// 0031
+				code.Make(code.OpJump, 44),
+				// 0034
+				code.Make(code.OpConstant, 2),
+				// 0037
+				code.Make(code.OpGetGlobal, 0),
+				// 0040
+				code.Make(code.OpConcat, 2),
+				// 0043
+				code.Make(code.OpThrow),
+				// 0044
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			name:              "match expression with variant pattern",
+			input:             "enum S { A(x) }; match (1) { S.A(x) => x }",
+			expectedConstants: []any{s, 1, "A", 0, "unhandled match value: "},
+			expectedInstructions: []code.Instructions{
+				// 0000
+				code.Make(code.OpConstant, 0),
+				// 0003
+				code.Make(code.OpSetGlobal, 0),
+				// 0006
+				code.Make(code.OpConstant, 1),
+				// 0009
+				code.Make(code.OpSetGlobal, 1),
+				// 0012
+				code.Make(code.OpCloseUpvalues, 0),
+				// 0014
+				code.Make(code.OpGetGlobal, 1),
+				// 0017
+				code.Make(code.OpGetGlobal, 0),
+				// 0020
+				code.Make(code.OpConstant, 2),
+				// 0023
+				code.Make(code.OpIndex),
+				// 0024
+				code.Make(code.OpMatchVariant, 1),
+				// 0027
+				code.Make(code.OpJumpNotTruthy, 44),
+				// 0030
+				code.Make(code.OpGetGlobal, 1),
+				// 0033
+				code.Make(code.OpConstant, 3),
+				// 0036
+				code.Make(code.OpIndex),
+				// 0037
+				code.Make(code.OpSetLocal, 0),
+				// 0039
+				code.Make(code.OpGetLocal, 0),
+				// 0041
+				code.Make(code.OpJump, 54),
+				// 0044
+				code.Make(code.OpConstant, 4),
+				// 0047
+				code.Make(code.OpGetGlobal, 1),
+				// 0050
+				code.Make(code.OpConcat, 2),
+				// 0053
+				code.Make(code.OpThrow),
+				// 0054
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	runCompilationTests(t, tests)
+}
+
+func BenchmarkMatchExpressions(b *testing.B) {
+	runCompilationBenchmarks(b, []string{
+		"match (1) { 1 => 10, _ => 20 }",
+		"match ([1]) { [a] => a }",
+		"match ({}) { {name} if name => name, _ => null }",
+		"enum S { A(x) }; match (1) { S.A(x) => x }",
+	})
+}
+
 func TestAssignmentExpressions(t *testing.T) {
 	tests := []compilerTestCase{
 		{
@@ -128,6 +128,10 @@ func testConstants(t *testing.T, expected []any, actual []objects.Object) error
 			if err != nil {
 				return fmt.Errorf("constant %d - code instructions assertion failed: %s", i, err)
 			}
+		case *objects.Enum:
+			if actual[i].Inspect() != constant.Inspect() {
+				return fmt.Errorf("constant %d - enum assertion failed: got %s, want %s", i, actual[i].Inspect(), constant.Inspect())
+			}
 
 		default:
 			return fmt.Errorf("unknown constant type %T", constant)
@@ -26,6 +26,10 @@ const (
 	BooleanKind SymbolKind = "BOOLEAN"
 	ArrayKind   SymbolKind = "ARRAY"
 	HashKind    SymbolKind = "HASH"
+
+	StructKind   SymbolKind = "STRUCT"
+	InstanceKind SymbolKind = "INSTANCE"
+	EnumKind     SymbolKind = "ENUM"
 )
 
 type Symbol struct {
@@ -47,8 +51,15 @@ type SymbolTable struct {
 
 	store          map[string]Symbol
 	numDefinitions int
+	maxDefinitions int
+	block          bool
 
 	FreeSymbols []Symbol
+
+	// The symbols whose values are being compiled, mapped to the symbol they
+	// shadow, references made directly within the table resolve to the shadowed
+	// symbol, while functions defined within the value can refer to the symbol.
+	pending map[string]*Symbol
 }
 
 func NewSymbolTable() *SymbolTable {
@@ -66,6 +77,39 @@ func NewEnclosedSymbolTable(outer *SymbolTable) *SymbolTable {
 	return s
 }
 
+// NewBlockSymbolTable creates a symbol table for a block within the outer
+// table, symbols defined within the block shadow the outer symbols and are
+// only visible within the block, the locals used by the block are reused by
+// the outer table once the block ends. Blocks outside of functions define
+// locals in the main frame, so they start from the first local slot.
+func NewBlockSymbolTable(outer *SymbolTable) *SymbolTable {
+	s := NewSymbolTable()
+	s.Outer = outer
+	s.block = true
+
+	if outer.Outer != nil {
+		s.numDefinitions = outer.numDefinitions
+	}
+
+	return s
+}
+
+// scope returns the symbol table of the function the symbol table belongs
+// to, or the global symbol table if it isn't within a function.
+func (s *SymbolTable) scope() *SymbolTable {
+	for s.block {
+		s = s.Outer
+	}
+
+	return s
+}
+
+// NumLocals returns the number of local slots needed by the function, or by
+// the main frame for the blocks outside of functions.
+func (s *SymbolTable) NumLocals() int {
+	return s.scope().maxDefinitions
+}
+
 func WriteBuiltinSymbols(table *SymbolTable) {
 	for i, v := range objects.Builtins {
 		table.DefineBuiltin(i, v.Name)
@@ -100,38 +144,83 @@ func (s *SymbolTable) DefineFunctionName(name string, mutable bool) Symbol {
 }
 
 func (s *SymbolTable) Define(name string, mutable bool) Symbol {
-	symbol := Symbol{Name: name, Mutable: mutable, Index: s.numDefinitions, Kind: UnknownKind}
+	symbol := Symbol{Name: name, Mutable: mutable, Kind: UnknownKind}
 
+	// Symbols defined within blocks outside of functions are locals of the
+	// main frame, so closures capture a new binding each time the block runs.
+	scope := s.scope()
 	if s.Outer == nil {
 		symbol.Scope = GlobalScope
+		symbol.Index = scope.numDefinitions
+
+		scope.numDefinitions++
 	} else {
 		symbol.Scope = LocalScope
+		symbol.Index = s.numDefinitions
+
+		s.numDefinitions++
+		scope.maxDefinitions = max(scope.maxDefinitions, s.numDefinitions)
 	}
 
 	s.store[name] = symbol
-	s.numDefinitions++
 
 	return symbol
 }
 
+// reusesLocal checks if the next local defined in the symbol table may be
+// bound more than once, either because the block it's in can run multiple
+// times, or because the slot has been used by a block that has ended.
+func (s *SymbolTable) reusesLocal() bool {
+	scope := s.scope()
+
+	return s.Outer != nil && (s.block || s.numDefinitions < scope.maxDefinitions)
+}
+
+// StartDefinition marks the name as being defined, the symbol defined for it
+// next can only be referenced by functions defined within its value, until
+// FinishDefinition is called, so the value can refer to the symbol it shadows.
+func (s *SymbolTable) StartDefinition(name string) {
+	if s.pending == nil {
+		s.pending = make(map[string]*Symbol)
+	}
+
+	s.pending[name] = nil
+	if symbol, ok := s.store[name]; ok {
+		s.pending[name] = &symbol
+	}
+}
+
+// FinishDefinition makes the symbol defined for the name visible to every
+// reference once its value has been compiled.
+func (s *SymbolTable) FinishDefinition(name string) {
+	delete(s.pending, name)
+}
+
 func (s *SymbolTable) defineFree(original Symbol) Symbol {
+	symbol := s.newFreeSymbol(original)
+	s.store[original.Name] = symbol
+
+	return symbol
+}
+
+func (s *SymbolTable) newFreeSymbol(original Symbol) Symbol {
 	s.FreeSymbols = append(s.FreeSymbols, original)
 
-	symbol := Symbol{
+	return Symbol{
 		Name:    original.Name,
 		Mutable: original.Mutable,
 		Scope:   FreeScope,
 		Index:   len(s.FreeSymbols) - 1,
 		Kind:    original.Kind,
 	}
-
-	s.store[original.Name] = symbol
-
-	return symbol
 }
 
 func (s *SymbolTable) UpdateKind(name string, kind SymbolKind) error {
 	symbol, ok := s.store[name]
+	if !ok && s.block {
+		return s.Outer.UpdateKind(name, kind)
+	}
+
 	if !ok {
 		return fmt.Errorf("symbol %s not found", name)
 	}
@@ -143,9 +232,29 @@ func (s *SymbolTable) UpdateKind(name string, kind SymbolKind) error {
 }
 
 func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
+	return s.resolve(name, true)
+}
+
+// resolve looks up the symbol for the name, direct is false when the lookup
+// comes from a function defined within the table rather than the table itself.
+func (s *SymbolTable) resolve(name string, direct bool) (Symbol, bool) {
 	symbol, ok := s.store[name]
+	if shadowed, pending := s.pending[name]; ok && pending && direct {
+		if shadowed != nil {
+			return *shadowed, true
+		}
+
+		return s.resolveShadowed(name)
+	}
+
+	if !ok && s.block {
+		// Blocks share the frame of the function they're in, so symbols
+		// from the outer tables can be used as is.
+		return s.Outer.resolve(name, direct)
+	}
+
 	if !ok && s.Outer != nil {
-		symbol, ok = s.Outer.Resolve(name)
+		symbol, ok = s.Outer.resolve(name, false)
 		if !ok {
 			return symbol, ok
 		}
@@ -160,3 +269,26 @@ func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
 
 	return symbol, ok
 }
+
+// resolveShadowed resolves the symbol shadowed by a symbol that is being
+// defined, without replacing the symbol being defined in the table.
+func (s *SymbolTable) resolveShadowed(name string) (Symbol, bool) {
+	if s.Outer == nil {
+		return Symbol{}, false
+	}
+
+	if s.block {
+		return s.Outer.resolve(name, true)
+	}
+
+	symbol, ok := s.Outer.resolve(name, false)
+	if ok && symbol.isEligibleForFreeing() {
+		symbol = s.newFreeSymbol(symbol)
+	}
+
+	if ok {
+		s.pending[name] = &symbol
+	}
+
+	return symbol, ok
+}
@@ -451,3 +451,154 @@ func TestUpdateKindFollowsFreeDefinition(t *testing.T) {
 		t.Fatalf("expected initial symbol kind ArrayKind, got %s", sym.Kind)
 	}
 }
+
+func TestDefineInBlockScope(t *testing.T) {
+	global := NewSymbolTable()
+	global.Define("a", false)
+
+	globalBlock := NewBlockSymbolTable(global)
+	globalBlock.Define("a", true)
+	globalBlock.Define("b", false)
+
+	global.Define("c", false)
+
+	expected := map[string]Symbol{
+		"a": {Name: "a", Mutable: true, Scope: LocalScope, Index: 0, Kind: UnknownKind},
+		"b": {Name: "b", Mutable: false, Scope: LocalScope, Index: 1, Kind: UnknownKind},
+	}
+
+	for name, sym := range expected {
+		result, ok := globalBlock.Resolve(name)
+		if !ok || result != sym {
+			t.Errorf("expected %s to resolve to %+v, got %+v", name, sym, result)
+		}
+	}
+
+	if result, _ := global.Resolve("a"); result.Index != 0 || result.Mutable {
+		t.Errorf("expected block symbol to not override the outer symbol, got %+v", result)
+	}
+
+	if _, ok := global.Resolve("b"); ok {
+		t.Errorf("expected block symbol to not be resolvable outside of the block")
+	}
+
+	if result, _ := global.Resolve("c"); result.Scope != GlobalScope || result.Index != 1 {
+		t.Errorf("expected globals defined after the block to be unaffected by its locals, got %+v", result)
+	}
+
+	if global.NumLocals() != 2 {
+		t.Errorf("expected the global block to need 2 locals in the main frame, got %d", global.NumLocals())
+	}
+
+	local := NewEnclosedSymbolTable(global)
+	local.Define("d", false)
+
+	localBlock := NewBlockSymbolTable(local)
+	localBlock.Define("e", false)
+	localBlock.Define("f", false)
+
+	local.Define("g", false)
+
+	localExpected := map[*SymbolTable]Symbol{
+		localBlock: {Name: "f", Mutable: false, Scope: LocalScope, Index: 2, Kind: UnknownKind},
+		local:      {Name: "g", Mutable: false, Scope: LocalScope, Index: 1, Kind: UnknownKind},
+	}
+
+	for table, sym := range localExpected {
+		result, ok := table.Resolve(sym.Name)
+		if !ok || result != sym {
+			t.Errorf("expected %s to resolve to %+v, got %+v", sym.Name, sym, result)
+		}
+	}
+
+	if local.NumLocals() != 3 {
+		t.Errorf("expected function to need 3 locals, got %d", local.NumLocals())
+	}
+
+	if len(local.FreeSymbols) != 0 {
+		t.Errorf("expected block symbols to not be resolved as free symbols, got %+v", local.FreeSymbols)
+	}
+}
+
+func TestResolveFreeFromBlockScope(t *testing.T) {
+	global := NewSymbolTable()
+
+	outer := NewEnclosedSymbolTable(global)
+	outerBlock := NewBlockSymbolTable(outer)
+	outerBlock.Define("a", true)
+
+	inner := NewEnclosedSymbolTable(outerBlock)
+	innerBlock := NewBlockSymbolTable(inner)
+
+	result, ok := innerBlock.Resolve("a")
+	if !ok {
+		t.Fatalf("name a not resolvable")
+	}
+
+	expected := Symbol{Name: "a", Mutable: true, Scope: FreeScope, Index: 0, Kind: UnknownKind}
+	if result != expected {
+		t.Errorf("expected a to resolve to %+v, got %+v", expected, result)
+	}
+
+	if len(inner.FreeSymbols) != 1 || inner.FreeSymbols[0].Scope != LocalScope {
+		t.Errorf("expected the function to capture the block local, got %+v", inner.FreeSymbols)
+	}
+
+	if len(innerBlock.FreeSymbols) != 0 {
+		t.Errorf("expected the block to not have any free symbols, got %+v", innerBlock.FreeSymbols)
+	}
+}
+
+func TestUpdateKindInBlockScope(t *testing.T) {
+	global := NewSymbolTable()
+	global.Define("a", true)
+
+	block := NewBlockSymbolTable(global)
+
+	err := block.UpdateKind("a", NumberKind)
+	if err != nil {
+		t.Fatalf("unexpected error updating kind from block: %s", err)
+	}
+
+	if result, _ := global.Resolve("a"); result.Kind != NumberKind {
+		t.Errorf("expected outer symbol kind to be %s, got %s", NumberKind, result.Kind)
+	}
+}
+
+func TestResolvePendingDefinition(t *testing.T) {
+	global := NewSymbolTable()
+	outerA := global.Define("a", false)
+
+	fn := NewEnclosedSymbolTable(global)
+	block := NewBlockSymbolTable(fn)
+
+	fn.StartDefinition("b")
+	innerB := fn.Define("b", false)
+
+	block.StartDefinition("a")
+	innerA := block.Define("a", false)
+
+	if result, _ := block.Resolve("a"); result != outerA {
+		t.Errorf("expected a to resolve to the shadowed %+v while defined, got %+v", outerA, result)
+	}
+
+	if _, ok := block.Resolve("b"); ok {
+		t.Errorf("expected b to be unresolvable while defined without a shadowed symbol")
+	}
+
+	closure := NewEnclosedSymbolTable(block)
+	if result, _ := closure.Resolve("a"); result.Scope != FreeScope || closure.FreeSymbols[0] != innerA {
+		t.Errorf("expected functions to capture the new %+v, got %+v", innerA, closure.FreeSymbols)
+	}
+
+	block.FinishDefinition("a")
+	fn.FinishDefinition("b")
+
+	if result, _ := block.Resolve("a"); result != innerA {
+		t.Errorf("expected a to resolve to %+v once defined, got %+v", innerA, result)
+	}
+
+	if result, _ := block.Resolve("b"); result != innerB {
+		t.Errorf("expected b to resolve to %+v once defined, got %+v", innerB, result)
+	}
+}
@@ -15,10 +15,17 @@ func (fa *FunctionAdapter) Inspect() string {
 }
 
 func (fa *FunctionAdapter) Call(args ...objects.Object) objects.Object {
-	env := objects.NewEnclosedEnvironment(fa.Fn.Env)
+	env, err := extendFunctionEnv(fa.Fn, args)
+	if err != nil {
+		return err
+	}
+
+	if fa.Fn.Generator {
+		return newGenerator(fa.Fn, env)
+	}
 
-	for paramIdx, param := range fa.Fn.Parameters {
-		env.SetImmutableForcefully(param.Value, args[paramIdx])
+	if fa.Fn.Async {
+		return newAsyncPromise(fa.Fn, env)
 	}
 
 	return objects.UnwrapReturnValue(Eval(fa.Fn.Body, env))
@@ -85,6 +85,7 @@ func TestLenBuiltinFunction(t *testing.T) {
 		{"len empty string", `len("")`, 0},
 		{"len four", `len("four")`, 4},
 		{"len hello world", `len("hello world")`, 11},
+		{"len multi-byte string", `len("héllo 世界")`, 8},
 		{"len null", `len(null)`, 0},
 		{"len int", `len(1)`, &objects.Error{Message: "argument 1 to `len` has invalid type: got INTEGER, want STRING|ARRAY|NULL"}},
 		{"len too many arguments", `len("one", "two")`, &objects.Error{Message: "wrong number of arguments to `len`: got 2, want 1"}},
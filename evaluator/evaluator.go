@@ -1,14 +1,17 @@
 package evaluator
 
 import (
+	"errors"
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/senither/zen-lang/ast"
 	"github.com/senither/zen-lang/lexer"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/scheduler"
 	"github.com/senither/zen-lang/parser"
 )
 
@@ -16,6 +19,9 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 	switch node := node.(type) {
 	// Statements
 	case *ast.Program:
+		scheduler.Lock()
+		defer scheduler.Unlock()
+
 		return evalProgram(node.Statements, env)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
@@ -34,8 +40,52 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 			return val
 		}
 
+		if node.Pattern != nil {
+			return destructurePattern(node.Pattern, val, env, func(ident *ast.Identifier, val objects.Object) objects.Object {
+				return env.Set(ident, ident.Value, val, node.Mutable)
+			})
+		}
+
 		return env.Set(node, node.Name.Value, val, node.Mutable)
 
+	case *ast.StructStatement:
+		return evalStructStatement(node, env)
+	case *ast.EnumStatement:
+		return evalEnumStatement(node, env)
+
+	// Exceptions
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if objects.IsError(val) {
+			return val
+		}
+
+		err := objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", objects.ThrownErrorMessage(val))
+		err.Thrown = val
+
+		return err
+
+	// Generators
+	case *ast.YieldStatement:
+		var val objects.Object = objects.NULL
+		if node.Value != nil {
+			val = Eval(node.Value, env)
+			if objects.IsError(val) {
+				return val
+			}
+		}
+
+		if !env.Yield(val) {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"yield statement not within a generator function",
+			)
+		}
+
+		return objects.NULL
+
 	// Loop controls
 	case *ast.BreakStatement:
 		return &objects.ReturnValue{Value: objects.BREAK}
@@ -47,10 +97,18 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 		return objects.NULL
 	case *ast.StringLiteral:
 		return &objects.String{Value: node.Value}
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(node, env)
 	case *ast.IntegerLiteral:
+		if node.Big != nil {
+			return &objects.BigInt{Value: node.Big}
+		}
+
 		return &objects.Integer{Value: node.Value}
 	case *ast.FloatLiteral:
 		return &objects.Float{Value: node.Value}
+	case *ast.DecimalLiteral:
+		return &objects.Decimal{Value: node.Value}
 	case *ast.BooleanLiteral:
 		return objects.NativeBoolToBooleanObject(node.Value)
 	case *ast.ArrayLiteral:
@@ -77,6 +135,10 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 			return left
 		}
 
+		if node.Operator == "??" {
+			return evalNullCoalescingExpression(node, left, env)
+		}
+
 		right := Eval(node.Right, env)
 		if objects.IsError(right) {
 			return right
@@ -96,12 +158,22 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 			return left
 		}
 
+		if node.Optional && left.Type() == objects.NULL_OBJ {
+			return objects.NULL
+		}
+
+		if node.Slice {
+			return evalSliceExpression(node, left, env)
+		}
+
 		index := Eval(node.Index, env)
 		if objects.IsError(index) {
 			return index
 		}
 
 		return evalIndexExpression(node, left, index, env)
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
 	case *ast.ChainExpression:
 		left := Eval(node.Left, env)
 		if objects.IsError(left) {
@@ -118,8 +190,14 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 		return evalAssignmentExpression(node, node.Left, right, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.TernaryExpression:
+		return evalTernaryExpression(node, env)
 	case *ast.WhileExpression:
 		return evalWhileExpression(node, env)
+	case *ast.ForExpression:
+		return evalForExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 
@@ -131,8 +209,11 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 		function := &objects.Function{
 			Name:       node.Name,
 			Parameters: params,
+			Rest:       node.Rest,
 			Env:        env,
 			Body:       body,
+			Generator:  node.Generator,
+			Async:      node.Async,
 		}
 
 		if function.Name != nil {
@@ -143,6 +224,8 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 		}
 
 		return function
+	case *ast.MethodLiteral:
+		return evalMethodLiteral(node, env)
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
 		if objects.IsError(function) {
@@ -150,6 +233,15 @@ func Eval(node ast.Node, env *objects.Environment) objects.Object {
 		}
 
 		return evalCallExpression(node, function, env)
+	case *ast.SpawnExpression:
+		return evalSpawnExpression(node, env)
+	case *ast.AwaitExpression:
+		value := Eval(node.Value, env)
+		if objects.IsError(value) {
+			return value
+		}
+
+		return objects.Await(value)
 
 	// Import & Export statements
 	case *ast.ImportStatement:
@@ -200,7 +292,7 @@ func evalIfExpression(ie *ast.IfExpression, env *objects.Environment) objects.Ob
 	condition := Eval(ie.Condition, env)
 
 	if objects.IsTruthy(condition) {
-		return Eval(ie.Consequence, env)
+		return Eval(ie.Consequence, objects.NewEnclosedEnvironment(env))
 	}
 
 	if ie.Intermediary != nil {
@@ -208,12 +300,37 @@ func evalIfExpression(ie *ast.IfExpression, env *objects.Environment) objects.Ob
 	}
 
 	if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
+		return Eval(ie.Alternative, objects.NewEnclosedEnvironment(env))
 	}
 
 	return objects.NULL
 }
 
+func evalTernaryExpression(te *ast.TernaryExpression, env *objects.Environment) objects.Object {
+	condition := Eval(te.Condition, env)
+	if objects.IsError(condition) {
+		return condition
+	}
+
+	if objects.IsTruthy(condition) {
+		return Eval(te.Consequence, env)
+	}
+
+	return Eval(te.Alternative, env)
+}
+
+func evalNullCoalescingExpression(
+	node *ast.InfixExpression,
+	left objects.Object,
+	env *objects.Environment,
+) objects.Object {
+	if left.Type() != objects.NULL_OBJ {
+		return left
+	}
+
+	return Eval(node.Right, env)
+}
+
 func evalWhileExpression(we *ast.WhileExpression, env *objects.Environment) objects.Object {
 	for {
 		condition := Eval(we.Condition, env)
@@ -225,7 +342,7 @@ func evalWhileExpression(we *ast.WhileExpression, env *objects.Environment) obje
 			break
 		}
 
-		body := objects.UnwrapReturnValue(Eval(we.Body, env))
+		body := objects.UnwrapReturnValue(Eval(we.Body, objects.NewEnclosedEnvironment(env)))
 		if objects.IsError(body) {
 			return body
 		}
@@ -233,6 +350,352 @@ func evalWhileExpression(we *ast.WhileExpression, env *objects.Environment) obje
 		if body == objects.BREAK {
 			break
 		}
+
+		scheduler.Tick()
+	}
+
+	return objects.NULL
+}
+
+func evalForExpression(fe *ast.ForExpression, env *objects.Environment) objects.Object {
+	iterable := Eval(fe.Iterable, env)
+	if objects.IsError(iterable) {
+		return iterable
+	}
+
+	iterator, err := objects.NewIterator(iterable)
+	if err != nil {
+		return objects.NewError(fe.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+	}
+
+	for {
+		key, value, ok := iterator.Next()
+		if !ok {
+			if iterator.Err() != nil {
+				return unwrapGeneratorError(iterator.Err(), fe, env)
+			}
+
+			break
+		}
+
+		loopEnv := objects.NewEnclosedEnvironment(env)
+		if fe.Key != nil {
+			loopEnv.SetImmutableForcefully(fe.Key.Value, key)
+		}
+
+		loopEnv.SetImmutableForcefully(fe.Value.Value, value)
+
+		body := Eval(fe.Body, loopEnv)
+		if objects.IsError(body) {
+			return body
+		}
+
+		scheduler.Tick()
+
+		if returnValue, ok := body.(*objects.ReturnValue); ok {
+			if returnValue.Value == objects.BREAK {
+				break
+			}
+
+			if returnValue.Value != objects.CONTINUE {
+				return returnValue
+			}
+		}
+	}
+
+	return objects.NULL
+}
+
+func evalMatchExpression(me *ast.MatchExpression, env *objects.Environment) objects.Object {
+	// Matches on enum variants must cover every variant of the enum, which the
+	// compiler checks up front, so it's checked before the subject is used.
+	if name, covered, ok := me.EnumCoverage(); ok {
+		if enum, ok := env.Get(name); ok {
+			if enum, ok := enum.(*objects.Enum); ok {
+				if err := enum.CheckCoverage(covered); err != nil {
+					return objects.NewError(me.Token, env.GetFileDescriptorContext(), "%s", err)
+				}
+			}
+		}
+	}
+
+	subject := Eval(me.Subject, env)
+	if objects.IsError(subject) {
+		return subject
+	}
+
+	for _, arm := range me.Arms {
+		for _, pattern := range arm.Patterns {
+			armEnv := objects.NewEnclosedEnvironment(env)
+			matched, err := matchPattern(pattern, subject, armEnv)
+			if err != nil {
+				return err
+			}
+
+			if !matched {
+				continue
+			}
+
+			if arm.Guard != nil {
+				guard := Eval(arm.Guard, armEnv)
+				if objects.IsError(guard) {
+					return guard
+				}
+
+				if !objects.IsTruthy(guard) {
+					continue
+				}
+			}
+
+			return evalMatchArmBody(arm.Body, armEnv)
+		}
+	}
+
+	return objects.NewError(
+		me.Token, env.GetFileDescriptorContext(),
+		"unhandled match value: %s",
+		objects.StringifyObject(subject),
+	)
+}
+
+func evalMatchArmBody(body *ast.BlockStatement, env *objects.Environment) objects.Object {
+	result := Eval(body, env)
+
+	switch result.(type) {
+	case *objects.ReturnValue, *objects.Error:
+		return result
+	}
+
+	// Only expressions produce a value for the arm, just like in the VM
+	if len(body.Statements) == 0 || result == nil {
+		return objects.NULL
+	}
+
+	if _, ok := body.Statements[len(body.Statements)-1].(*ast.ExpressionStatement); !ok {
+		return objects.NULL
+	}
+
+	return result
+}
+
+func matchPattern(pattern ast.Expression, value objects.Object, env *objects.Environment) (bool, objects.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			env.SetImmutableForcefully(pattern.Value, value)
+		}
+
+		return true, nil
+	case *ast.ArrayPattern:
+		array, ok := value.(*objects.Array)
+		if !ok || len(array.Elements) != len(pattern.Elements) {
+			return false, nil
+		}
+
+		for i, element := range pattern.Elements {
+			matched, err := matchPattern(element, array.Elements[i], env)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case *ast.HashPattern:
+		var hash *objects.Hash
+		switch value := value.(type) {
+		case *objects.Hash:
+			hash = value
+		case *objects.ImmutableHash:
+			hash = &value.Value
+		default:
+			return false, nil
+		}
+
+		for i, key := range pattern.Keys {
+			pair, ok := hash.Pairs[(&objects.String{Value: key.Value}).HashKey()]
+			if !ok {
+				return false, nil
+			}
+
+			matched, err := matchPattern(pattern.Values[i], pair.Value, env)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case *ast.VariantPattern:
+		return matchVariantPattern(pattern, value, env)
+
+	default:
+		literal := Eval(pattern, env)
+		if objects.IsNumber(literal.Type()) && objects.IsNumber(value.Type()) {
+			return objects.UnwrapNumberValue(literal) == objects.UnwrapNumberValue(value), nil
+		}
+
+		return objects.Equals(literal, value) == objects.TRUE, nil
+	}
+}
+
+// matchVariantPattern tests if the value is the same variant as the one in the
+// pattern, if the pattern has any values to match against, the value must
+// also hold the same number of values, which are then matched in order.
+func matchVariantPattern(pattern *ast.VariantPattern, value objects.Object, env *objects.Environment) (bool, objects.Object) {
+	enum := evalIdentifier(pattern.Enum, env)
+	if objects.IsError(enum) {
+		return false, enum
+	}
+
+	definition := evalChainExpression(
+		&ast.ChainExpression{Token: pattern.Token, Left: pattern.Enum, Right: pattern.Variant},
+		enum,
+		pattern.Variant,
+		env,
+	)
+	if objects.IsError(definition) {
+		return false, definition
+	}
+
+	patternVariant, ok := definition.(*objects.EnumVariant)
+	if !ok {
+		return false, objects.NewError(
+			pattern.Token, env.GetFileDescriptorContext(),
+			"cannot match against non-variant type: %s",
+			definition.Type(),
+		)
+	}
+
+	variant, ok := value.(*objects.EnumVariant)
+	if !ok || variant.Enum != patternVariant.Enum || variant.Name != patternVariant.Name {
+		return false, nil
+	}
+
+	if len(pattern.Patterns) == 0 {
+		return true, nil
+	}
+
+	if len(pattern.Patterns) != len(variant.Values) {
+		return false, nil
+	}
+
+	for i, element := range pattern.Patterns {
+		matched, err := matchPattern(element, variant.Values[i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// destructurePattern binds the values found within val to the identifiers in the
+// pattern using the bind function, values that are missing or null will use
+// the default value of the pattern instead if one has been given.
+func destructurePattern(
+	pattern ast.Expression,
+	val objects.Object,
+	env *objects.Environment,
+	bind func(ident *ast.Identifier, val objects.Object) objects.Object,
+) objects.Object {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value == "_" {
+			return val
+		}
+
+		return bind(pattern, val)
+	case *ast.DefaultPattern:
+		if val.Type() == objects.NULL_OBJ {
+			val = Eval(pattern.Default, env)
+			if objects.IsError(val) {
+				return val
+			}
+		}
+
+		return destructurePattern(pattern.Pattern, val, env, bind)
+	case *ast.ArrayPattern:
+		array, ok := val.(*objects.Array)
+		if !ok {
+			return objects.NewError(
+				pattern.Token, env.GetFileDescriptorContext(),
+				"cannot destructure %s as an array",
+				val.Type(),
+			)
+		}
+
+		for i, element := range pattern.Elements {
+			var value objects.Object = objects.NULL
+			if i < len(array.Elements) {
+				value = array.Elements[i]
+			}
+
+			rs := destructurePattern(element, value, env, bind)
+			if objects.IsError(rs) {
+				return rs
+			}
+		}
+
+		return val
+	case *ast.HashPattern:
+		var hash *objects.Hash
+		switch val := val.(type) {
+		case *objects.Hash:
+			hash = val
+		case *objects.ImmutableHash:
+			hash = &val.Value
+		default:
+			return objects.NewError(
+				pattern.Token, env.GetFileDescriptorContext(),
+				"cannot destructure %s as a hash",
+				val.Type(),
+			)
+		}
+
+		for i, key := range pattern.Keys {
+			var value objects.Object = objects.NULL
+			if pair, ok := hash.Pairs[(&objects.String{Value: key.Value}).HashKey()]; ok {
+				value = pair.Value
+			}
+
+			rs := destructurePattern(pattern.Values[i], value, env, bind)
+			if objects.IsError(rs) {
+				return rs
+			}
+		}
+
+		return val
+
+	default:
+		return objects.NewError(
+			pattern.GetToken(), env.GetFileDescriptorContext(),
+			"unsupported binding pattern: %s",
+			pattern.String(),
+		)
+	}
+}
+
+func evalTryStatement(ts *ast.TryStatement, env *objects.Environment) objects.Object {
+	result := Eval(ts.Block, objects.NewEnclosedEnvironment(env))
+
+	err, ok := result.(*objects.Error)
+	if !ok {
+		if _, ok := result.(*objects.ReturnValue); ok {
+			return result
+		}
+
+		return objects.NULL
+	}
+
+	catchEnv := objects.NewEnclosedEnvironment(env)
+	if ts.Parameter != nil {
+		catchEnv.SetImmutableForcefully(ts.Parameter.Value, objects.ErrorToHash(err))
+	}
+
+	result = Eval(ts.Catch, catchEnv)
+
+	switch result.(type) {
+	case *objects.ReturnValue, *objects.Error:
+		return result
 	}
 
 	return objects.NULL
@@ -258,9 +721,51 @@ func evalIdentifier(node *ast.Identifier, env *objects.Environment) objects.Obje
 	)
 }
 
+func evalTemplateLiteral(node *ast.TemplateLiteral, env *objects.Environment) objects.Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts {
+		value := Eval(part, env)
+		if objects.IsError(value) {
+			return value
+		}
+
+		out.WriteString(objects.StringifyObject(value))
+	}
+
+	return &objects.String{Value: out.String()}
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env *objects.Environment) objects.Object {
 	pairs := make(map[objects.HashKey]objects.HashPair)
 
+	// Spread hashes are merged in first, so any keys given explicitly
+	// in the literal will always take priority over the spread ones.
+	for _, spread := range node.Spreads {
+		value := Eval(spread.Value, env)
+		if objects.IsError(value) {
+			return value
+		}
+
+		var hash *objects.Hash
+		switch value := value.(type) {
+		case *objects.Hash:
+			hash = value
+		case *objects.ImmutableHash:
+			hash = &value.Value
+		default:
+			return objects.NewError(
+				spread.Token, env.GetFileDescriptorContext(),
+				"cannot spread non-hash value: %s",
+				value.Type(),
+			)
+		}
+
+		for hashKey, pair := range hash.Pairs {
+			pairs[hashKey] = pair
+		}
+	}
+
 	for key, value := range node.Pairs {
 		keyObj := Eval(key, env)
 		if objects.IsError(keyObj) {
@@ -298,6 +803,8 @@ func evalPrefixExpression(
 		return evalBangOperatorExpression(right)
 	case "-":
 		return evalMinusPrefixOperatorExpression(node, right, env)
+	case "~":
+		return evalBitwiseNotPrefixOperatorExpression(node, right, env)
 
 	default:
 		return objects.NewError(
@@ -327,15 +834,23 @@ func evalMinusPrefixOperatorExpression(
 	right objects.Object,
 	env *objects.Environment,
 ) objects.Object {
-	switch right := right.(type) {
-	case *objects.Integer:
-		return &objects.Integer{Value: -right.Value}
-	case *objects.Float:
-		return &objects.Float{Value: -right.Value}
-
-	default:
+	if !objects.IsNumber(right.Type()) {
 		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "unknown operator: -%s", right.Type())
 	}
+
+	return objects.NegateNumber(right)
+}
+
+func evalBitwiseNotPrefixOperatorExpression(
+	node *ast.PrefixExpression,
+	right objects.Object,
+	env *objects.Environment,
+) objects.Object {
+	if !objects.IsInteger(right) {
+		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "unknown operator: ~%s", right.Type())
+	}
+
+	return objects.BitwiseNot(right)
 }
 
 func evalInfixExpression(node *ast.InfixExpression, left, right objects.Object, env *objects.Environment) objects.Object {
@@ -352,6 +867,8 @@ func evalInfixExpression(node *ast.InfixExpression, left, right objects.Object,
 		} else {
 			return objects.FALSE
 		}
+	case isBitwiseOperator(node.Operator):
+		return evalBitwiseInfixExpression(node, left, right, env)
 	case objects.IsNumber(left.Type()) && objects.IsNumber(right.Type()):
 		return evalNumberInfixExpression(node, left, right, env)
 	case left.Type() == objects.STRING_OBJ && right.Type() == objects.STRING_OBJ:
@@ -402,8 +919,14 @@ func evalSuffixExpression(node *ast.SuffixExpression, left objects.Object, env *
 func evalIncrementExpression(node *ast.SuffixExpression, left objects.Object, env *objects.Environment) objects.Object {
 	switch left := left.(type) {
 	case *objects.Integer:
-		left.Value++
-		return left
+		if left.Value < math.MaxInt64 {
+			left.Value++
+			return left
+		}
+
+		return evalReassignedStepExpression(node, left, "+", env)
+	case *objects.BigInt, *objects.Decimal:
+		return evalReassignedStepExpression(node, left, "+", env)
 	case *objects.Float:
 		left.Value++
 		return left
@@ -420,9 +943,15 @@ func evalIncrementExpression(node *ast.SuffixExpression, left objects.Object, en
 func evalDecrementExpression(node *ast.SuffixExpression, left objects.Object, env *objects.Environment) objects.Object {
 	switch left := left.(type) {
 	case *objects.Integer:
-		left.Value--
-		return left
-	case *objects.Float:
+		if left.Value > math.MinInt64 {
+			left.Value--
+			return left
+		}
+
+		return evalReassignedStepExpression(node, left, "-", env)
+	case *objects.BigInt, *objects.Decimal:
+		return evalReassignedStepExpression(node, left, "-", env)
+	case *objects.Float:
 		left.Value--
 		return left
 
@@ -435,6 +964,28 @@ func evalDecrementExpression(node *ast.SuffixExpression, left objects.Object, en
 	}
 }
 
+// evalReassignedStepExpression increments or decrements integers that overflow,
+// BigInts and decimals, these can't be changed in place like other numbers
+// since they're immutable or the result may be a different type, so the
+// variable is reassigned instead.
+func evalReassignedStepExpression(
+	node *ast.SuffixExpression,
+	left objects.Object,
+	operator string,
+	env *objects.Environment,
+) objects.Object {
+	result, err := objects.ApplyNumberOperator(operator, left, &objects.Integer{Value: 1})
+	if err != nil {
+		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err)
+	}
+
+	if ident, ok := node.Left.(*ast.Identifier); ok {
+		return env.Assign(node, ident.Value, result)
+	}
+
+	return result
+}
+
 func evalIndexExpression(
 	node *ast.IndexExpression,
 	left, index objects.Object,
@@ -443,11 +994,29 @@ func evalIndexExpression(
 	switch {
 	case left.Type() == objects.ARRAY_OBJ && index.Type() == objects.INTEGER_OBJ:
 		return evalArrayIndexExpression(node, left, index, env)
+	case left.Type() == objects.STRING_OBJ && index.Type() == objects.INTEGER_OBJ:
+		return evalStringIndexExpression(node, left, index, env)
 	case left.Type() == objects.HASH_OBJ:
 		return evalHashIndexExpression(node, left, index, env)
 	case left.Type() == objects.IMMUTABLE_HASH_OBJ:
 		iHash := left.(*objects.ImmutableHash)
 		return evalHashIndexExpression(node, &iHash.Value, index, env)
+	case left.Type() == objects.STRUCT_INSTANCE_OBJ && index.Type() == objects.STRING_OBJ:
+		value, err := left.(*objects.StructInstance).Get(index.(*objects.String).Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
+	case left.Type() == objects.ENUM_OBJ && index.Type() == objects.STRING_OBJ:
+		value, err := left.(*objects.Enum).Variant(index.(*objects.String).Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
+	case left.Type() == objects.ENUM_VARIANT_OBJ:
+		return evalEnumVariantIndexExpression(node, left.(*objects.EnumVariant), index, env)
 
 	default:
 		return objects.NewError(
@@ -458,6 +1027,77 @@ func evalIndexExpression(
 	}
 }
 
+func evalEnumVariantIndexExpression(
+	node *ast.IndexExpression,
+	variant *objects.EnumVariant,
+	index objects.Object,
+	env *objects.Environment,
+) objects.Object {
+	switch index := index.(type) {
+	case *objects.String:
+		value, err := variant.Get(index.Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
+	case *objects.Integer:
+		if index.Value < 0 || index.Value >= int64(len(variant.Values)) {
+			return objects.NULL
+		}
+
+		return variant.Values[index.Value]
+
+	default:
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"index operator not supported: %s",
+			variant.Type(),
+		)
+	}
+}
+
+func evalSliceExpression(node *ast.IndexExpression, left objects.Object, env *objects.Environment) objects.Object {
+	bounds := []objects.Object{objects.NULL, objects.NULL}
+
+	for i, bound := range []ast.Expression{node.Index, node.End} {
+		if bound == nil {
+			continue
+		}
+
+		bounds[i] = Eval(bound, env)
+		if objects.IsError(bounds[i]) {
+			return bounds[i]
+		}
+	}
+
+	slice, err := objects.Slice(left, bounds[0], bounds[1])
+	if err != nil {
+		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err)
+	}
+
+	return slice
+}
+
+func evalRangeExpression(node *ast.RangeExpression, env *objects.Environment) objects.Object {
+	start := Eval(node.Start, env)
+	if objects.IsError(start) {
+		return start
+	}
+
+	end := Eval(node.End, env)
+	if objects.IsError(end) {
+		return end
+	}
+
+	rng, err := objects.NewRange(start, end, node.Inclusive)
+	if err != nil {
+		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err)
+	}
+
+	return rng
+}
+
 func evalArrayIndexExpression(
 	node *ast.IndexExpression,
 	left, index objects.Object,
@@ -486,6 +1126,25 @@ func evalArrayIndexExpression(
 	return arrObj.Elements[idx]
 }
 
+func evalStringIndexExpression(
+	node *ast.IndexExpression,
+	left, index objects.Object,
+	env *objects.Environment,
+) objects.Object {
+	idxObj := index.(*objects.Integer)
+
+	char, ok := objects.StringIndex(left.(*objects.String), idxObj.Value)
+	if !ok {
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"string index out of bounds: %d",
+			idxObj.Value,
+		)
+	}
+
+	return char
+}
+
 func evalHashIndexExpression(
 	node *ast.IndexExpression,
 	left, index objects.Object,
@@ -516,9 +1175,39 @@ func evalChainExpression(
 	right ast.Expression,
 	env *objects.Environment,
 ) objects.Object {
+	if node.Optional && left.Type() == objects.NULL_OBJ {
+		return objects.NULL
+	}
+
 	switch left := left.(type) {
 	case *objects.Hash:
 		return evalHashChainExpression(node, left, right, env)
+	case *objects.StructInstance:
+		return evalStructChainExpression(node, left, right, env)
+	case *objects.Enum:
+		return evalEnumChainExpression(node, left, right, env)
+	case *objects.Generator:
+		return evalGeneratorChainExpression(node, left, right, env)
+	case *objects.Task:
+		return evalTaskChainExpression(node, left, right, env)
+	case *objects.Promise:
+		return evalPromiseChainExpression(node, left, right, env)
+	case *objects.EnumVariant:
+		name, ok := right.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, got %s",
+				left.Inspect(), right.TokenLiteral(),
+			)
+		}
+
+		value, err := left.Get(name.Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
 	case *objects.ImmutableHash:
 		switch right := right.(type) {
 		case *ast.AssignmentExpression:
@@ -549,7 +1238,9 @@ func evalHashChainExpression(
 	switch right := right.(type) {
 	case *ast.Identifier:
 		pair, ok := hash.Pairs[(&objects.String{Value: right.Value}).HashKey()]
-		if !ok {
+		if !ok && node.Optional {
+			return objects.NULL
+		} else if !ok {
 			return objects.NewError(
 				node.Token, env.GetFileDescriptorContext(),
 				"invalid chain expression for %s, key not found: %s",
@@ -588,8 +1279,48 @@ func evalHashChainExpression(
 			)
 		}
 
+		// Optional chaining only guards against the hash itself being null, a
+		// missing key reads as null and the rest of the chain is still applied
+		// to it, the same way the VM looks up missing keys.
 		pair, ok := hash.Pairs[(&objects.String{Value: leftInner.Value}).HashKey()]
+		if !ok && node.Optional {
+			pair = objects.HashPair{Value: objects.NULL}
+		} else if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, key not found: %s",
+				hash.Type(), leftInner.Value,
+			)
+		}
+
+		if right.Optional && pair.Value.Type() == objects.NULL_OBJ {
+			return objects.NULL
+		}
+
+		if right.Slice {
+			return evalSliceExpression(right, pair.Value, env)
+		}
+
+		index := Eval(right.Index, env)
+		if objects.IsError(index) {
+			return index
+		}
+
+		return evalIndexExpression(right, pair.Value, index, env)
+	case *ast.ChainExpression:
+		leftInner, ok := right.Left.(*ast.Identifier)
 		if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, expected identifier, got %s",
+				hash.Type(), right.Left.TokenLiteral(),
+			)
+		}
+
+		pair, ok := hash.Pairs[(&objects.String{Value: leftInner.Value}).HashKey()]
+		if !ok && node.Optional {
+			pair = objects.HashPair{Value: objects.NULL}
+		} else if !ok {
 			return objects.NewError(
 				node.Token, env.GetFileDescriptorContext(),
 				"invalid chain expression for %s, key not found: %s",
@@ -597,70 +1328,348 @@ func evalHashChainExpression(
 			)
 		}
 
-		index := Eval(right.Index, env)
-		if objects.IsError(index) {
-			return index
+		return evalChainExpression(right, pair.Value, right.Right, env)
+	case *ast.AssignmentExpression:
+		wrapped, ok := right.Right.(*ast.AssignmentExpression)
+		if !ok {
+			if index, ok := right.Left.(*ast.IndexExpression); ok {
+				return evalChainIndexAssignment(hash, right, index, env)
+			}
+
+			return objects.NewError(
+				right.Token, env.GetFileDescriptorContext(),
+				"unsupported chain assignment structure for %s: %T",
+				hash.Type(), right.Left,
+			)
+		}
+
+		leftKey, ok := wrapped.Left.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				wrapped.Token, env.GetFileDescriptorContext(),
+				"invalid assignment expression for %s, expected identifier, got %s",
+				hash.Type(), wrapped.Left.TokenLiteral(),
+			)
+		}
+
+		obj := Eval(wrapped.Right, env)
+		if objects.IsError(obj) {
+			return obj
+		}
+
+		key := &objects.String{Value: leftKey.Value}
+		hash.Pairs[key.HashKey()] = objects.HashPair{Key: key, Value: obj}
+
+		return obj
+
+	default:
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"invalid chain expression for %s, got %s",
+			hash.Type(), right.TokenLiteral(),
+		)
+	}
+}
+
+func evalStructChainExpression(
+	node *ast.ChainExpression,
+	instance *objects.StructInstance,
+	right ast.Expression,
+	env *objects.Environment,
+) objects.Object {
+	get := func(name *ast.Identifier) objects.Object {
+		value, err := instance.Get(name.Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
+	}
+
+	switch right := right.(type) {
+	case *ast.Identifier:
+		return get(right)
+	case *ast.CallExpression:
+		name, ok := right.Function.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, expected identifier, got %s",
+				instance.Struct.Name, right.Function.TokenLiteral(),
+			)
+		}
+
+		method := get(name)
+		if objects.IsError(method) {
+			return method
+		}
+
+		return evalCallExpression(right, method, env)
+	case *ast.IndexExpression:
+		leftInner, ok := right.Left.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, expected identifier, got %s",
+				instance.Struct.Name, right.Left.TokenLiteral(),
+			)
+		}
+
+		value := get(leftInner)
+		if objects.IsError(value) {
+			return value
+		}
+
+		if right.Optional && value.Type() == objects.NULL_OBJ {
+			return objects.NULL
+		}
+
+		if right.Slice {
+			return evalSliceExpression(right, value, env)
+		}
+
+		index := Eval(right.Index, env)
+		if objects.IsError(index) {
+			return index
+		}
+
+		return evalIndexExpression(right, value, index, env)
+	case *ast.ChainExpression:
+		leftInner, ok := right.Left.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, expected identifier, got %s",
+				instance.Struct.Name, right.Left.TokenLiteral(),
+			)
+		}
+
+		value := get(leftInner)
+		if objects.IsError(value) {
+			return value
+		}
+
+		return evalChainExpression(right, value, right.Right, env)
+	case *ast.AssignmentExpression:
+		wrapped, ok := right.Right.(*ast.AssignmentExpression)
+		if !ok {
+			if index, ok := right.Left.(*ast.IndexExpression); ok {
+				propIdent, ok := index.Left.(*ast.Identifier)
+				if !ok {
+					return objects.NewError(
+						index.Token, env.GetFileDescriptorContext(),
+						"invalid index assignment left side in chain: %s",
+						index.Left.TokenLiteral(),
+					)
+				}
+
+				value := get(propIdent)
+				if objects.IsError(value) {
+					return value
+				}
+
+				return evalChainArrayIndexAssignment(value, right, index, env)
+			}
+
+			return objects.NewError(
+				right.Token, env.GetFileDescriptorContext(),
+				"unsupported chain assignment structure for %s: %T",
+				instance.Struct.Name, right.Left,
+			)
+		}
+
+		leftKey, ok := wrapped.Left.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				wrapped.Token, env.GetFileDescriptorContext(),
+				"invalid assignment expression for %s, expected identifier, got %s",
+				instance.Struct.Name, wrapped.Left.TokenLiteral(),
+			)
+		}
+
+		obj := Eval(wrapped.Right, env)
+		if objects.IsError(obj) {
+			return obj
+		}
+
+		err := instance.Set(leftKey.Value, obj)
+		if err != nil {
+			return objects.NewError(wrapped.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return obj
+
+	default:
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"invalid chain expression for %s, got %s",
+			instance.Struct.Name, right.TokenLiteral(),
+		)
+	}
+}
+
+// methodReceiver is implemented by the objects exposing their methods
+// through Get, like tasks, promises and generators.
+type methodReceiver interface {
+	objects.Object
+	Get(name string) (objects.Object, error)
+}
+
+// evalMethodChainExpression evaluates accessing or calling a method of the
+// receiver, once the called method has been found it's passed to call along
+// with its name, so the receiver can decide how the method is invoked.
+func evalMethodChainExpression(
+	node *ast.ChainExpression,
+	receiver methodReceiver,
+	right ast.Expression,
+	env *objects.Environment,
+	call func(right *ast.CallExpression, name string, method objects.Object) objects.Object,
+) objects.Object {
+	switch right := right.(type) {
+	case *ast.Identifier:
+		method, err := receiver.Get(right.Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return method
+	case *ast.CallExpression:
+		name, ok := right.Function.(*ast.Identifier)
+		if !ok {
+			return objects.NewError(
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, expected identifier, got %s",
+				receiver.Type(), right.Function.TokenLiteral(),
+			)
+		}
+
+		method, err := receiver.Get(name.Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return call(right, name.Value, method)
+
+	default:
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"invalid chain expression for %s, got %s",
+			receiver.Type(), right.TokenLiteral(),
+		)
+	}
+}
+
+func evalTaskChainExpression(
+	node *ast.ChainExpression,
+	task *objects.Task,
+	right ast.Expression,
+	env *objects.Environment,
+) objects.Object {
+	call := func(right *ast.CallExpression, name string, method objects.Object) objects.Object {
+		if len(right.Arguments) != 0 {
+			return objects.NewError(
+				right.Token, env.GetFileDescriptorContext(),
+				"%s", objects.NewWrongNumberOfArgumentsError(name, 0, len(right.Arguments)).Error(),
+			)
+		}
+
+		// The result is awaited directly instead of through the bound
+		// method, so errors raised within the task keep their position.
+		if name == "await" {
+			return task.Await()
 		}
 
-		return evalIndexExpression(right, pair.Value, index, env)
-	case *ast.ChainExpression:
-		leftInner, ok := right.Left.(*ast.Identifier)
-		if !ok {
-			return objects.NewError(
-				node.Token, env.GetFileDescriptorContext(),
-				"invalid chain expression for %s, expected identifier, got %s",
-				hash.Type(), right.Left.TokenLiteral(),
-			)
-		}
+		return applyFunction(right, method, []objects.Object{}, env)
+	}
 
-		pair, ok := hash.Pairs[(&objects.String{Value: leftInner.Value}).HashKey()]
-		if !ok {
-			return objects.NewError(
-				node.Token, env.GetFileDescriptorContext(),
-				"invalid chain expression for %s, key not found: %s",
-				hash.Type(), leftInner.Value,
-			)
+	return evalMethodChainExpression(node, task, right, env, call)
+}
+
+func evalPromiseChainExpression(
+	node *ast.ChainExpression,
+	promise *objects.Promise,
+	right ast.Expression,
+	env *objects.Environment,
+) objects.Object {
+	call := func(right *ast.CallExpression, name string, method objects.Object) objects.Object {
+		args := evalExpressions(right.Arguments, env)
+		if len(args) == 1 && objects.IsError(args[0]) {
+			return args[0]
 		}
 
-		return evalChainExpression(node, pair.Value, right.Right, env)
-	case *ast.AssignmentExpression:
-		wrapped, ok := right.Right.(*ast.AssignmentExpression)
-		if !ok {
-			if index, ok := right.Left.(*ast.IndexExpression); ok {
-				return evalChainIndexAssignment(hash, right, index, env)
-			}
+		return applyFunction(right, method, args, env)
+	}
+
+	return evalMethodChainExpression(node, promise, right, env, call)
+}
 
+func evalGeneratorChainExpression(
+	node *ast.ChainExpression,
+	generator *objects.Generator,
+	right ast.Expression,
+	env *objects.Environment,
+) objects.Object {
+	call := func(right *ast.CallExpression, name string, method objects.Object) objects.Object {
+		if len(right.Arguments) != 0 {
 			return objects.NewError(
 				right.Token, env.GetFileDescriptorContext(),
-				"unsupported chain assignment structure for %s: %T",
-				hash.Type(), right.Left,
+				"%s", objects.NewWrongNumberOfArgumentsError(name, 0, len(right.Arguments)).Error(),
 			)
 		}
 
-		leftKey, ok := wrapped.Left.(*ast.Identifier)
+		// The generator is resumed directly instead of through its bound
+		// method, so errors raised within it keep their original position.
+		result, err := generator.NextResult()
+		if err != nil {
+			return unwrapGeneratorError(err, right, env)
+		}
+
+		return result
+	}
+
+	return evalMethodChainExpression(node, generator, right, env, call)
+}
+
+func evalEnumChainExpression(
+	node *ast.ChainExpression,
+	enum *objects.Enum,
+	right ast.Expression,
+	env *objects.Environment,
+) objects.Object {
+	variant := func(name *ast.Identifier) objects.Object {
+		value, err := enum.Variant(name.Value)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
+	}
+
+	switch right := right.(type) {
+	case *ast.Identifier:
+		return variant(right)
+	case *ast.CallExpression:
+		name, ok := right.Function.(*ast.Identifier)
 		if !ok {
 			return objects.NewError(
-				wrapped.Token, env.GetFileDescriptorContext(),
-				"invalid assignment expression for %s, expected identifier, got %s",
-				hash.Type(), wrapped.Left.TokenLiteral(),
+				node.Token, env.GetFileDescriptorContext(),
+				"invalid chain expression for %s, expected identifier, got %s",
+				enum.Name, right.Function.TokenLiteral(),
 			)
 		}
 
-		obj := Eval(wrapped.Right, env)
-		if objects.IsError(obj) {
-			return obj
+		value := variant(name)
+		if objects.IsError(value) {
+			return value
 		}
 
-		key := &objects.String{Value: leftKey.Value}
-		hash.Pairs[key.HashKey()] = objects.HashPair{Key: key, Value: obj}
-
-		return obj
+		return evalCallExpression(right, value, env)
 
 	default:
 		return objects.NewError(
 			node.Token, env.GetFileDescriptorContext(),
 			"invalid chain expression for %s, got %s",
-			hash.Type(), right.TokenLiteral(),
+			enum.Name, right.TokenLiteral(),
 		)
 	}
 }
@@ -689,12 +1698,21 @@ func evalChainIndexAssignment(
 		)
 	}
 
-	arr, isArr := pair.Value.(*objects.Array)
+	return evalChainArrayIndexAssignment(pair.Value, assign, index, env)
+}
+
+func evalChainArrayIndexAssignment(
+	target objects.Object,
+	assign *ast.AssignmentExpression,
+	index *ast.IndexExpression,
+	env *objects.Environment,
+) objects.Object {
+	arr, isArr := target.(*objects.Array)
 	if !isArr {
 		return objects.NewError(
 			index.Token, env.GetFileDescriptorContext(),
 			"expected array at chain index assignment target, got %s",
-			pair.Value.Type(),
+			target.Type(),
 		)
 	}
 
@@ -717,7 +1735,12 @@ func evalChainIndexAssignment(
 		return valueObj
 	}
 
-	if idxInt.Value < 0 || idxInt.Value >= int64(len(arr.Elements)) {
+	position := idxInt.Value
+	if position < 0 {
+		position += int64(len(arr.Elements))
+	}
+
+	if position < 0 || position >= int64(len(arr.Elements)) {
 		return objects.NewError(
 			index.Token, env.GetFileDescriptorContext(),
 			"array index out of bounds: %d",
@@ -725,7 +1748,7 @@ func evalChainIndexAssignment(
 		)
 	}
 
-	arr.Elements[idxInt.Value] = valueObj
+	arr.Elements[position] = valueObj
 	return valueObj
 }
 
@@ -762,6 +1785,8 @@ func evalAssignmentExpression(
 				node.Token, env.GetFileDescriptorContext(),
 				"cannot assign to immutable hash keys",
 			)
+		case *objects.StructInstance:
+			return evalStructAssignmentExpression(node, leftObj, left.Index, right, env)
 
 		default:
 			return objects.NewError(
@@ -780,6 +1805,35 @@ func evalAssignmentExpression(
 	}
 }
 
+func evalStructAssignmentExpression(
+	node *ast.AssignmentExpression,
+	instance *objects.StructInstance,
+	index ast.Expression,
+	value objects.Object,
+	env *objects.Environment,
+) objects.Object {
+	idx := Eval(index, env)
+	if objects.IsError(idx) {
+		return idx
+	}
+
+	field, ok := idx.(*objects.String)
+	if !ok {
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"index operator not supported: %s",
+			idx.Type(),
+		)
+	}
+
+	err := instance.Set(field.Value, value)
+	if err != nil {
+		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+	}
+
+	return value
+}
+
 func evalArrayAssignmentExpression(
 	node *ast.AssignmentExpression,
 	arr *objects.Array,
@@ -794,7 +1848,12 @@ func evalArrayAssignmentExpression(
 
 	switch idx := idx.(type) {
 	case *objects.Integer:
-		if idx.Value < 0 || idx.Value >= int64(len(arr.Elements)) {
+		position := idx.Value
+		if position < 0 {
+			position += int64(len(arr.Elements))
+		}
+
+		if position < 0 || position >= int64(len(arr.Elements)) {
 			return objects.NewError(
 				node.Token, env.GetFileDescriptorContext(),
 				"array index out of bounds: %d",
@@ -802,7 +1861,7 @@ func evalArrayAssignmentExpression(
 			)
 		}
 
-		arr.Elements[idx.Value] = value
+		arr.Elements[position] = value
 
 	default:
 		return objects.NewError(
@@ -846,34 +1905,31 @@ func evalNumberInfixExpression(
 	left, right objects.Object,
 	env *objects.Environment,
 ) objects.Object {
-	leftVal := objects.UnwrapNumberValue(left)
-	rightVal := objects.UnwrapNumberValue(right)
+	switch node.Operator {
+	case "+", "-", "*", "/", "^", "%":
+		result, err := objects.ApplyNumberOperator(node.Operator, left, right)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err)
+		}
+
+		return result
+	}
+
+	cmp, ordered := objects.CompareNumbers(left, right)
 
 	switch node.Operator {
-	case "+":
-		return objects.WrapNumberValue(leftVal+rightVal, left, right)
-	case "-":
-		return objects.WrapNumberValue(leftVal-rightVal, left, right)
-	case "*":
-		return objects.WrapNumberValue(leftVal*rightVal, left, right)
-	case "/":
-		return objects.WrapNumberValue(leftVal/rightVal, left, right)
-	case "^":
-		return objects.WrapNumberValue(math.Pow(leftVal, rightVal), left, right)
-	case "%":
-		return objects.WrapNumberValue(math.Mod(leftVal, rightVal), left, right)
 	case "<":
-		return objects.NativeBoolToBooleanObject(leftVal < rightVal)
+		return objects.NativeBoolToBooleanObject(ordered && cmp < 0)
 	case ">":
-		return objects.NativeBoolToBooleanObject(leftVal > rightVal)
+		return objects.NativeBoolToBooleanObject(ordered && cmp > 0)
 	case "==":
-		return objects.NativeBoolToBooleanObject(leftVal == rightVal)
+		return objects.NativeBoolToBooleanObject(ordered && cmp == 0)
 	case "!=":
-		return objects.NativeBoolToBooleanObject(leftVal != rightVal)
+		return objects.NativeBoolToBooleanObject(!ordered || cmp != 0)
 	case "<=":
-		return objects.NativeBoolToBooleanObject(leftVal <= rightVal)
+		return objects.NativeBoolToBooleanObject(ordered && cmp <= 0)
 	case ">=":
-		return objects.NativeBoolToBooleanObject(leftVal >= rightVal)
+		return objects.NativeBoolToBooleanObject(ordered && cmp >= 0)
 
 	default:
 		return objects.NewError(
@@ -884,6 +1940,36 @@ func evalNumberInfixExpression(
 	}
 }
 
+func isBitwiseOperator(operator string) bool {
+	switch operator {
+	case "&", "|", "~", "<<", ">>":
+		return true
+	}
+
+	return false
+}
+
+func evalBitwiseInfixExpression(
+	node *ast.InfixExpression,
+	left, right objects.Object,
+	env *objects.Environment,
+) objects.Object {
+	if !objects.IsInteger(left) || !objects.IsInteger(right) {
+		return objects.NewError(
+			node.Token, env.GetFileDescriptorContext(),
+			"unknown operator: %s %s %s",
+			left.Type(), node.Operator, right.Type(),
+		)
+	}
+
+	result, err := objects.ApplyBitwiseOperator(node.Operator, left, right)
+	if err != nil {
+		return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err)
+	}
+
+	return result
+}
+
 func evalStringInfixExpression(
 	node *ast.InfixExpression,
 	left, right objects.Object,
@@ -934,20 +2020,48 @@ func evalExpressions(exps []ast.Expression, env *objects.Environment) []objects.
 	var result []objects.Object
 
 	for _, e := range exps {
+		spread, isSpread := e.(*ast.SpreadElement)
+		if isSpread {
+			e = spread.Value
+		}
+
 		evaluated := Eval(e, env)
 		if objects.IsError(evaluated) {
 			return []objects.Object{evaluated}
 		}
 
-		result = append(result, evaluated)
+		if !isSpread {
+			result = append(result, evaluated)
+			continue
+		}
+
+		switch evaluated := evaluated.(type) {
+		case *objects.Array:
+			result = append(result, evaluated.Elements...)
+		case *objects.Range:
+			result = append(result, evaluated.Elements()...)
+
+		default:
+			return []objects.Object{objects.NewError(
+				spread.Token, env.GetFileDescriptorContext(),
+				"cannot spread non-array value: %s",
+				evaluated.Type(),
+			)}
+		}
 	}
 
 	return result
 }
 
-func evalCallExpression(node *ast.CallExpression, function objects.Object, env *objects.Environment) objects.Object {
-	args := evalExpressions(node.Arguments, env)
+// evalSpawnExpression calls the function in a task of its own, the arguments
+// are evaluated right away, before the task starts running.
+func evalSpawnExpression(node *ast.SpawnExpression, env *objects.Environment) objects.Object {
+	function := Eval(node.Call.Function, env)
+	if objects.IsError(function) {
+		return function
+	}
 
+	args := evalExpressions(node.Call.Arguments, env)
 	if len(args) == 1 && objects.IsError(args[0]) {
 		return objects.NewEmptyErrorWithParent(
 			args[0].(*objects.Error),
@@ -956,24 +2070,76 @@ func evalCallExpression(node *ast.CallExpression, function objects.Object, env *
 		)
 	}
 
-	fnObj, ok := function.(*objects.Function)
-	if ok && len(args) < len(fnObj.Parameters) {
-		fnName := "<anonymous>"
-		if fnObj.Name != nil {
-			fnName = fnObj.Name.Value
+	switch fn := function.(type) {
+	case *objects.Function:
+		err := fn.ValidateArguments(len(args))
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+	case *objects.BoundMethod:
+		err := fn.ValidateArguments(len(args))
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
 		}
 
+		function = fn.Method
+		args = append([]objects.Object{fn.Receiver}, args...)
+	case *objects.Builtin, *objects.ASTAwareBuiltin:
+
+	default:
 		return objects.NewError(
 			node.Token, env.GetFileDescriptorContext(),
-			"%s",
-			objects.NewWrongNumberOfArgumentsError(
-				fnName,
-				len(fnObj.Parameters),
-				len(args),
-			).Error(),
+			"cannot spawn non-function: %s",
+			function.Type(),
+		)
+	}
+
+	return objects.NewTask(func() objects.Object {
+		return applyFunction(node.Call, function, args, env)
+	})
+}
+
+func evalCallExpression(node *ast.CallExpression, function objects.Object, env *objects.Environment) objects.Object {
+	args := evalExpressions(node.Arguments, env)
+
+	if len(args) == 1 && objects.IsError(args[0]) {
+		return objects.NewEmptyErrorWithParent(
+			args[0].(*objects.Error),
+			node.GetToken(),
+			env.GetFileDescriptorContext(),
 		)
 	}
 
+	switch fnObj := function.(type) {
+	case *objects.Function:
+		err := fnObj.ValidateArguments(len(args))
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+	case *objects.BoundMethod:
+		err := fnObj.ValidateArguments(len(args))
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		function = fnObj.Method
+		args = append([]objects.Object{fnObj.Receiver}, args...)
+	case *objects.Struct:
+		instance, err := fnObj.NewInstance(args)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return instance
+	case *objects.EnumVariant:
+		value, err := fnObj.New(args)
+		if err != nil {
+			return objects.NewError(node.Token, env.GetFileDescriptorContext(), "%s", err.Error())
+		}
+
+		return value
+	}
+
 	result := applyFunction(node, function, args, env)
 	if objects.IsError(result) {
 		return objects.NewEmptyErrorWithParent(
@@ -986,6 +2152,79 @@ func evalCallExpression(node *ast.CallExpression, function objects.Object, env *
 	return result
 }
 
+func evalStructStatement(node *ast.StructStatement, env *objects.Environment) objects.Object {
+	fields := []string{}
+	for _, field := range node.Fields {
+		fields = append(fields, field.Value)
+	}
+
+	structObj := &objects.Struct{
+		Name:    node.Name.Value,
+		Fields:  fields,
+		Methods: map[string]objects.Object{},
+	}
+
+	return env.Set(node, node.Name.Value, structObj, false)
+}
+
+func evalEnumStatement(node *ast.EnumStatement, env *objects.Environment) objects.Object {
+	enum := &objects.Enum{Name: node.Name.Value, Variants: []*objects.EnumVariant{}}
+
+	for _, variant := range node.Variants {
+		fields := []string{}
+		for _, field := range variant.Fields {
+			fields = append(fields, field.Value)
+		}
+
+		enum.Variants = append(enum.Variants, &objects.EnumVariant{
+			Enum:   enum,
+			Name:   variant.Name.Value,
+			Fields: fields,
+		})
+	}
+
+	return env.Set(node, node.Name.Value, enum, false)
+}
+
+func evalMethodLiteral(node *ast.MethodLiteral, env *objects.Environment) objects.Object {
+	obj := evalIdentifier(node.Struct, env)
+	if objects.IsError(obj) {
+		return obj
+	}
+
+	structObj, ok := obj.(*objects.Struct)
+	if !ok {
+		return objects.NewError(
+			node.Struct.Token, env.GetFileDescriptorContext(),
+			"cannot define method on non-struct type: %s",
+			obj.Type(),
+		)
+	}
+
+	name := node.Function.Name.Value
+	if structObj.HasField(name) {
+		return objects.NewError(
+			node.Function.Name.Token, env.GetFileDescriptorContext(),
+			"method %s conflicts with a field of struct %s",
+			name, structObj.Name,
+		)
+	}
+
+	method := &objects.Function{
+		Name:       &ast.Identifier{Token: node.Function.Name.Token, Value: structObj.Name + "." + name},
+		Parameters: append([]ast.Expression{node.Receiver}, node.Function.Parameters...),
+		Rest:       node.Function.Rest,
+		Env:        env,
+		Body:       node.Function.Body,
+		Generator:  node.Function.Generator,
+		Async:      node.Function.Async,
+	}
+
+	structObj.Methods[name] = method
+
+	return method
+}
+
 func evalImportStatement(node *ast.ImportStatement, env *objects.Environment) objects.Object {
 	if env.GetFileDescriptorContext() == nil {
 		return objects.NewError(
@@ -1052,7 +2291,7 @@ func evalImportZenFileContents(node *ast.ImportStatement, env *objects.Environme
 	}
 
 	newEnv := objects.NewEnvironment(path)
-	evaluated := Eval(program, newEnv)
+	evaluated := evalProgram(program.Statements, newEnv)
 	if evaluated == nil {
 		return objects.NewError(
 			node.Token, env.GetFileDescriptorContext(),
@@ -1143,7 +2382,19 @@ func applyFunction(
 ) objects.Object {
 	switch fn := fn.(type) {
 	case *objects.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
+		extendedEnv, err := extendFunctionEnv(fn, args)
+		if err != nil {
+			return err
+		}
+
+		if fn.Generator {
+			return newGenerator(fn, extendedEnv)
+		}
+
+		if fn.Async {
+			return newAsyncPromise(fn, extendedEnv)
+		}
+
 		evaluated := Eval(fn.Body, extendedEnv)
 		return objects.UnwrapReturnValue(evaluated)
 	case *objects.Builtin:
@@ -1164,12 +2415,135 @@ func applyFunction(
 	}
 }
 
-func extendFunctionEnv(fn *objects.Function, args []objects.Object) *objects.Environment {
+// newGenerator creates a generator that evaluates the body of the function in
+// a goroutine of its own, which is paused every time a value is yielded,
+// until the generator is resumed again by the code consuming it. Once the
+// generator can no longer be resumed the paused goroutine is stopped, so
+// generators that aren't run to completion don't leak their goroutine.
+//
+// The paused goroutine keeps the environment of the function alive, so a
+// generator that can be reached from that environment, like a generator
+// stored in a global variable, or in a variable next to the generator
+// function, is never collected, and its goroutine is kept until the program
+// exits. Generators that are run to completion never have this problem.
+func newGenerator(fn *objects.Function, env *objects.Environment) *objects.Generator {
+	yields := make(chan objects.Object)
+	resumes := make(chan struct{})
+	stop := make(chan struct{})
+	started := false
+
+	var result objects.Object
+
+	env.SetYieldHandler(func(val objects.Object) {
+		yields <- val
+
+		select {
+		case <-resumes:
+		case <-stop:
+			runtime.Goexit()
+		}
+	})
+
+	run := func() {
+		result = Eval(fn.Body, env)
+		close(yields)
+	}
+
+	name := ""
+	if fn.Name != nil {
+		name = fn.Name.Value
+	}
+
+	generator := objects.NewGenerator(name, func() (objects.Object, bool, error) {
+		if !started {
+			started = true
+			go run()
+		} else {
+			resumes <- struct{}{}
+		}
+
+		value, ok := <-yields
+		if !ok {
+			if objects.IsError(result) {
+				return nil, false, &generatorError{err: result.(*objects.Error)}
+			}
+
+			return objects.NULL, false, nil
+		}
+
+		return value, true, nil
+	})
+
+	// The finalizer only runs once nothing references the generator anymore,
+	// which includes the environment the paused goroutine is holding on to.
+	runtime.SetFinalizer(generator, func(*objects.Generator) {
+		close(stop)
+	})
+
+	return generator
+}
+
+// newAsyncPromise evaluates the body of the async function as a coroutine,
+// returning a promise that is settled once the function has returned.
+func newAsyncPromise(fn *objects.Function, env *objects.Environment) *objects.Promise {
+	return objects.NewAsyncPromise(func() objects.Object {
+		return objects.UnwrapReturnValue(Eval(fn.Body, env))
+	})
+}
+
+// generatorError wraps the errors raised within a generator, so the error
+// object can be recovered with its original position by the consumer.
+type generatorError struct {
+	err *objects.Error
+}
+
+func (e *generatorError) Error() string {
+	err := e.err
+	for err.Parent != nil {
+		err = err.Parent
+	}
+
+	return err.Message
+}
+
+// unwrapGeneratorError returns the error that stopped a generator as an error
+// object, errors raised within the generator keep their original position.
+func unwrapGeneratorError(err error, node ast.Node, env *objects.Environment) objects.Object {
+	var genErr *generatorError
+	if errors.As(err, &genErr) {
+		return genErr.err
+	}
+
+	return objects.NewError(node.GetToken(), env.GetFileDescriptorContext(), "%s", err.Error())
+}
+
+func extendFunctionEnv(fn *objects.Function, args []objects.Object) (*objects.Environment, objects.Object) {
 	env := objects.NewEnclosedEnvironment(fn.Env)
 
+	bind := func(ident *ast.Identifier, val objects.Object) objects.Object {
+		return env.SetImmutableForcefully(ident.Value, val)
+	}
+
 	for paramIdx, param := range fn.Parameters {
-		env.SetImmutableForcefully(param.Value, args[paramIdx])
+		var arg objects.Object = objects.NULL
+		if paramIdx < len(args) {
+			arg = args[paramIdx]
+		}
+
+		rs := destructurePattern(param, arg, env, bind)
+		if objects.IsError(rs) {
+			return nil, rs
+		}
+	}
+
+	if fn.Rest != nil {
+		rest := []objects.Object{}
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+
+		env.SetImmutableForcefully(fn.Rest.Value, &objects.Array{Elements: rest})
 	}
 
-	return env
+	return env, nil
 }
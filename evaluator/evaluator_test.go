@@ -1,7 +1,9 @@
 package evaluator
 
 import (
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/senither/zen-lang/lexer"
 	"github.com/senither/zen-lang/objects"
@@ -92,12 +94,17 @@ func TestErrorHandling(t *testing.T) {
 				return z(b);
 			}
 
-			println(c(func () {
+			println(c(func (f) {
 				return true + false;
 			}));
 			`,
 			&objects.Error{Message: "unknown operator: BOOLEAN + BOOLEAN"},
 		},
+		{
+			"for loop over non-iterable",
+			"for (v in 5) { v; }",
+			&objects.Error{Message: "cannot iterate over INTEGER"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -188,3 +195,403 @@ func TestFunctionApplication(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"default parameter", "var f = func(a, b = 10) { a + b }; f(1);", 11},
+		{"default parameter overridden", "var f = func(a, b = 10) { a + b }; f(1, 2);", 3},
+		{"default parameter with null argument", "var f = func(a, b = 10) { a + b }; f(1, null);", 11},
+		{"default parameter referencing earlier parameter", "var f = func(a, b = a * 2) { b }; f(4);", 8},
+		{"multiple default parameters", `var f = func(a = "x", b = "y") { a + b }; f() + f("a") + f("a", "b");`, "xyayab"},
+		{"default destructuring parameter", "var f = func({ n = 1 } = {}) { n }; f() + f({\"n\": 5});", 6},
+		{"rest parameter", "var f = func(...rest) { rest }; f(1, 2, 3);", []int{1, 2, 3}},
+		{"rest parameter without arguments", "var f = func(a, ...rest) { len(rest) }; f(1);", 0},
+		{"rest parameter after defaults", "var f = func(a, b = 2, ...rest) { a + b + len(rest) }; f(1) + f(1, 1, 1, 1);", 7},
+		{"named function with rest parameter", "func count(...items) { len(items) }; count(1, 2, 3);", 3},
+		{"closure with rest parameter", "var f = func(...a) { func() { a } }; f(1, 2)();", []int{1, 2}},
+		{"spread arguments", "var f = func(a, b, c) { a + b + c }; f(...[1, 2, 3]);", 6},
+		{"spread arguments with regular arguments", "var f = func(a, b, c) { a * b + c }; var args = [2]; f(5, ...args, 1);", 11},
+		{"spread arguments into rest parameter", "var f = func(...rest) { rest }; f(0, ...[1, 2], ...[3]);", []int{0, 1, 2, 3}},
+		{"spread empty array", "var f = func(a = 1) { a }; f(...[]);", 1},
+		{"spread arguments to builtin", "len(...[[1, 2, 3]]);", 3},
+		{"array literal spread", "var a = [1, 2]; var b = [4]; [0, ...a, 3, ...b];", []int{0, 1, 2, 3, 4}},
+		{"array literal spread copies elements", "var mut a = [1]; var b = [...a]; a[0] = 2; b;", []int{1}},
+		{"hash literal spread", `var base = {"a": 1, "b": 2}; var h = {...base, "c": 3}; h["a"] + h["b"] + h["c"];`, 6},
+		{"hash literal spread overridden by pairs", `var base = {"a": 1}; {"a": 5, ...base}["a"];`, 5},
+		{"hash literal multiple spreads", `var h = {...{"a": 1, "b": 1}, ...{"b": 2}}; h["a"] + h["b"];`, 3},
+		{
+			"too few arguments for default parameters",
+			"func(a, b = 1) { a + b; }();",
+			&objects.Error{Message: "wrong number of arguments to `<anonymous>`: got 0, want at least 1"},
+		},
+		{
+			"too many arguments",
+			"func(a) { a; }(1, 2);",
+			&objects.Error{Message: "wrong number of arguments to `<anonymous>`: got 2, want 1"},
+		},
+		{
+			"too many arguments for default parameters",
+			"func(a, b = 1) { a + b; }(1, 2, 3);",
+			&objects.Error{Message: "wrong number of arguments to `<anonymous>`: got 3, want at most 2"},
+		},
+		{
+			"too many spread arguments",
+			"func named(a) { a; }; named(...[1, 2]);",
+			&objects.Error{Message: "wrong number of arguments to `named`: got 2, want 1"},
+		},
+		{
+			"too few arguments for rest parameter",
+			"func(a, ...b) { a; }();",
+			&objects.Error{Message: "wrong number of arguments to `<anonymous>`: got 0, want at least 1"},
+		},
+		{
+			"spreading non array into arguments",
+			"func(a) { a }(...1);",
+			&objects.Error{Message: "cannot spread non-array value: INTEGER"},
+		},
+		{
+			"spreading non array into array",
+			`[..."abc"];`,
+			&objects.Error{Message: "cannot spread non-array value: STRING"},
+		},
+		{
+			"spreading non hash into hash",
+			"{...[1]};",
+			&objects.Error{Message: "cannot spread non-hash value: ARRAY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("function application: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"field access", `struct User { name, age }; var u = User("Alice", 30); u.age`, 30},
+		{"missing fields are null", `struct User { name, age }; var u = User("Alice"); u.age`, nil},
+		{"field assignment", `struct User { name }; var u = User("Alice"); u.name = "Bob"; u.name`, "Bob"},
+		{"index access", `struct User { name }; var u = User("Alice"); u["name"]`, "Alice"},
+		{"index assignment", `struct User { name }; var u = User(); u["name"] = "Bob"; u.name`, "Bob"},
+		{"field array assignment", `struct List { items }; var l = List([1, 2]); l.items[0] = 5; l.items`, []int{5, 2}},
+		{"instance type", `struct User { name }; type(User("Alice"))`, "User"},
+		{"struct type", `struct User { name }; type(User)`, "STRUCT"},
+		{"equality", `struct P { x }; P(1) == P(1)`, true},
+		{"inequality", `struct P { x }; P(1) == P(2)`, false},
+		{
+			"method",
+			`struct User { name }; func (u User) greet(greeting) { greeting + ", " + u.name }; var u = User("Alice"); u.greet("Hi")`,
+			"Hi, Alice",
+		},
+		{
+			"method with default parameter",
+			`struct User { name }; func (u User) greet(greeting = "Hello") { greeting + ", " + u.name }; var u = User("Alice"); u.greet()`,
+			"Hello, Alice",
+		},
+		{
+			"method modifying receiver",
+			`struct Counter { count }; func (c Counter) inc() { c.count = c.count + 1 }; var c = Counter(0); c.inc(); c.inc(); c.count`,
+			2,
+		},
+		{
+			"method calling another method",
+			`struct P { x, y }; func (p P) sum() { p.x + p.y }; func (p P) double() { p.sum() * 2 }; var p = P(2, 3); p.double()`,
+			10,
+		},
+		{
+			"bound method",
+			`struct P { x }; func (p P) get() { p.x }; var p = P(4); var get = p.get; get()`,
+			4,
+		},
+		{
+			"unknown field",
+			`struct User { name }; var u = User(); u.age`,
+			&objects.Error{Message: `struct User has no field or method named "age"`},
+		},
+		{
+			"assigning unknown field",
+			`struct User { name }; var u = User(); u.age = 30`,
+			&objects.Error{Message: `struct User has no field named "age"`},
+		},
+		{
+			"too many constructor arguments",
+			`struct User { name }; User("Alice", 30)`,
+			&objects.Error{Message: "wrong number of arguments to `User`: got 2, want at most 1"},
+		},
+		{
+			"too few method arguments",
+			`struct User { name }; func (u User) greet(greeting) { greeting }; var u = User(); u.greet()`,
+			&objects.Error{Message: "wrong number of arguments to `User.greet`: got 0, want 1"},
+		},
+		{
+			"method on non-struct",
+			`var User = 1; func (u User) greet() { 1 }`,
+			&objects.Error{Message: "cannot define method on non-struct type: INTEGER"},
+		},
+		{
+			"method conflicting with field",
+			`struct User { name }; func (u User) name() { 1 }`,
+			&objects.Error{Message: "method name conflicts with a field of struct User"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("structs: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestEnums(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"variant", "enum Status { Active, Disabled }; string(Status.Active)", "Status.Active"},
+		{"variant with values", `enum Status { Active, Banned(reason) }; string(Status.Banned("spam"))`, "Status.Banned(spam)"},
+		{"definition", "enum Status { Active, Banned(reason) }; string(Status)", "enum Status { Active, Banned(reason) }"},
+		{"variant equality", "enum Status { Active, Disabled }; Status.Active == Status.Active", true},
+		{"variant inequality", "enum Status { Active, Disabled }; Status.Active == Status.Disabled", false},
+		{"variant values equality", "enum Status { Banned(reason) }; Status.Banned(1) == Status.Banned(1)", true},
+		{"variants of different enums", "enum A { X }; enum B { X }; A.X == B.X", false},
+		{"variant field access", `enum Status { Banned(reason) }; var s = Status.Banned("spam"); s.reason`, "spam"},
+		{"variant index access", `enum Status { Banned(reason) }; var s = Status.Banned("spam"); s[0]`, "spam"},
+		{"variant type", "enum Status { Active }; type(Status.Active)", "Status"},
+		{"match variant", "enum S { A, B }; match (S.B) { S.A => 1, S.B => 2 }", 2},
+		{"match variant values", "enum S { A(x, y) }; match (S.A(2, 3)) { S.A(x, y) => x * y }", 6},
+		{"match variant without values", "enum S { A(x) }; match (S.A(5)) { S.A => 1 }", 1},
+		{"match variant literal values", "enum S { A(x) }; match (S.A(5)) { S.A(1) => 1, S.A(5) => 2, S.A(x) => 3 }", 2},
+		{"match variant value count", "enum S { A(x) }; match (S.A(5)) { S.A(x, y) => 1, _ => 2 }", 2},
+		{
+			"unknown variant",
+			"enum Status { Active }; Status.Banned",
+			&objects.Error{Message: `enum Status has no variant named "Banned"`},
+		},
+		{
+			"wrong number of variant values",
+			"enum Status { Banned(reason) }; Status.Banned(1, 2)",
+			&objects.Error{Message: "wrong number of arguments to `Status.Banned`: got 2, want 1"},
+		},
+		{
+			"calling variant without values",
+			"enum Status { Active }; Status.Active(1)",
+			&objects.Error{Message: "variant Status.Active doesn't take any values"},
+		},
+		{
+			"unknown variant in pattern",
+			"enum Status { Active }; match (Status.Active) { Status.Banned => 1 }",
+			&objects.Error{Message: `enum Status has no variant named "Banned"`},
+		},
+		{
+			"match missing variants",
+			"enum S { A, B(x), C }; match (S.A) { S.A => 1, S.B(1) => 2, S.C if true => 3 }",
+			&objects.Error{Message: "non-exhaustive match on enum S, missing variants: B, C"},
+		},
+		{"match missing variants with catch-all", "enum S { A, B, C }; match (S.C) { S.A => 1, _ => 2 }", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run("enums: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"next value", "func* g() { yield 1; yield 2; }; var it = g(); it.next(); var r = it.next(); r.value", 2},
+		{"next done", "func* g() { yield 1; }; var it = g(); it.next(); var r = it.next(); r.done", true},
+		{"empty yield", "func* g() { yield; }; var it = g(); var r = it.next(); r.value", nil},
+		{"arguments", "func* g(a, b = 2) { yield a + b; }; var it = g(1); var r = it.next(); r.value", 3},
+		{"for loop", "func* g(n) { var mut i = 0; while (i < n) { yield i; i++; } }; var mut sum = 0; for (v in g(5)) { sum = sum + v; }; sum", 10},
+		{"yield in if", "func* g() { for (i in 1..6) { if (i % 2 == 0) { yield i; } } }; var mut sum = 0; for (v in g()) { sum = sum + v; }; sum", 12},
+		{"return ends", "func* g() { yield 1; return null; yield 2; }; var mut sum = 0; for (v in g()) { sum = sum + v; }; sum", 1},
+		{"is lazy", "var mut count = 0; func* g() { count++; yield 1; }; var it = g(); count", 0},
+		{"type", "func* g() { yield 1; }; type(g())", "GENERATOR"},
+		{
+			"error thrown in generator",
+			`func* g() { yield 1; throw "out of numbers"; }; for (v in g()) { v }`,
+			&objects.Error{Message: "out of numbers"},
+		},
+		{
+			"unknown method",
+			"func* g() { yield 1; }; var it = g(); it.reset()",
+			&objects.Error{Message: `generator has no method named "reset"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("generators: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestGeneratorsStoppedEarlyReleaseGoroutine(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	testEval(`
+	func* naturals() { var mut i = 0; while (true) { yield i; i++; } };
+	for (n in 0..<10) {
+		for (v in naturals()) { if (v == 3) { break; } };
+		naturals().next();
+	}
+	`)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("generator goroutines were not released, got %d goroutines, want %d", after, before)
+	}
+}
+
+func TestTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"await", "func f(a, b) { a + b }; var t = spawn f(1, 2); t.await()", 3},
+		{"default arguments", "func f(a, b = 10) { a + b }; var t = spawn f(1); t.await()", 11},
+		{"builtin", `var t = spawn len("hello"); t.await()`, 5},
+		{"done", "var t = spawn func() { 1 }(); t.await(); t.done()", true},
+		{"type", "var t = spawn func() { 1 }(); type(t)", "TASK"},
+		{"shares globals", "var mut n = 0; func f() { n = n + 5 }; var t = spawn f(); t.await(); n", 5},
+		{
+			"channels",
+			`var ch = channels.make(); func f(n) { channels.send(ch, n * n) }; spawn f(2); spawn f(3);
+			channels.receive(ch) + channels.receive(ch)`,
+			13,
+		},
+		{
+			"loop over channel",
+			`var ch = channels.make(); func f() { for (i in 1..4) { channels.send(ch, i) }; channels.close(ch) }; spawn f();
+			var mut sum = 0; for (v in ch) { sum = sum + v }; sum`,
+			10,
+		},
+		{
+			"error thrown in task",
+			`var t = spawn func() { throw "task failed" }(); t.await()`,
+			&objects.Error{Message: "task failed"},
+		},
+		{
+			"spawning non-function",
+			"var x = 5; spawn x()",
+			&objects.Error{Message: "cannot spawn non-function: INTEGER"},
+		},
+		{
+			"unknown method",
+			"var t = spawn func() { 1 }(); t.cancel()",
+			&objects.Error{Message: `task has no method named "cancel"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("tasks: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestAsyncFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"await result", "async func f(a, b) { a + b }; await f(1, 2)", 3},
+		{"await non-promise", "await 5", 5},
+		{"nested await", "async func f() { 2 }; async func g() { var v = await f(); v * 3 }; await g()", 6},
+		{"runs eagerly", "var mut n = 0; async func f() { n = 7; }; f(); n", 7},
+		{"type", "async func f() { 1 }; type(f())", "PROMISE"},
+		{"then", "async func f() { 2 }; var p = f(); var q = p.then(func(v) { v * 10 }); await q", 20},
+		{
+			"catch",
+			`async func f() { throw "failed" }; var p = f(); var q = p.catch(func(err) { err.message }); await q`,
+			"failed",
+		},
+		{
+			"error thrown in async function",
+			`async func f() { throw "async failure" }; await f()`,
+			&objects.Error{Message: "async failure"},
+		},
+		{
+			"unknown method",
+			"async func f() { 1 }; var p = f(); p.finally()",
+			&objects.Error{Message: `promise has no method named "finally"`},
+		},
+		{
+			"then without arguments",
+			"async func f() { 1 }; var p = f(); p.then()",
+			&objects.Error{Message: "wrong number of arguments to `then`: got 0, want at least 1"},
+		},
+		{
+			"then with too many arguments",
+			"async func f() { 1 }; var p = f(); var g = func(v) { v }; p.then(g, g, g)",
+			&objects.Error{Message: "wrong number of arguments to `then`: got 3, want at most 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("async: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestBlockScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"global block shadowing", "var a = 1; if (true) { var a = 2; }; a", 1},
+		{"global block value", "var a = 1; if (true) { var a = 2; a }", 2},
+		{"local block shadowing", "func f() { var a = 1; if (true) { var a = 2; }; a }; f()", 1},
+		{"function shadowing", "var a = 1; func f() { var a = 2; a }; f()", 2},
+		{"while block shadowing", "var mut i = 0; var x = 5; while (i < 3) { var x = i; i++; }; x", 5},
+		{"modifying outer from block", "func f() { var mut a = 1; if (true) { a = a + 10; }; a }; f()", 11},
+		{"try block", `var a = 1; try { var a = 2; } catch { }; a`, 1},
+		{
+			"closures in loop capture each iteration",
+			`func f() { var mut fs = []; for (i in 1..3) { var v = i * 2; fs = [...fs, func() { v }]; }; fs }
+			var fs = f(); var a = fs[0]; var b = fs[2]; a() + b()`,
+			8,
+		},
+		{
+			"block variables don't leak",
+			"if (true) { var hidden = 1; }; hidden",
+			&objects.Error{Message: "identifier not found: hidden"},
+		},
+		{
+			"redeclaring in the same block",
+			"if (true) { var a = 1; var a = 2; }",
+			&objects.Error{Message: "cannot modify immutable variable: a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("block scopes: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package evaluator
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/decimal"
 )
 
 func TestEvalStringExpression(t *testing.T) {
@@ -48,6 +50,29 @@ func TestEvalStringCastConcatenation(t *testing.T) {
 	}
 }
 
+func TestEvalStringInterpolation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"interpolated identifier", `var name = "World"; "Hello ${name}!"`, "Hello World!"},
+		{"interpolated expression", `"1 + 2 = ${1 + 2}"`, "1 + 2 = 3"},
+		{"interpolated hash access", `var user = {"name": "Alexis"}; "Hi ${user.name}"`, "Hi Alexis"},
+		{"interpolated function call", `"${len([1, 2, 3])} items"`, "3 items"},
+		{"interpolated nested string", `"a ${"b ${"c"}"}"`, "a b c"},
+		{"interpolated objects", `"${null} ${true} ${1.5} ${[1, 2]}"`, "null true 1.5 [1, 2]"},
+		{"escaped interpolation", `"\${name}"`, "${name}"},
+		{"single quoted string", `'${name}'`, "${name}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestEvalIntegerExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -90,6 +115,76 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestEvalBigIntegerExpression(t *testing.T) {
+	bigInt := func(value string) *big.Int {
+		result, _ := new(big.Int).SetString(value, 10)
+		return result
+	}
+
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"9223372036854775807 + 1", bigInt("9223372036854775808")},
+		{"-9223372036854775807 - 2", bigInt("-9223372036854775809")},
+		{"9223372036854775807 * 3", bigInt("27670116110564327421")},
+		{"2 ^ 100", bigInt("1267650600228229401496703205376")},
+		{"99999999999999999999", bigInt("99999999999999999999")},
+		{"99999999999999999999 - 99999999999999999998", 1},
+		{"-9223372036854775808", -9223372036854775808},
+		{"2 ^ 64 / 2 ^ 32", 4294967296},
+		{"2 ^ 64 % 10", 6},
+		{"2 ^ 64 > 9223372036854775807", true},
+		{"2 ^ 64 <= 2 ^ 64", true},
+		{"var mut n = 9223372036854775807; n++; n", bigInt("9223372036854775808")},
+		{"var mut n = -9223372036854775808; n--; n", bigInt("-9223372036854775809")},
+		{"var mut n = 2 ^ 63; n--; n", 9223372036854775807},
+		{"~(2 ^ 64)", bigInt("-18446744073709551617")},
+		{"2 ^ 9999999999", &objects.Error{Message: "integer overflow: 2 ^ 9999999999 is too large"}},
+		{
+			"func factorial(n) { if (n <= 1) { return 1; }; return n * factorial(n - 1); }; factorial(25)",
+			bigInt("15511210043330985984000000"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("big integer expression: "+tt.input, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestEvalDecimalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"19.99d", decimal.MustParse("19.99")},
+		{"0.1d + 0.2d", decimal.MustParse("0.3")},
+		{"0.1d + 0.2d == 0.3d", true},
+		{"19.99d * 3", decimal.MustParse("59.97")},
+		{"10.00d / 4", decimal.MustParse("2.50")},
+		{"1d / 3", decimal.MustParse("0.3333333333333333")},
+		{"-7.5d % 2", decimal.MustParse("-1.5")},
+		{"1.1d ^ 2", decimal.MustParse("1.21")},
+		{"-19.99d", decimal.MustParse("-19.99")},
+		{"0.1 + 0.2d", decimal.MustParse("0.3")},
+		{"1.50d == 1.5d", true},
+		{"1d == 1", true},
+		{"2.5d > 2", true},
+		{"var mut n = 1.5d; n++; n", decimal.MustParse("2.5")},
+		{"var mut n = 1.5d; n--; n", decimal.MustParse("0.5")},
+		{"1d / 0", &objects.Error{Message: "division by zero"}},
+		{"2d ^ 0.5d", &objects.Error{Message: "decimal exponents must be integers, got 0.5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("decimal expression: "+tt.input, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestEvalFloatExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -272,6 +367,27 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"first character", `"zen"[0]`, "z"},
+		{"multi-byte character", `"héllo"[1]`, "é"},
+		{"character after multi-byte character", `"héllo"[2]`, "l"},
+		{"negative index", `"日本語"[-1]`, "語"},
+		{"out of bounds index", `"héllo"[5]`, &objects.Error{Message: "string index out of bounds: 5"}},
+		{"negative out of bounds index", `"héllo"[-6]`, &objects.Error{Message: "string index out of bounds: -6"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("string index: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestHashLiterals(t *testing.T) {
 	input := `
 		var two = "two";
@@ -420,6 +536,38 @@ func TestChainedHashIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestOptionalChainExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"optional key", `var x = {"a": {"b": 5}}; x?.a?.b`, 5},
+		{"optional null value", `var x = null; x?.a`, nil},
+		{"optional null value short-circuits chain", `var x = null; x?.a.b.c`, nil},
+		{"optional missing key", `var x = {"a": 1}; x?.b?.c`, nil},
+		{"optional nested null value", `var x = {"a": null}; x.a?.b`, nil},
+		{"optional index", `var x = [1, 2]; x?[1]`, 2},
+		{"optional index on null", `var x = null; x?[1]`, nil},
+		{"optional index in chain", `var x = {"a": [1, 2]}; x?.a?[0]`, 1},
+		{"optional index in chain on null", `var x = {"a": null}; x.a?[0]`, nil},
+		{"optional call", `var x = {"f": func() { 7 }}; x?.f()`, 7},
+		{"optional call on null", `var x = null; x?.f()`, nil},
+		{"optional chain with fallback", `var x = null; x?.a ?? 10`, 10},
+		{
+			"non-optional step fails",
+			`var x = {"a": null}; x?.a.b`,
+			&objects.Error{Message: "invalid chain expression for NULL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("optional chain: "+tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestChainedHashAssignmentExpressions(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -531,6 +679,11 @@ func TestReassigningArrayIndexExpressions(t *testing.T) {
 			"var x = [1, 2, 3]; x[2] = true; x;",
 			[]any{1, 2, true},
 		},
+		{
+			"assignment to negative index",
+			"var x = [1, 2, 3]; x[-1] = 99; x;",
+			[]any{1, 2, 99},
+		},
 	}
 
 	for _, tt := range tests {
@@ -631,6 +784,98 @@ func TestIfElseIfElseExpressions(t *testing.T) {
 		})
 	}
 }
+
+func TestTernaryExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"get value from truthy condition", "true ? 10 : 20", 10},
+		{"get value from falsy condition", "false ? 10 : 20", 20},
+		{"get value from integer condition", "1 ? 10 : 20", 10},
+		{"get value from null condition", "null ? 10 : 20", 20},
+		{"get value from comparison condition", "1 > 2 ? 10 : 20", 20},
+		{"get value from nested ternary", "false ? 10 : true ? 20 : 30", 20},
+		{"get value from ternary in function", "var max = func(a, b) { return a > b ? a : b }; max(3, 7);", 7},
+		{"only evaluates the chosen branch", "var mut i = 0; true ? i++ : i--; i;", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestNullCoalescingExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"get left value when not null", "5 ?? 10", 5},
+		{"get right value when null", "null ?? 10", 10},
+		{"get false when left is false", "false ?? true", false},
+		{"get zero when left is zero", "0 ?? 10", 0},
+		{"get value from chained coalescing", "null ?? null ?? 15", 15},
+		{"get null when both sides are null", "null ?? null", nil},
+		{"get value from missing hash key", `var h = {"a": 1}; h["b"] ?? 2;`, 2},
+		{"does not evaluate right side when not null", "var mut i = 0; 1 ?? i++; i;", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"match integer literal", "match (2) { 1 => 10, 2 => 20, _ => 30 }", 20},
+		{"match multiple patterns", "match (3) { 1, 2, 3 => 10, _ => 20 }", 10},
+		{"match float against integer", "match (1.0) { 1 => 10, _ => 20 }", 10},
+		{"match string literal", `match ("b") { "a" => 1, "b" => 2 }`, 2},
+		{"match boolean literal", "match (false) { true => 1, false => 2 }", 2},
+		{"match null literal", "match (null) { 0 => 1, null => 2 }", 2},
+		{"match negative literal", "match (-5) { 5 => 1, -5 => 2 }", 2},
+		{"match wildcard", "match (99) { 1 => 1, _ => 2 }", 2},
+		{"match binding", "match (7) { n => n * 2 }", 14},
+		{"match array pattern", "match ([1, 2]) { [a, b] => a + b }", 3},
+		{"match array pattern length", "match ([1, 2, 3]) { [a, b] => 1, [a, b, c] => c }", 3},
+		{"match nested array pattern", "match ([1, [2, 3]]) { [1, [a, b]] => a * b }", 6},
+		{"match array pattern with literals", "match ([0, 5]) { [1, x] => x, [0, x] => x * 10 }", 50},
+		{"match hash pattern", `match ({"name": "zen"}) { { name } => name }`, "zen"},
+		{"match hash pattern with missing key", `match ({"a": 1}) { { b } => b, { a } => a }`, 1},
+		{"match hash pattern with nested pattern", `match ({"user": {"id": 4}}) { { user: { id } } => id }`, 4},
+		{"match hash pattern with literal value", `match ({"type": "b", "v": 2}) { { type: "a", v } => v, { type: "b", v } => v * 2 }`, 4},
+		{"match array against hash pattern", "match ([1]) { { a } => 1, _ => 2 }", 2},
+		{"match guard", "match (5) { n if n > 10 => 1, n if n > 3 => 2, _ => 3 }", 2},
+		{"match guard with multiple patterns", "match (2) { 1, 2 if false => 1, _ => 2 }", 2},
+		{"match block body", "match (1) { 1 => { var a = 5; a * 2 } }", 10},
+		{"match block body without value", "match (1) { 1 => { var a = 5; } }", nil},
+		{"match in function", `var f = func(x) { return match (x) { 0 => "zero", _ => "other" } }; f(0) + f(1);`, "zeroother"},
+		{"match return from arm", "var f = func(x) { match (x) { 0 => { return 1 }, _ => 2 }; return 3; }; f(0)", 1},
+		{"match nested match", "match (1) { 1 => match (2) { 2 => 3 } }", 3},
+		{
+			"match without matching arm",
+			"match (3) { 1 => 1, 2 => 2 }",
+			&objects.Error{Message: "unhandled match value: 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestWhileExpressions(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -649,3 +894,87 @@ func TestWhileExpressions(t *testing.T) {
 		})
 	}
 }
+
+func TestForExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"loop over array values", "var mut sum = 0; for (v in [1, 2, 3]) { sum = sum + v; } sum;", 6},
+		{"loop over array indexes", "var mut sum = 0; for (i, v in [5, 5, 5]) { sum = sum + i; } sum;", 3},
+		{"loop over hash values", "var mut sum = 0; for (k, v in {\"a\": 1, \"b\": 2}) { sum = sum + v; } sum;", 3},
+		{"loop over string characters", "var mut s = \"\"; for (c in \"abc\") { s = c + s; } s;", "cba"},
+		{"loop over empty array", "var mut i = 0; for (v in []) { i++; } i;", 0},
+		{"loop with break", "var mut i = 0; for (v in [1, 2, 3]) { if (v == 2) { break; } i = v; } i;", 1},
+		{"loop with continue", "var mut i = 0; for (v in [1, 2, 3]) { if (v == 2) { continue; } i = i + v; } i;", 4},
+		{"loop with no return value", "for (v in [1, 2]) { v; }", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestRangeExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"inclusive range", "[...1..5]", []any{1, 2, 3, 4, 5}},
+		{"exclusive range", "[...1..<5]", []any{1, 2, 3, 4}},
+		{"empty range", "[...5..1]", []any{}},
+		{"range with expressions", "var n = 2; [...n - 1..n * 2]", []any{1, 2, 3, 4}},
+		{"range with negative bounds", "[...-2..0]", []any{-2, -1, 0}},
+		{"spreading range into arguments", "func(a, b) { a + b }(...1..2)", 3},
+		{"iterating range", "var mut sum = 0; for (i in 1..4) { sum += i; } sum", 10},
+		{"iterating range with index", "var mut sum = 0; for (i, v in 5..<8) { sum += i * v; } sum", 20},
+		{"range equality", "1..3 == 1..3", true},
+		{"range inequality", "1..3 == 1..<3", false},
+		{"range equality with exclusive end", "1..3 == 1..<4", true},
+		{"empty range inequality", "5..1 == 5..3", false},
+		{"reversed range inequality", "5..1 == 1..5", false},
+		{"range with float bounds", "1.5..3", &objects.Error{Message: "range bounds must be integers, got FLOAT and INTEGER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"slice array with both bounds", "[1, 2, 3, 4, 5][1:3]", []any{2, 3}},
+		{"slice array without start", "[1, 2, 3, 4, 5][:2]", []any{1, 2}},
+		{"slice array without end", "[1, 2, 3, 4, 5][3:]", []any{4, 5}},
+		{"slice array without bounds", "[1, 2, 3][:]", []any{1, 2, 3}},
+		{"slice array with negative end", "[1, 2, 3][:-1]", []any{1, 2}},
+		{"slice array with negative start", "[1, 2, 3][-2:]", []any{2, 3}},
+		{"slice array out of bounds", "[1, 2, 3][1:99]", []any{2, 3}},
+		{"slice array with start after end", "[1, 2, 3][2:1]", []any{}},
+		{"slice array returns a copy", "var a = [1, 2, 3]; var b = a[:]; b[0] = 9; a", []any{1, 2, 3}},
+		{"slice string", `"hello world"[6:]`, "world"},
+		{"slice string with negative bounds", `"hello"[1:-1]`, "ell"},
+		{"slice string out of bounds", `"hello"[-99:99]`, "hello"},
+		{"slice with null bound", "[1, 2, 3][null:2]", []any{1, 2}},
+		{"slice chained array", `var h = {"items": [1, 2, 3]}; h.items[1:]`, []any{2, 3}},
+		{"slice optional null", "var a = null; a?[1:]", nil},
+		{"slice with string bounds", `[1, 2][1:"2"]`, &objects.Error{Message: "slice bounds must be integers, got STRING"}},
+		{"slice hash", `{"a": 1}[0:1]`, &objects.Error{Message: "slice operator not supported: HASH"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
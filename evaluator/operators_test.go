@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/senither/zen-lang/objects"
@@ -29,6 +30,56 @@ func TestBangOperator(t *testing.T) {
 	}
 }
 
+func TestBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"bitwise and", "12 & 10", 8},
+		{"bitwise or", "12 | 10", 14},
+		{"bitwise xor", "12 ~ 10", 6},
+		{"bitwise not", "~12", -13},
+		{"double bitwise not", "~~12", 12},
+		{"shift left", "1 << 4", 16},
+		{"shift right", "256 >> 4", 16},
+		{"shift right keeps the sign", "-16 >> 2", -4},
+		{"shift left past the integer size", "1 << 64", new(big.Int).Lsh(big.NewInt(1), 64)},
+		{"shift binds looser than addition", "1 << 2 + 1", 8},
+		{"bitwise and binds tighter than equality", "6 & 3 == 2", true},
+		{"bitwise precedence", "1 | 2 ~ 3 & 4", 3},
+		{"compound bitwise or", "var mut flags = 1; flags |= 4; flags", 5},
+		{"compound bitwise and", "var mut flags = 7; flags &= ~2; flags", 5},
+		{"compound bitwise xor", "var mut flags = 5; flags ~= 1; flags", 4},
+		{"compound shifts", "var mut n = 1; n <<= 3; n >>= 1; n", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
+func TestBitwiseOperatorFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *objects.Error
+	}{
+		{"bitwise and with floats", "1.5 & 1", &objects.Error{Message: "unknown operator: FLOAT & INTEGER"}},
+		{"bitwise or with strings", `"a" | "b"`, &objects.Error{Message: "unknown operator: STRING | STRING"}},
+		{"bitwise not with boolean", "~true", &objects.Error{Message: "unknown operator: ~BOOLEAN"}},
+		{"negative shift count", "1 << -1", &objects.Error{Message: "negative shift count: -1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestVarReassignmentStatements(t *testing.T) {
 	input := []struct {
 		name     string
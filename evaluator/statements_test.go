@@ -62,6 +62,60 @@ func TestVarStatements(t *testing.T) {
 	}
 }
 
+func TestDestructuringVarStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{"array destructuring", "var [a, b] = [1, 2]; a + b;", 3},
+		{"array destructuring with missing elements", "var [a, b] = [1]; b;", nil},
+		{"array destructuring ignores extra elements", "var [a] = [1, 2, 3]; a;", 1},
+		{"array destructuring with wildcard", "var [_, b] = [1, 2]; b;", 2},
+		{"nested array destructuring", "var [a, [b, c]] = [1, [2, 3]]; a + b + c;", 6},
+		{"hash destructuring", `var { name, age } = {"name": "zen", "age": 3}; name + string(age);`, "zen3"},
+		{"hash destructuring with renamed keys", `var { "name": n, id: i } = {"name": "zen", "id": 1}; n + string(i);`, "zen1"},
+		{"hash destructuring with missing key", `var { name } = {}; name;`, nil},
+		{"nested hash destructuring", `var { user: { tags: [first] } } = {"user": {"tags": ["a", "b"]}}; first;`, "a"},
+		{"destructuring with default values", `var [a, b = 2] = [1]; var { c = 3, d = 4 } = {"d": 5}; a + b + c + d;`, 11},
+		{"destructuring with default for null value", "var [a = 5] = [null]; a;", 5},
+		{"destructuring with nested default pattern", "var [a, [b, c] = [2, 3]] = [1]; a + b + c;", 6},
+		{"destructuring with default referencing earlier binding", "var [a, b = a * 2] = [4]; b;", 8},
+		{"mutable destructuring", "var mut [a, b] = [1, 2]; a = a + b; a;", 3},
+		{"destructuring within function", "var f = func(pair) { var [a, b] = pair; a * b; }; f([3, 4]);", 12},
+		{"function parameter array pattern", "var f = func([a, b]) { a + b }; f([1, 2]);", 3},
+		{"function parameter hash pattern", `var f = func(x, { name, suffix = "!" }) { x + name + suffix }; f("hi ", {"name": "zen"});`, "hi zen!"},
+		{"named function parameter pattern", "func sum([a, b], c) { a + b + c }; sum([1, 2], 3);", 6},
+		{"closure over destructured parameter", "var f = func({ n }) { func() { n * 2 } }; f({\"n\": 4})();", 8},
+		{
+			"destructuring non array value",
+			"var [a] = 1;",
+			&objects.Error{Message: "cannot destructure INTEGER as an array"},
+		},
+		{
+			"destructuring non hash value",
+			"var { a } = [1];",
+			&objects.Error{Message: "cannot destructure ARRAY as a hash"},
+		},
+		{
+			"destructuring non array parameter",
+			"var f = func([a]) { a }; f(true);",
+			&objects.Error{Message: "cannot destructure BOOLEAN as an array"},
+		},
+		{
+			"destructuring immutable binding",
+			"var [a] = [1]; a = 2;",
+			&objects.Error{Message: "cannot modify immutable variable: a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestCompoundAssignments(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -74,6 +128,12 @@ func TestCompoundAssignments(t *testing.T) {
 		{"division assignment", "var mut x = 5; x /= 5;", 1},
 		{"modulus assignment", "var mut x = 5; x %= 5;", 0},
 		{"exponentiation assignment", "var mut x = 5; x ^= 5;", 3125},
+		{"array index assignment", "var x = [1, 2]; x[1] += 5; x[1];", 7},
+		{"nested array index assignment", "var x = [[1, 2]]; x[0][1] *= 5; x[0][1];", 10},
+		{"hash index assignment", `var x = {"a": 5}; x["a"] -= 2; x["a"];`, 3},
+		{"hash chain assignment", `var x = {"a": 5}; x.a -= 2; x.a;`, 3},
+		{"nested hash chain assignment", `var x = {"a": {"b": 3}}; x.a.b ^= 2; x.a.b;`, 9},
+		{"hash chain with index assignment", `var x = {"a": [1, 2]}; x.a[0] += 9; x.a[0];`, 10},
 	}
 
 	for _, tt := range tests {
@@ -113,6 +173,61 @@ func TestWhileBreakStatements(t *testing.T) {
 	}
 }
 
+func TestTryStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{
+			"catch thrown string",
+			`var mut r = ""; try { throw "failed"; } catch (e) { r = e.message; } r;`,
+			"failed",
+		},
+		{
+			"catch runtime error",
+			`var mut r = ""; try { 1 + true; } catch (e) { r = e.message; } r;`,
+			"type mismatch: INTEGER + BOOLEAN",
+		},
+		{
+			"catch error location",
+			"var mut r = 0; try {\n  throw \"failed\";\n} catch (e) { r = e.line * 10 + e.column; } r;",
+			23,
+		},
+		{
+			"catch without parameter",
+			`var mut r = 0; try { throw "failed"; } catch { r = 1; } r;`,
+			1,
+		},
+		{
+			"try without error",
+			`var mut r = 0; try { r = 1; } catch { r = 2; } r;`,
+			1,
+		},
+		{
+			"rethrow caught error",
+			`var mut r = ""; try { try { throw "inner"; } catch (e) { throw e; } } catch (e) { r = e.message; } r;`,
+			"inner",
+		},
+		{
+			"return from within try statement",
+			`func f() { try { return 1; } catch { return 2; } } f();`,
+			1,
+		},
+		{
+			"uncaught throw",
+			`throw "failed";`,
+			&objects.Error{Message: "failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects.AssertExpectedObject(t, tt.expected, testEval(tt.input))
+		})
+	}
+}
+
 func TestExportStatement(t *testing.T) {
 	tests := []struct {
 		name     string
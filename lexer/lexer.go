@@ -2,6 +2,8 @@ package lexer
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/senither/zen-lang/tokens"
 )
@@ -13,6 +15,27 @@ type Lexer struct {
 	currentLine   int
 	currentColumn int
 	ch            byte
+
+	// The string interpolations we're currently inside of, used to
+	// find the closing brace and how to read the rest of the string.
+	templates []template
+}
+
+// template is a string with interpolations that is being read, depth is the
+// brace depth within the current interpolation, and triple is set for triple
+// quoted strings, which are read differently from regular strings.
+type template struct {
+	depth  int
+	triple *tripleQuote
+}
+
+// tripleQuote holds what is needed to read the parts of a triple quoted
+// string, the indentation is found up front so it can be removed from
+// every part of the string, even if it's split up by interpolations.
+type tripleQuote struct {
+	quote     byte
+	indent    string
+	multiline bool
 }
 
 func New(input string) *Lexer {
@@ -35,17 +58,20 @@ func (l *Lexer) NextToken() tokens.Token {
 	switch l.ch {
 	case ';':
 		token = newToken(tokens.SEMICOLON, l)
-	case '"':
-		token = newTokenWithValue(tokens.STRING, l, l.readString('"'))
-	case '\'':
-		token = newTokenWithValue(tokens.STRING, l, l.readString('\''))
+	case '"', '\'', '`':
+		token = l.readStringToken()
 
 	case '=':
-		if l.peekChar() == '=' {
+		switch l.peekChar() {
+		case '=':
 			ch := l.ch
 			l.readChar()
 			token = newTokenWithValue(tokens.EQ, l, string(ch)+string(l.ch))
-		} else {
+		case '>':
+			ch := l.ch
+			l.readChar()
+			token = newTokenWithValue(tokens.ARROW, l, string(ch)+string(l.ch))
+		default:
 			token = newToken(tokens.ASSIGN, l)
 		}
 	case '!':
@@ -98,6 +124,10 @@ func (l *Lexer) NextToken() tokens.Token {
 			ch := l.ch
 			l.readChar()
 			token = newTokenWithValue(tokens.GT_EQ, l, string(ch)+string(l.ch))
+		case '>':
+			ch := l.ch
+			l.readChar()
+			token = newTokenWithValue(tokens.SHIFT_RIGHT, l, string(ch)+string(l.ch))
 		default:
 			token = newToken(tokens.GT, l)
 		}
@@ -107,6 +137,10 @@ func (l *Lexer) NextToken() tokens.Token {
 			ch := l.ch
 			l.readChar()
 			token = newTokenWithValue(tokens.LT_EQ, l, string(ch)+string(l.ch))
+		case '<':
+			ch := l.ch
+			l.readChar()
+			token = newTokenWithValue(tokens.SHIFT_LEFT, l, string(ch)+string(l.ch))
 		default:
 			token = newToken(tokens.LT, l)
 		}
@@ -116,7 +150,7 @@ func (l *Lexer) NextToken() tokens.Token {
 			l.readChar()
 			token = newTokenWithValue(tokens.AND, l, string(ch)+string(l.ch))
 		} else {
-			token = newToken(tokens.ILLEGAL, l)
+			token = newToken(tokens.BIT_AND, l)
 		}
 	case '|':
 		if l.peekChar() == '|' {
@@ -124,7 +158,36 @@ func (l *Lexer) NextToken() tokens.Token {
 			l.readChar()
 			token = newTokenWithValue(tokens.OR, l, string(ch)+string(l.ch))
 		} else {
-			token = newToken(tokens.ILLEGAL, l)
+			token = newToken(tokens.BIT_OR, l)
+		}
+	case '~':
+		token = newToken(tokens.TILDE, l)
+	case '?':
+		switch l.peekChar() {
+		case '?':
+			ch := l.ch
+			l.readChar()
+			token = newTokenWithValue(tokens.NULL_COALESCE, l, string(ch)+string(l.ch))
+		case '.':
+			if l.followsWhitespace() {
+				token = newToken(tokens.QUESTION, l)
+				break
+			}
+
+			ch := l.ch
+			l.readChar()
+			token = newTokenWithValue(tokens.OPTIONAL_CHAIN, l, string(ch)+string(l.ch))
+		case '[':
+			if l.followsWhitespace() {
+				token = newToken(tokens.QUESTION, l)
+				break
+			}
+
+			ch := l.ch
+			l.readChar()
+			token = newTokenWithValue(tokens.OPTIONAL_INDEX, l, string(ch)+string(l.ch))
+		default:
+			token = newToken(tokens.QUESTION, l)
 		}
 
 	case ',':
@@ -132,16 +195,42 @@ func (l *Lexer) NextToken() tokens.Token {
 	case ':':
 		token = newToken(tokens.COLON, l)
 	case '.':
-		token = newToken(tokens.PERIOD, l)
+		switch {
+		case l.peekChar() == '.' && l.peekCharAt(1) == '.':
+			l.readChar()
+			l.readChar()
+			token = newTokenWithValue(tokens.ELLIPSIS, l, "...")
+		case l.peekChar() == '.' && l.peekCharAt(1) == '<':
+			l.readChar()
+			l.readChar()
+			token = newTokenWithValue(tokens.RANGE_EX, l, "..<")
+		case l.peekChar() == '.':
+			l.readChar()
+			token = newTokenWithValue(tokens.RANGE, l, "..")
+		default:
+			token = newToken(tokens.PERIOD, l)
+		}
 
 	case '(':
 		token = newToken(tokens.LPAREN, l)
 	case ')':
 		token = newToken(tokens.RPAREN, l)
 	case '{':
+		if len(l.templates) > 0 {
+			l.templates[len(l.templates)-1].depth++
+		}
+
 		token = newToken(tokens.LBRACE, l)
 	case '}':
-		token = newToken(tokens.RBRACE, l)
+		if len(l.templates) > 0 && l.templates[len(l.templates)-1].depth == 0 {
+			token = l.readTemplateContinuation()
+		} else {
+			if len(l.templates) > 0 {
+				l.templates[len(l.templates)-1].depth--
+			}
+
+			token = newToken(tokens.RBRACE, l)
+		}
 	case '[':
 		token = newToken(tokens.LBRACKET, l)
 	case ']':
@@ -151,7 +240,7 @@ func (l *Lexer) NextToken() tokens.Token {
 		token = newTokenWithValue(tokens.EOF, l, "")
 
 	default:
-		if isLetter(l.ch) {
+		if isLetter(l.ch) || l.unicodeLetterSize() > 0 {
 			literal := l.readIdentifier()
 
 			if literal == "else" {
@@ -229,18 +318,104 @@ func newToken(tokenType tokens.TokenType, l *Lexer) tokens.Token {
 func (l *Lexer) readIdentifier() string {
 	position := l.position
 
-	for isLetter(l.ch) || isDigit(l.ch) {
-		l.readChar()
+	for {
+		if isLetter(l.ch) || isDigit(l.ch) {
+			l.readChar()
+			continue
+		}
+
+		size := l.unicodeLetterSize()
+		if size == 0 {
+			break
+		}
+
+		for range size {
+			l.readChar()
+		}
 	}
 
 	return l.input[position:l.position]
 }
 
-func (l *Lexer) readString(endChar byte) string {
+// unicodeLetterSize returns the number of bytes used by the non-ASCII letter
+// at the current position, or zero if the current character isn't one.
+func (l *Lexer) unicodeLetterSize() int {
+	if l.ch < utf8.RuneSelf || l.position >= len(l.input) {
+		return 0
+	}
+
+	r, size := utf8.DecodeRuneInString(l.input[l.position:])
+	if r == utf8.RuneError || !unicode.IsLetter(r) {
+		return 0
+	}
+
+	return size
+}
+
+// readStringToken reads the string at the current position, the token is
+// positioned at the start of the string rather than where it ended, since
+// strings can span multiple lines.
+func (l *Lexer) readStringToken() tokens.Token {
+	line, column := l.currentLine, l.currentColumn+1
+
+	var token tokens.Token
+
+	switch {
+	case l.ch == '`':
+		token = newTokenWithValue(tokens.STRING, l, l.readRawString())
+	case l.peekChar() == l.ch && l.peekCharAt(1) == l.ch:
+		triple := l.startTripleQuotedString(l.ch)
+
+		value, interpolated := l.readTripleQuotedString(triple, true)
+		if interpolated {
+			l.templates = append(l.templates, template{triple: triple})
+			token = newTokenWithValue(tokens.TEMPLATE_START, l, value)
+		} else {
+			token = newTokenWithValue(tokens.STRING, l, value)
+		}
+	case l.ch == '"':
+		value, interpolated := l.readString('"')
+		if interpolated {
+			l.templates = append(l.templates, template{})
+			token = newTokenWithValue(tokens.TEMPLATE_START, l, value)
+		} else {
+			token = newTokenWithValue(tokens.STRING, l, value)
+		}
+	default:
+		value, _ := l.readString(l.ch)
+		token = newTokenWithValue(tokens.STRING, l, value)
+	}
+
+	token.Line = line
+	token.Column = column
+
+	return token
+}
+
+// readString reads the string until the end character, double quoted
+// strings stops early at interpolations and returns true, leaving the
+// lexer at the opening brace of the interpolation.
+func (l *Lexer) readString(endChar byte) (string, bool) {
 	var result strings.Builder
 	l.readChar()
 
 	for l.ch != endChar && l.ch != 0 {
+		if endChar == '"' {
+			if l.ch == '$' && l.peekChar() == '{' {
+				l.readChar()
+
+				return l.escapeString(result.String()), true
+			}
+
+			if l.ch == '\\' && strings.HasPrefix(l.input[l.readPosition:], "${") {
+				l.readChar()
+				result.WriteByte(l.ch)
+				l.readChar()
+
+				continue
+			}
+		}
+
 		result.WriteByte(l.ch)
 
 		if l.ch == '\\' {
@@ -254,7 +429,164 @@ func (l *Lexer) readString(endChar byte) string {
 		l.readChar()
 	}
 
-	return l.escapeString(result.String())
+	return l.escapeString(result.String()), false
+}
+
+// readRawString reads the string until the closing backtick, raw strings have
+// no escape sequences or interpolations and are returned exactly as written.
+func (l *Lexer) readRawString() string {
+	l.readChar()
+	position := l.position
+
+	for l.ch != '`' && l.ch != 0 {
+		l.readChar()
+	}
+
+	return l.input[position:l.position]
+}
+
+// startTripleQuotedString skips past the opening quotes of a triple quoted
+// string, and the line break after them, after finding the indentation that
+// is common to the lines of the string, which is removed from each line. The
+// indentation before the closing quotes counts towards the indentation.
+func (l *Lexer) startTripleQuotedString(quote byte) *tripleQuote {
+	l.readChar()
+	l.readChar()
+	l.readChar()
+
+	end := l.position
+	for end < len(l.input) && !strings.HasPrefix(l.input[end:], strings.Repeat(string(quote), 3)) {
+		if l.input[end] == '\\' {
+			end++
+		}
+
+		end++
+	}
+
+	lines := strings.Split(l.input[l.position:min(end, len(l.input))], "\n")
+	triple := &tripleQuote{quote: quote, multiline: len(lines) > 1}
+	if !triple.multiline {
+		return triple
+	}
+
+	if isBlank(lines[0]) {
+		for l.ch != '\n' {
+			l.readChar()
+		}
+
+		l.readChar()
+		lines = lines[1:]
+	}
+
+	indent := ""
+	found := false
+
+	for i, line := range lines {
+		if isBlank(line) && i < len(lines)-1 {
+			continue
+		}
+
+		prefix := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found {
+			indent, found = prefix, true
+		} else {
+			indent = commonPrefix(indent, prefix)
+		}
+	}
+
+	triple.indent = indent
+
+	return triple
+}
+
+// readTripleQuotedString reads the next part of the triple quoted string,
+// leaving the lexer at the last of the closing quotes, or at the opening brace
+// of an interpolation, where it returns true. Escape sequences are supported,
+// and double quoted strings support interpolations like regular strings.
+func (l *Lexer) readTripleQuotedString(triple *tripleQuote, lineStart bool) (string, bool) {
+	quote := triple.quote
+	position := l.position
+
+	var result strings.Builder
+
+	for l.ch != 0 && !(l.ch == quote && l.peekChar() == quote && l.peekCharAt(1) == quote) {
+		if quote == '"' && l.ch == '$' && l.peekChar() == '{' {
+			result.WriteString(l.input[position:l.position])
+			l.readChar()
+
+			return l.escapeString(triple.trimIndentation(result.String(), lineStart, false)), true
+		}
+
+		if quote == '"' && l.ch == '\\' && strings.HasPrefix(l.input[l.readPosition:], "${") {
+			result.WriteString(l.input[position:l.position])
+			l.readChar()
+			position = l.position
+		}
+
+		if l.ch == '\\' && l.peekChar() != 0 {
+			l.readChar()
+		}
+
+		l.readChar()
+	}
+
+	result.WriteString(l.input[position:l.position])
+
+	if l.ch != 0 {
+		l.readChar()
+		l.readChar()
+	}
+
+	return l.escapeString(triple.trimIndentation(result.String(), lineStart, true)), false
+}
+
+// trimIndentation removes the common indentation from the lines within a part
+// of a triple quoted string, the first line of the part is only trimmed if the
+// part starts on a new line rather than after an interpolation. Blank lines
+// are emptied, and the line with the closing quotes is removed if it's blank.
+func (t *tripleQuote) trimIndentation(value string, lineStart, last bool) string {
+	if !t.multiline {
+		return value
+	}
+
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		if i == 0 && !lineStart {
+			continue
+		}
+
+		final := i == len(lines)-1
+		switch {
+		case final && last && isBlank(line):
+			lines = lines[:i]
+		case isBlank(line) && !final:
+			lines[i] = ""
+		default:
+			lines[i] = strings.TrimPrefix(line, t.indent)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (l *Lexer) readTemplateContinuation() tokens.Token {
+	var value string
+	var interpolated bool
+
+	if triple := l.templates[len(l.templates)-1].triple; triple != nil {
+		l.readChar()
+		value, interpolated = l.readTripleQuotedString(triple, false)
+	} else {
+		value, interpolated = l.readString('"')
+	}
+
+	if interpolated {
+		return newTokenWithValue(tokens.TEMPLATE_MIDDLE, l, value)
+	}
+
+	l.templates = l.templates[:len(l.templates)-1]
+
+	return newTokenWithValue(tokens.TEMPLATE_END, l, value)
 }
 
 func (l *Lexer) escapeString(val string) string {
@@ -272,6 +604,11 @@ func (l *Lexer) escapeString(val string) string {
 }
 
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.currentLine++
+		l.currentColumn = 0
+	}
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -284,35 +621,124 @@ func (l *Lexer) readChar() {
 }
 
 func (l *Lexer) readNumberToken() tokens.Token {
+	column := l.currentColumn
 	val := l.readNumber()
-	if strings.Contains(val, ".") {
-		return newTokenWithValue(tokens.FLOAT, l, val)
-	} else if strings.HasSuffix(val, "f") {
-		return newTokenWithValue(tokens.FLOAT, l, val[:len(val)-1])
+
+	var token tokens.Token
+
+	switch {
+	case isPrefixedNumber(val):
+		token = newTokenWithValue(tokens.INT, l, val)
+	case strings.HasSuffix(val, "d"):
+		token = newTokenWithValue(tokens.DECIMAL, l, val[:len(val)-1])
+	case strings.HasSuffix(val, "f"):
+		token = newTokenWithValue(tokens.FLOAT, l, val[:len(val)-1])
+	case strings.ContainsAny(val, ".eE"):
+		token = newTokenWithValue(tokens.FLOAT, l, val)
+	default:
+		token = newTokenWithValue(tokens.INT, l, val)
 	}
 
-	return newTokenWithValue(tokens.INT, l, val)
+	// The underscores and suffixes are removed from the literal, so the column
+	// is set to where the number started rather than using the literal length.
+	token.Column = column
+
+	return token
 }
 
 func (l *Lexer) readNumber() string {
 	position := l.position
 
+	// Hexadecimal, binary and octal numbers read every letter and digit after
+	// the prefix, so invalid digits become part of the number and are reported
+	// by the parser instead of being split into separate tokens.
+	if l.ch == '0' && isNumberPrefix(l.peekChar()) {
+		l.readChar()
+		l.readChar()
+
+		for isLetter(l.ch) || isDigit(l.ch) {
+			l.readChar()
+		}
+
+		return removeNumberSeparators(l.input[position:l.position])
+	}
+
 	for isDigit(l.ch) {
 		l.readChar()
 	}
 
-	switch l.ch {
-	case '.':
+	hasFraction := l.ch == '.' && l.peekChar() != '.'
+	if hasFraction {
 		l.readChar()
 
 		for isDigit(l.ch) {
 			l.readChar()
 		}
-	case 'f':
+	}
+
+	if (l.ch == 'e' || l.ch == 'E') && (!isLetter(l.peekChar()) || l.peekChar() == '_') {
 		l.readChar()
+
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+
+	if l.ch == 'f' && !hasFraction || l.isDecimalSuffix() {
+		l.readChar()
+	}
+
+	return removeNumberSeparators(l.input[position:l.position])
+}
+
+// removeNumberSeparators removes the underscores used to separate the digits
+// of the number literal, misplaced underscores are kept in the literal so the
+// parser can report them, underscores must be placed between two digits, or
+// right after the prefix of hexadecimal, binary and octal numbers.
+func removeNumberSeparators(literal string) string {
+	prefixed := isPrefixedNumber(literal)
+
+	for i := 1; i < len(literal); i++ {
+		if literal[i] != '_' {
+			continue
+		}
+
+		if i+1 == len(literal) || literal[i-1] == '_' || literal[i+1] == '_' {
+			return literal
+		}
+
+		if !prefixed && (!isDecimalDigit(literal[i-1]) || !isDecimalDigit(literal[i+1])) {
+			return literal
+		}
 	}
 
-	return strings.ReplaceAll(l.input[position:l.position], "_", "")
+	return strings.ReplaceAll(literal, "_", "")
+}
+
+// isDecimalSuffix checks if the current character is the `d` suffix used by
+// decimal literals, like 19.99d, rather than the start of an identifier.
+func (l *Lexer) isDecimalSuffix() bool {
+	return l.ch == 'd' && !isLetter(l.peekChar()) && !isDigit(l.peekChar())
+}
+
+// followsWhitespace checks if the current character is preceded by whitespace,
+// used to tell optional chaining like a?.b and a?[0] apart from ternaries
+// like cond ? [a] : [b], where the question mark is separated from the value.
+func (l *Lexer) followsWhitespace() bool {
+	if l.position == 0 {
+		return false
+	}
+
+	switch l.input[l.position-1] {
+	case ' ', '\t', '\n', '\r':
+		return true
+	}
+
+	return false
 }
 
 func (l *Lexer) peekChar() byte {
@@ -323,13 +749,16 @@ func (l *Lexer) peekChar() byte {
 	return l.input[l.readPosition]
 }
 
+func (l *Lexer) peekCharAt(offset int) byte {
+	if l.readPosition+offset >= len(l.input) {
+		return 0
+	}
+
+	return l.input[l.readPosition+offset]
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		if l.ch == '\n' {
-			l.currentLine++
-			l.currentColumn = 0
-		}
-
 		l.readChar()
 	}
 }
@@ -354,10 +783,37 @@ func isDynamicToken(token tokens.TokenType) bool {
 	return token == tokens.IDENT || token == tokens.INT
 }
 
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// commonPrefix returns the longest prefix the two strings have in common,
+// comparing the characters exactly, so tabs and spaces never match.
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return a[:i]
+}
+
 func isLetter(ch byte) bool {
 	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
 }
 
+func isNumberPrefix(ch byte) bool {
+	return ch == 'x' || ch == 'X' || ch == 'b' || ch == 'B' || ch == 'o' || ch == 'O'
+}
+
+func isPrefixedNumber(value string) bool {
+	return len(value) > 1 && value[0] == '0' && isNumberPrefix(value[1])
+}
+
 func isDigit(ch byte) bool {
 	return '0' <= ch && ch <= '9' || ch == '_'
 }
+
+func isDecimalDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
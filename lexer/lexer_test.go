@@ -11,6 +11,8 @@ func TestNextToken(t *testing.T) {
 		var mut five = 5;
 		var pie = 3.14;
 		var val = 9f;
+		var price = 19.99d;
+		var cents = 5d;
 		var nil = null;
 
 		var add = func(x, y) {
@@ -36,15 +38,34 @@ func TestNextToken(t *testing.T) {
 			break;
 		}
 
+		for (k, v in items) {
+			continue;
+		}
+
+		try { throw e; } catch (e) {}
+
+		match (x) { _ => 1 }
+		struct User { name }
+		enum Status { Active, Banned(reason) }
+		func* gen() { yield 1; }
+		spawn work(1);
+		async func() { await x; };
+		f(...args);
+		1..5 1..<5 1.5;
+
 		"one-word";
 		"multiple words";
 		'one-word';
 		'multiple words';
+		"Hi ${name}, ${ {"a": 1} }";
 
 		=+-!*^%/<>
 		== !=;
 		<= >=;
 		&& ||;
+		& | ~ << >>;
+		? ??;
+		a?.b?[0];
 
 		++ --;
 
@@ -78,6 +99,17 @@ func TestNextToken(t *testing.T) {
 		{tokens.ASSIGN, "="},
 		{tokens.FLOAT, "9"},
 		{tokens.SEMICOLON, ";"},
+		// Variable assignment to decimals
+		{tokens.VARIABLE, "var"},
+		{tokens.IDENT, "price"},
+		{tokens.ASSIGN, "="},
+		{tokens.DECIMAL, "19.99"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.VARIABLE, "var"},
+		{tokens.IDENT, "cents"},
+		{tokens.ASSIGN, "="},
+		{tokens.DECIMAL, "5"},
+		{tokens.SEMICOLON, ";"},
 		// Variable assignment to nil
 		{tokens.VARIABLE, "var"},
 		{tokens.IDENT, "nil"},
@@ -170,6 +202,104 @@ func TestNextToken(t *testing.T) {
 		{tokens.BREAK_LOOP, "break"},
 		{tokens.SEMICOLON, ";"},
 		{tokens.RBRACE, "}"},
+		// For loops
+		{tokens.FOR, "for"},
+		{tokens.LPAREN, "("},
+		{tokens.IDENT, "k"},
+		{tokens.COMMA, ","},
+		{tokens.IDENT, "v"},
+		{tokens.IN, "in"},
+		{tokens.IDENT, "items"},
+		{tokens.RPAREN, ")"},
+		{tokens.LBRACE, "{"},
+		{tokens.CONTINUE_LOOP, "continue"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.RBRACE, "}"},
+		// Try & catch statements
+		{tokens.TRY, "try"},
+		{tokens.LBRACE, "{"},
+		{tokens.THROW, "throw"},
+		{tokens.IDENT, "e"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.RBRACE, "}"},
+		{tokens.CATCH, "catch"},
+		{tokens.LPAREN, "("},
+		{tokens.IDENT, "e"},
+		{tokens.RPAREN, ")"},
+		{tokens.LBRACE, "{"},
+		{tokens.RBRACE, "}"},
+		// Match expressions
+		{tokens.MATCH, "match"},
+		{tokens.LPAREN, "("},
+		{tokens.IDENT, "x"},
+		{tokens.RPAREN, ")"},
+		{tokens.LBRACE, "{"},
+		{tokens.IDENT, "_"},
+		{tokens.ARROW, "=>"},
+		{tokens.INT, "1"},
+		{tokens.RBRACE, "}"},
+		// Structs
+		{tokens.STRUCT, "struct"},
+		{tokens.IDENT, "User"},
+		{tokens.LBRACE, "{"},
+		{tokens.IDENT, "name"},
+		{tokens.RBRACE, "}"},
+		// Enums
+		{tokens.ENUM, "enum"},
+		{tokens.IDENT, "Status"},
+		{tokens.LBRACE, "{"},
+		{tokens.IDENT, "Active"},
+		{tokens.COMMA, ","},
+		{tokens.IDENT, "Banned"},
+		{tokens.LPAREN, "("},
+		{tokens.IDENT, "reason"},
+		{tokens.RPAREN, ")"},
+		{tokens.RBRACE, "}"},
+		// Generators
+		{tokens.FUNCTION, "func"},
+		{tokens.ASTERISK, "*"},
+		{tokens.IDENT, "gen"},
+		{tokens.LPAREN, "("},
+		{tokens.RPAREN, ")"},
+		{tokens.LBRACE, "{"},
+		{tokens.YIELD, "yield"},
+		{tokens.INT, "1"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.RBRACE, "}"},
+		// Tasks
+		{tokens.SPAWN, "spawn"},
+		{tokens.IDENT, "work"},
+		{tokens.LPAREN, "("},
+		{tokens.INT, "1"},
+		{tokens.RPAREN, ")"},
+		{tokens.SEMICOLON, ";"},
+		// Async functions
+		{tokens.ASYNC, "async"},
+		{tokens.FUNCTION, "func"},
+		{tokens.LPAREN, "("},
+		{tokens.RPAREN, ")"},
+		{tokens.LBRACE, "{"},
+		{tokens.AWAIT, "await"},
+		{tokens.IDENT, "x"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.RBRACE, "}"},
+		{tokens.SEMICOLON, ";"},
+		// Spread operator
+		{tokens.IDENT, "f"},
+		{tokens.LPAREN, "("},
+		{tokens.ELLIPSIS, "..."},
+		{tokens.IDENT, "args"},
+		{tokens.RPAREN, ")"},
+		{tokens.SEMICOLON, ";"},
+		// Ranges
+		{tokens.INT, "1"},
+		{tokens.RANGE, ".."},
+		{tokens.INT, "5"},
+		{tokens.INT, "1"},
+		{tokens.RANGE_EX, "..<"},
+		{tokens.INT, "5"},
+		{tokens.FLOAT, "1.5"},
+		{tokens.SEMICOLON, ";"},
 		// String literals
 		{tokens.STRING, "one-word"},
 		{tokens.SEMICOLON, ";"},
@@ -179,6 +309,17 @@ func TestNextToken(t *testing.T) {
 		{tokens.SEMICOLON, ";"},
 		{tokens.STRING, "multiple words"},
 		{tokens.SEMICOLON, ";"},
+		// Interpolated string literals
+		{tokens.TEMPLATE_START, "Hi "},
+		{tokens.IDENT, "name"},
+		{tokens.TEMPLATE_MIDDLE, ", "},
+		{tokens.LBRACE, "{"},
+		{tokens.STRING, "a"},
+		{tokens.COLON, ":"},
+		{tokens.INT, "1"},
+		{tokens.RBRACE, "}"},
+		{tokens.TEMPLATE_END, ""},
+		{tokens.SEMICOLON, ";"},
 		// Expression operators
 		{tokens.ASSIGN, "="},
 		{tokens.PLUS, "+"},
@@ -199,6 +340,25 @@ func TestNextToken(t *testing.T) {
 		{tokens.AND, "&&"},
 		{tokens.OR, "||"},
 		{tokens.SEMICOLON, ";"},
+		// Bitwise operators
+		{tokens.BIT_AND, "&"},
+		{tokens.BIT_OR, "|"},
+		{tokens.TILDE, "~"},
+		{tokens.SHIFT_LEFT, "<<"},
+		{tokens.SHIFT_RIGHT, ">>"},
+		{tokens.SEMICOLON, ";"},
+		// Conditional operators
+		{tokens.QUESTION, "?"},
+		{tokens.NULL_COALESCE, "??"},
+		{tokens.SEMICOLON, ";"},
+		// Optional chaining operators
+		{tokens.IDENT, "a"},
+		{tokens.OPTIONAL_CHAIN, "?."},
+		{tokens.IDENT, "b"},
+		{tokens.OPTIONAL_INDEX, "?["},
+		{tokens.INT, "0"},
+		{tokens.RBRACKET, "]"},
+		{tokens.SEMICOLON, ";"},
 		// Increment & Decrement
 		{tokens.INCREMENT, "++"},
 		{tokens.DECREMENT, "--"},
@@ -322,3 +482,246 @@ func TestNextTokenEscapedString(t *testing.T) {
 		t.Fatalf("expected EOF token at end of input, got %q (value: %q)", tok.Type, tok.Literal)
 	}
 }
+
+func TestNextTokenMultiLineStrings(t *testing.T) {
+	input := "var a = `raw\\n\n${b}`;\n" +
+		"var c = \"\"\"\n" +
+		"    one\n" +
+		"      two\\t\n" +
+		"    \"\"\";\n" +
+		"'''it's \"quoted\"''' d"
+
+	tests := []struct {
+		expectedType    tokens.TokenType
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{tokens.VARIABLE, "var", 1, 1},
+		{tokens.IDENT, "a", 1, 5},
+		{tokens.ASSIGN, "=", 1, 7},
+		{tokens.STRING, "raw\\n\n${b}", 1, 10},
+		{tokens.SEMICOLON, ";", 2, 6},
+		{tokens.VARIABLE, "var", 3, 1},
+		{tokens.IDENT, "c", 3, 5},
+		{tokens.ASSIGN, "=", 3, 7},
+		{tokens.STRING, "one\n  two\t", 3, 10},
+		{tokens.SEMICOLON, ";", 6, 8},
+		{tokens.STRING, `it's "quoted"`, 7, 2},
+		{tokens.IDENT, "d", 7, 21},
+		{tokens.EOF, "", 7, 22},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong.\nexpected %q,\ngot %q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong.\nexpected %q,\ngot %q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf(
+				"tests[%d] - position wrong.\nexpected %d:%d,\ngot %d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column,
+			)
+		}
+	}
+}
+
+func TestTrimIndentation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"single line ", "single line "},
+		{"\n    one\n    two\n    ", "one\ntwo"},
+		{"\n    one\n\n      two\n    ", "one\n\n  two"},
+		{"\n        one\n    ", "    one"},
+		{"\n\tone\n\t\ttwo", "one\n\ttwo"},
+		{"\n    ", ""},
+		{"\n\t  one\n  \ttwo\n", "\t  one\n  \ttwo"},
+		{"\n  \tone\n  two\n  ", "\tone\ntwo"},
+	}
+
+	for _, tt := range tests {
+		input := `"""` + tt.input + `"""`
+		if tok := New(input).NextToken(); tok.Literal != tt.expected {
+			t.Errorf("New(%q).NextToken() = %q, want %q", input, tok.Literal, tt.expected)
+		}
+	}
+}
+
+func TestNextTokenTripleQuotedTemplates(t *testing.T) {
+	input := "\"\"\"\n    Hi ${name},\n\n      ${ {\"a\": 1}.a } \\${total}\n    \"\"\"; '''${name}'''"
+
+	tests := []struct {
+		expectedType    tokens.TokenType
+		expectedLiteral string
+	}{
+		{tokens.TEMPLATE_START, "Hi "},
+		{tokens.IDENT, "name"},
+		{tokens.TEMPLATE_MIDDLE, ",\n\n  "},
+		{tokens.LBRACE, "{"},
+		{tokens.STRING, "a"},
+		{tokens.COLON, ":"},
+		{tokens.INT, "1"},
+		{tokens.RBRACE, "}"},
+		{tokens.PERIOD, "."},
+		{tokens.IDENT, "a"},
+		{tokens.TEMPLATE_END, " ${total}"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.STRING, "${name}"},
+		{tokens.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong.\nexpected %q,\ngot %q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong.\nexpected %q,\ngot %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenUnicodeIdentifiers(t *testing.T) {
+	input := `var größe = 名前 + café_2;`
+
+	tests := []struct {
+		expectedType    tokens.TokenType
+		expectedLiteral string
+	}{
+		{tokens.VARIABLE, "var"},
+		{tokens.IDENT, "größe"},
+		{tokens.ASSIGN, "="},
+		{tokens.IDENT, "名前"},
+		{tokens.PLUS, "+"},
+		{tokens.IDENT, "café_2"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong.\nexpected %q,\ngot %q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong.\nexpected %q,\ngot %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenOptionalChainingAndTernaries(t *testing.T) {
+	input := "a?.b?[0]; cond ?[1] : [2]; cond ? .b : c"
+
+	tests := []struct {
+		expectedType    tokens.TokenType
+		expectedLiteral string
+	}{
+		{tokens.IDENT, "a"},
+		{tokens.OPTIONAL_CHAIN, "?."},
+		{tokens.IDENT, "b"},
+		{tokens.OPTIONAL_INDEX, "?["},
+		{tokens.INT, "0"},
+		{tokens.RBRACKET, "]"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.IDENT, "cond"},
+		{tokens.QUESTION, "?"},
+		{tokens.LBRACKET, "["},
+		{tokens.INT, "1"},
+		{tokens.RBRACKET, "]"},
+		{tokens.COLON, ":"},
+		{tokens.LBRACKET, "["},
+		{tokens.INT, "2"},
+		{tokens.RBRACKET, "]"},
+		{tokens.SEMICOLON, ";"},
+		{tokens.IDENT, "cond"},
+		{tokens.QUESTION, "?"},
+		{tokens.PERIOD, "."},
+		{tokens.IDENT, "b"},
+		{tokens.COLON, ":"},
+		{tokens.IDENT, "c"},
+		{tokens.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong.\nexpected %q,\ngot %q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong.\nexpected %q,\ngot %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenNumbers(t *testing.T) {
+	input := `0xFF 0XaB 0b1010 0o755 1_000_000 1.5e-3 2E3 1e+2f 1.5e2d 0x_FF 0b102 0x 1e 1__0 2_ 3_.5 4e_1`
+
+	l := New(input)
+
+	tests := []struct {
+		expectedType    tokens.TokenType
+		expectedLiteral string
+		expectedColumn  int
+	}{
+		{tokens.INT, "0xFF", 1},
+		{tokens.INT, "0XaB", 6},
+		{tokens.INT, "0b1010", 11},
+		{tokens.INT, "0o755", 18},
+		{tokens.INT, "1000000", 24},
+		{tokens.FLOAT, "1.5e-3", 34},
+		{tokens.FLOAT, "2E3", 41},
+		{tokens.FLOAT, "1e+2", 45},
+		{tokens.DECIMAL, "1.5e2", 51},
+		{tokens.INT, "0xFF", 58},
+		{tokens.INT, "0b102", 64},
+		{tokens.INT, "0x", 70},
+		{tokens.FLOAT, "1e", 73},
+		{tokens.INT, "1__0", 76},
+		{tokens.INT, "2_", 81},
+		{tokens.FLOAT, "3_.5", 84},
+		{tokens.FLOAT, "4e_1", 89},
+	}
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong.\nexpected %q,\ngot %q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong.\nexpected %q,\ngot %q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - column wrong.\nexpected %d,\ngot %d", i, tt.expectedColumn, tok.Column)
+		}
+	}
+
+	tok := l.NextToken()
+	if tok.Type != tokens.EOF {
+		t.Fatalf("expected EOF token at end of input, got %q (value: %q)", tok.Type, tok.Literal)
+	}
+}
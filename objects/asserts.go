@@ -2,7 +2,10 @@ package objects
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
+
+	"github.com/senither/zen-lang/objects/decimal"
 )
 
 func AssertExpectedObject(t *testing.T, expected any, actual Object) {
@@ -19,6 +22,16 @@ func AssertExpectedObject(t *testing.T, expected any, actual Object) {
 		if err != nil {
 			t.Errorf("integer assertion failed: %s", err)
 		}
+	case *big.Int:
+		err := AssertBigInt(expected, actual)
+		if err != nil {
+			t.Errorf("big integer assertion failed: %s", err)
+		}
+	case decimal.Decimal:
+		err := AssertDecimal(expected, actual)
+		if err != nil {
+			t.Errorf("decimal assertion failed: %s", err)
+		}
 	case float64:
 		err := AssertFloat(expected, actual)
 		if err != nil {
@@ -142,6 +155,32 @@ func AssertInteger(expected int64, actual Object) error {
 	return nil
 }
 
+func AssertBigInt(expected *big.Int, actual Object) error {
+	result, ok := actual.(*BigInt)
+	if !ok {
+		return fmt.Errorf("object is not BigInt. got %T (%+v)", actual, actual)
+	}
+
+	if result.Value.Cmp(expected) != 0 {
+		return fmt.Errorf("object has wrong value. got %s, want %s", result.Value, expected)
+	}
+
+	return nil
+}
+
+func AssertDecimal(expected decimal.Decimal, actual Object) error {
+	result, ok := actual.(*Decimal)
+	if !ok {
+		return fmt.Errorf("object is not Decimal. got %T (%+v)", actual, actual)
+	}
+
+	if result.Value.String() != expected.String() {
+		return fmt.Errorf("object has wrong value. got %s, want %s", result.Value, expected)
+	}
+
+	return nil
+}
+
 func AssertFloat(expected float64, actual Object) error {
 	result, ok := actual.(*Float)
 	if !ok {
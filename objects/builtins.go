@@ -2,9 +2,15 @@ package objects
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/senither/zen-lang/ast"
+	"github.com/senither/zen-lang/objects/decimal"
 )
 
 var Builtins = []BuiltinDefinition{
@@ -40,7 +46,7 @@ var Builtins = []BuiltinDefinition{
 
 			switch arg := args[0].(type) {
 			case *String:
-				return &Integer{Value: int64(len(arg.Value))}, nil
+				return &Integer{Value: int64(utf8.RuneCountInString(arg.Value))}, nil
 			case *Array:
 				return &Integer{Value: int64(len(arg.Elements))}, nil
 			case *Null:
@@ -67,7 +73,7 @@ var Builtins = []BuiltinDefinition{
 	{
 		Name: "int",
 		Schema: BuiltinSchema{
-			NewRequiredArgument(INTEGER_OBJ, FLOAT_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ),
+			NewRequiredArgument(INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ),
 		},
 		Builtin: &Builtin{Fn: func(args ...Object) (Object, error) {
 			if len(args) != 1 {
@@ -75,10 +81,17 @@ var Builtins = []BuiltinDefinition{
 			}
 
 			switch arg := args[0].(type) {
-			case *Integer:
+			case *Integer, *BigInt:
 				return arg, nil
 			case *Float:
-				return &Integer{Value: int64(arg.Value)}, nil
+				if math.IsNaN(arg.Value) || math.IsInf(arg.Value, 0) {
+					return nil, NewErrorf("int", "failed to convert `%s` to %s", arg.Inspect(), INTEGER_OBJ)
+				}
+
+				value, _ := big.NewFloat(arg.Value).Int(nil)
+				return WrapBigIntValue(value), nil
+			case *Decimal:
+				return WrapBigIntValue(arg.Value.Truncate()), nil
 			case *Boolean:
 				if arg.Value {
 					return &Integer{Value: 1}, nil
@@ -88,17 +101,22 @@ var Builtins = []BuiltinDefinition{
 			case *Null:
 				return &Integer{Value: 0}, nil
 			case *String:
-				var intValue int64
-				_, err := fmt.Sscan(arg.Value, &intValue)
-				if err != nil {
+				if value, ok := parseIntegerString(arg.Value); ok {
+					return value, nil
+				}
+
+				// Floats written as strings are truncated like other floats.
+				floatValue, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+				if err != nil || math.IsNaN(floatValue) || math.IsInf(floatValue, 0) {
 					return nil, NewErrorf("int", "failed to convert `%s` to %s", arg.Value, INTEGER_OBJ)
 				}
 
-				return &Integer{Value: intValue}, nil
+				value, _ := big.NewFloat(floatValue).Int(nil)
+				return WrapBigIntValue(value), nil
 
 			default:
 				return nil, NewInvalidArgumentTypesError("int", []ObjectType{
-					INTEGER_OBJ, FLOAT_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ,
+					INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ,
 				}, 0, args)
 			}
 		}},
@@ -106,7 +124,7 @@ var Builtins = []BuiltinDefinition{
 	{
 		Name: "float",
 		Schema: BuiltinSchema{
-			NewRequiredArgument(INTEGER_OBJ, FLOAT_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ),
+			NewRequiredArgument(INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ),
 		},
 		Builtin: &Builtin{Fn: func(args ...Object) (Object, error) {
 			if len(args) != 1 {
@@ -116,8 +134,8 @@ var Builtins = []BuiltinDefinition{
 			switch arg := args[0].(type) {
 			case *Float:
 				return arg, nil
-			case *Integer:
-				return &Float{Value: float64(arg.Value)}, nil
+			case *Integer, *BigInt, *Decimal:
+				return &Float{Value: UnwrapNumberValue(arg)}, nil
 			case *Boolean:
 				if arg.Value {
 					return &Float{Value: 1}, nil
@@ -127,19 +145,80 @@ var Builtins = []BuiltinDefinition{
 			case *Null:
 				return &Float{Value: 0}, nil
 			case *String:
-				var intValue float64
-				_, err := fmt.Sscan(arg.Value, &intValue)
-				if err != nil {
-					return nil, NewErrorf("float", "failed to convert `%s` to %s", arg.Value, FLOAT_OBJ)
+				floatValue, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+				if err == nil {
+					return &Float{Value: floatValue}, nil
+				}
+
+				// Hexadecimal, binary and octal integers aren't supported by
+				// ParseFloat, so they're parsed as integers and converted.
+				if value, ok := parseIntegerString(arg.Value); ok {
+					return &Float{Value: UnwrapNumberValue(value)}, nil
 				}
 
-				return &Float{Value: float64(intValue)}, nil
+				return nil, NewErrorf("float", "failed to convert `%s` to %s", arg.Value, FLOAT_OBJ)
 
 			default:
 				return nil, NewInvalidArgumentTypesError("float", []ObjectType{
-					INTEGER_OBJ, FLOAT_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ,
+					INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ,
+				}, 0, args)
+			}
+		}},
+	},
+	{
+		Name: "decimal",
+		Schema: BuiltinSchema{
+			NewRequiredArgument(INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ),
+			NewOptionalArgument(INTEGER_OBJ),
+			NewOptionalArgument(STRING_OBJ),
+		},
+		Builtin: &Builtin{Fn: func(args ...Object) (Object, error) {
+			if len(args) == 0 {
+				return nil, NewWrongNumberOfArgumentsWantAtLeastError("decimal", 1, len(args))
+			}
+
+			if len(args) > 3 {
+				return nil, NewWrongNumberOfArgumentsWantAtMostError("decimal", 3, len(args))
+			}
+
+			var value decimal.Decimal
+
+			switch arg := args[0].(type) {
+			case *Integer, *BigInt, *Float, *Decimal:
+				var err error
+				if value, err = UnwrapDecimalValue(arg); err != nil {
+					return nil, NewErrorf("decimal", "failed to convert `%s` to %s", arg.Inspect(), DECIMAL_OBJ)
+				}
+			case *Boolean:
+				if arg.Value {
+					value = decimal.FromInt64(1)
+				} else {
+					value = decimal.FromInt64(0)
+				}
+			case *Null:
+				value = decimal.FromInt64(0)
+			case *String:
+				var err error
+				if value, err = decimal.Parse(arg.Value); err != nil {
+					return nil, NewErrorf("decimal", "failed to convert `%s` to %s", arg.Value, DECIMAL_OBJ)
+				}
+
+			default:
+				return nil, NewInvalidArgumentTypesError("decimal", []ObjectType{
+					INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ, STRING_OBJ, BOOLEAN_OBJ, NULL_OBJ,
 				}, 0, args)
 			}
+
+			if len(args) > 1 {
+				places, mode, err := parseRoundingArguments("decimal", args, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				value = value.Round(places, mode)
+			}
+
+			return &Decimal{Value: value}, nil
 		}},
 	},
 	{
@@ -151,8 +230,12 @@ var Builtins = []BuiltinDefinition{
 			}
 
 			switch args[0].Type() {
-			case FUNCTION_OBJ, BUILTIN_OBJ, COMPILED_FUNCTION_OBJ, CLOSURE_OBJ:
+			case FUNCTION_OBJ, BUILTIN_OBJ, COMPILED_FUNCTION_OBJ, CLOSURE_OBJ, BOUND_METHOD_OBJ:
 				return &String{Value: "FUNCTION"}, nil
+			case STRUCT_INSTANCE_OBJ:
+				return &String{Value: args[0].(*StructInstance).Struct.Name}, nil
+			case ENUM_VARIANT_OBJ:
+				return &String{Value: args[0].(*EnumVariant).Enum.Name}, nil
 
 			default:
 				_, ok := args[0].(Callable)
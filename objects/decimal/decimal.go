@@ -0,0 +1,361 @@
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DIVISION_PRECISION is the number of decimal places divisions are calculated
+// with, the result is then trimmed of any trailing zeros beyond the places
+// of the numbers that were divided.
+const DIVISION_PRECISION = 16
+
+// MAX_COEFFICIENT_BITS limits the size of the decimals created by exponents,
+// which can otherwise create decimals too large to fit in memory.
+const MAX_COEFFICIENT_BITS = 1 << 20
+
+type RoundingMode int
+
+const (
+	HalfUp RoundingMode = iota
+	HalfDown
+	HalfEven
+	Up
+	Down
+	Ceiling
+	Floor
+)
+
+var roundingModes = map[string]RoundingMode{
+	"half_up":   HalfUp,
+	"half_down": HalfDown,
+	"half_even": HalfEven,
+	"up":        Up,
+	"down":      Down,
+	"ceiling":   Ceiling,
+	"floor":     Floor,
+}
+
+// ParseRoundingMode looks up the rounding mode by its name, like "half_up".
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	mode, ok := roundingModes[name]
+	if !ok {
+		return HalfUp, fmt.Errorf("unknown rounding mode: %q", name)
+	}
+
+	return mode, nil
+}
+
+func (m RoundingMode) String() string {
+	for name, mode := range roundingModes {
+		if mode == m {
+			return name
+		}
+	}
+
+	return "unknown"
+}
+
+// Decimal is an exact decimal number, stored as an integer coefficient and
+// the number of digits after the decimal point, so 19.99 is stored as the
+// coefficient 1999 with a scale of 2. Decimals are never modified in place.
+type Decimal struct {
+	coefficient *big.Int
+	scale       int32
+}
+
+func New(coefficient *big.Int, scale int32) Decimal {
+	return Decimal{coefficient: coefficient, scale: scale}
+}
+
+func FromInt(value *big.Int) Decimal {
+	return Decimal{coefficient: new(big.Int).Set(value)}
+}
+
+func FromInt64(value int64) Decimal {
+	return Decimal{coefficient: big.NewInt(value)}
+}
+
+// FromFloat converts the float to the shortest decimal that represents it,
+// so 0.1 is converted to exactly 0.1 rather than the float's binary value.
+func FromFloat(value float64) (Decimal, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Decimal{}, fmt.Errorf("cannot convert %v to a decimal", value)
+	}
+
+	return Parse(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// Parse parses a decimal from a string like "19.99", "-0.5" or "1.5e-3".
+func Parse(value string) (Decimal, error) {
+	text := strings.TrimSpace(value)
+	mantissa, exponentText, hasExponent := strings.Cut(strings.ToLower(text), "e")
+	integer, fraction, _ := strings.Cut(strings.TrimLeft(mantissa, "+-"), ".")
+
+	if integer == "" && fraction == "" || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q", value)
+	}
+
+	exponent := 0
+	if hasExponent {
+		var err error
+		if exponent, err = strconv.Atoi(exponentText); err != nil || exponent < math.MinInt16 || exponent > math.MaxInt16 {
+			return Decimal{}, fmt.Errorf("invalid decimal: %q", value)
+		}
+	}
+
+	coefficient, _ := new(big.Int).SetString("0"+integer+fraction, 10)
+	if strings.HasPrefix(mantissa, "-") {
+		coefficient.Neg(coefficient)
+	}
+
+	// Positive exponents beyond the fraction move the decimal point past the
+	// digits, which are padded with zeros since the scale can't be negative.
+	scale := len(fraction) - exponent
+	if scale < 0 {
+		coefficient.Mul(coefficient, pow10(int32(-scale)))
+		scale = 0
+	}
+
+	return Decimal{coefficient: coefficient, scale: int32(scale)}, nil
+}
+
+// MustParse is like Parse, but panics if the decimal can't be parsed.
+func MustParse(value string) Decimal {
+	d, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func isDigits(value string) bool {
+	for _, ch := range value {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (d Decimal) Coefficient() *big.Int { return d.coefficient }
+func (d Decimal) Scale() int32          { return d.scale }
+func (d Decimal) Sign() int             { return d.coefficient.Sign() }
+
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.coefficient).String()
+	if len(digits) <= int(d.scale) {
+		digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+	}
+
+	if d.scale > 0 {
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+
+	if d.coefficient.Sign() < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// Normalize removes any trailing zeros after the decimal point, so decimals
+// with the same value always have the same representation.
+func (d Decimal) Normalize() Decimal {
+	return d.trim(0)
+}
+
+func (d Decimal) trim(minScale int32) Decimal {
+	coefficient := new(big.Int).Set(d.coefficient)
+	scale := d.scale
+
+	ten := big.NewInt(10)
+	quotient, remainder := new(big.Int), new(big.Int)
+
+	for scale > minScale {
+		quotient.QuoRem(coefficient, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+
+		coefficient.Set(quotient)
+		scale--
+	}
+
+	return Decimal{coefficient: coefficient, scale: scale}
+}
+
+func (d Decimal) rescale(scale int32) *big.Int {
+	return new(big.Int).Mul(d.coefficient, pow10(scale-d.scale))
+}
+
+func pow10(exponent int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+func (d Decimal) Float64() float64 {
+	value, _ := new(big.Rat).SetFrac(d.coefficient, pow10(d.scale)).Float64()
+	return value
+}
+
+// Truncate returns the integer part of the decimal.
+func (d Decimal) Truncate() *big.Int {
+	return new(big.Int).Quo(d.coefficient, pow10(d.scale))
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{coefficient: new(big.Int).Neg(d.coefficient), scale: d.scale}
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+
+	return Decimal{coefficient: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+
+	return Decimal{coefficient: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coefficient: new(big.Int).Mul(d.coefficient, other.coefficient), scale: d.scale + other.scale}
+}
+
+// Div divides the decimals using the default division precision, trailing
+// zeros are trimmed down to the places of the most precise of the decimals.
+func (d Decimal) Div(other Decimal) (Decimal, error) {
+	result, err := d.DivRound(other, max(DIVISION_PRECISION, d.scale, other.scale), HalfUp)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return result.trim(max(d.scale, other.scale)), nil
+}
+
+// DivRound divides the decimals, rounding the result to the given number of
+// decimal places using the rounding mode.
+func (d Decimal) DivRound(other Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	if other.Sign() == 0 {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+
+	numerator := new(big.Int).Set(d.coefficient)
+	denominator := new(big.Int).Set(other.coefficient)
+
+	// The quotient is scaled so it has the requested number of places, the
+	// exponent can be negative, so the denominator is scaled up instead.
+	exponent := places + other.scale - d.scale
+	if exponent >= 0 {
+		numerator.Mul(numerator, pow10(exponent))
+	} else {
+		denominator.Mul(denominator, pow10(-exponent))
+	}
+
+	return Decimal{coefficient: roundQuotient(numerator, denominator, mode), scale: places}, nil
+}
+
+// Mod returns the remainder of dividing the decimals, the result has the same
+// sign as the dividend.
+func (d Decimal) Mod(other Decimal) (Decimal, error) {
+	if other.Sign() == 0 {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+
+	scale := max(d.scale, other.scale)
+
+	return Decimal{coefficient: new(big.Int).Rem(d.rescale(scale), other.rescale(scale)), scale: scale}, nil
+}
+
+// Pow raises the decimal to the integer exponent, negative exponents divide
+// one by the decimal raised to the positive exponent.
+func (d Decimal) Pow(exponent int64) (Decimal, error) {
+	if exponent < 0 {
+		result, err := d.Pow(-exponent)
+		if err != nil {
+			return Decimal{}, err
+		}
+
+		return FromInt64(1).Div(result)
+	}
+
+	bits := int64(d.coefficient.BitLen())
+	if bits > 1 && exponent > MAX_COEFFICIENT_BITS/bits || int64(d.scale)*exponent > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal overflow: %s ^ %d is too large", d, exponent)
+	}
+
+	return Decimal{
+		coefficient: new(big.Int).Exp(d.coefficient, big.NewInt(exponent), nil),
+		scale:       d.scale * int32(exponent),
+	}, nil
+}
+
+// Round rounds the decimal to the given number of decimal places using the
+// rounding mode, decimals with fewer places are returned as they are, negative
+// places round to the left of the decimal point, so -2 rounds to the hundreds.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places >= d.scale {
+		return d
+	}
+
+	if places < 0 {
+		rounded := roundQuotient(d.coefficient, pow10(d.scale-places), mode)
+		return Decimal{coefficient: rounded.Mul(rounded, pow10(-places))}
+	}
+
+	return Decimal{coefficient: roundQuotient(d.coefficient, pow10(d.scale-places), mode), scale: places}
+}
+
+// roundQuotient divides the numerator by the denominator, rounding the result
+// to an integer using the rounding mode.
+func roundQuotient(numerator, denominator *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	negative := (numerator.Sign() < 0) != (denominator.Sign() < 0)
+	half := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1).CmpAbs(denominator)
+
+	var awayFromZero bool
+
+	switch mode {
+	case HalfUp:
+		awayFromZero = half >= 0
+	case HalfDown:
+		awayFromZero = half > 0
+	case HalfEven:
+		awayFromZero = half > 0 || half == 0 && quotient.Bit(0) == 1
+	case Up:
+		awayFromZero = true
+	case Down:
+		awayFromZero = false
+	case Ceiling:
+		awayFromZero = !negative
+	case Floor:
+		awayFromZero = negative
+	}
+
+	if !awayFromZero {
+		return quotient
+	}
+
+	if negative {
+		return quotient.Sub(quotient, big.NewInt(1))
+	}
+
+	return quotient.Add(quotient, big.NewInt(1))
+}
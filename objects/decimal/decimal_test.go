@@ -0,0 +1,247 @@
+package decimal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"19.99", "19.99"},
+		{"-0.5", "-0.5"},
+		{"+3", "3"},
+		{".25", "0.25"},
+		{"10.", "10"},
+		{"0.000", "0.000"},
+		{"  42.10 ", "42.10"},
+		{"99999999999999999999.99", "99999999999999999999.99"},
+		{"1.5e-3", "0.0015"},
+		{"1.5E2", "150"},
+		{"-2e+1", "-20"},
+		{"12.345e1", "123.45"},
+	}
+
+	for _, tt := range tests {
+		d, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %s", tt.input, err)
+			continue
+		}
+
+		if d.String() != tt.expected {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, d, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", ".", "-", "1.2.3", "abc", "1e", "e5", "1e5.5", "1e99999", "1_000"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected an error, got none", input)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{0.1, "0.1"},
+		{2.675, "2.675"},
+		{-42, "-42"},
+		{1e21, "1000000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		d, err := FromFloat(tt.input)
+		if err != nil {
+			t.Errorf("FromFloat(%v) returned error: %s", tt.input, err)
+			continue
+		}
+
+		if d.String() != tt.expected {
+			t.Errorf("FromFloat(%v) = %s, want %s", tt.input, d, tt.expected)
+		}
+	}
+
+	for _, input := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := FromFloat(input); err == nil {
+			t.Errorf("FromFloat(%v) expected an error, got none", input)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		left     string
+		operator string
+		right    string
+		expected string
+	}{
+		{"0.1", "+", "0.2", "0.3"},
+		{"19.99", "+", "0.01", "20.00"},
+		{"1", "-", "0.9", "0.1"},
+		{"-1.5", "-", "2.25", "-3.75"},
+		{"19.99", "*", "3", "59.97"},
+		{"0.1", "*", "0.1", "0.01"},
+		{"10", "/", "4", "2.5"},
+		{"10.00", "/", "4", "2.50"},
+		{"1", "/", "3", "0.3333333333333333"},
+		{"-2", "/", "3", "-0.6666666666666667"},
+		{"5.5", "%", "2", "1.5"},
+		{"-7.5", "%", "2", "-1.5"},
+	}
+
+	for _, tt := range tests {
+		left, right := MustParse(tt.left), MustParse(tt.right)
+
+		var result Decimal
+		var err error
+
+		switch tt.operator {
+		case "+":
+			result = left.Add(right)
+		case "-":
+			result = left.Sub(right)
+		case "*":
+			result = left.Mul(right)
+		case "/":
+			result, err = left.Div(right)
+		case "%":
+			result, err = left.Mod(right)
+		}
+
+		if err != nil {
+			t.Errorf("%s %s %s returned error: %s", tt.left, tt.operator, tt.right, err)
+			continue
+		}
+
+		if result.String() != tt.expected {
+			t.Errorf("%s %s %s = %s, want %s", tt.left, tt.operator, tt.right, result, tt.expected)
+		}
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	if _, err := MustParse("1").Div(MustParse("0.0")); err == nil {
+		t.Errorf("expected division by zero error, got none")
+	}
+
+	if _, err := MustParse("1").Mod(MustParse("0")); err == nil {
+		t.Errorf("expected division by zero error, got none")
+	}
+}
+
+func TestPow(t *testing.T) {
+	tests := []struct {
+		base     string
+		exponent int64
+		expected string
+	}{
+		{"1.1", 2, "1.21"},
+		{"1.05", 3, "1.157625"},
+		{"2", -2, "0.25"},
+		{"5", 0, "1"},
+	}
+
+	for _, tt := range tests {
+		result, err := MustParse(tt.base).Pow(tt.exponent)
+		if err != nil {
+			t.Errorf("%s ^ %d returned error: %s", tt.base, tt.exponent, err)
+			continue
+		}
+
+		if result.String() != tt.expected {
+			t.Errorf("%s ^ %d = %s, want %s", tt.base, tt.exponent, result, tt.expected)
+		}
+	}
+
+	if _, err := MustParse("2").Pow(math.MaxInt32); err == nil {
+		t.Errorf("expected overflow error, got none")
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		input    string
+		places   int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.675", 2, HalfUp, "2.68"},
+		{"2.5", 0, HalfUp, "3"},
+		{"-2.5", 0, HalfUp, "-3"},
+		{"2.5", 0, HalfDown, "2"},
+		{"2.51", 0, HalfDown, "3"},
+		{"2.5", 0, HalfEven, "2"},
+		{"3.5", 0, HalfEven, "4"},
+		{"-2.5", 0, HalfEven, "-2"},
+		{"2.1", 0, Up, "3"},
+		{"-2.1", 0, Up, "-3"},
+		{"2.9", 0, Down, "2"},
+		{"-2.9", 0, Down, "-2"},
+		{"-2.1", 0, Ceiling, "-2"},
+		{"2.1", 0, Ceiling, "3"},
+		{"-2.1", 0, Floor, "-3"},
+		{"2.9", 0, Floor, "2"},
+		{"1234.5", -2, HalfUp, "1200"},
+		{"1250", -2, HalfEven, "1200"},
+		{"1.5", 4, HalfUp, "1.5"},
+	}
+
+	for _, tt := range tests {
+		result := MustParse(tt.input).Round(tt.places, tt.mode)
+		if result.String() != tt.expected {
+			t.Errorf("round(%s, %d, %s) = %s, want %s", tt.input, tt.places, tt.mode, result, tt.expected)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for name, mode := range roundingModes {
+		parsed, err := ParseRoundingMode(name)
+		if err != nil {
+			t.Errorf("ParseRoundingMode(%q) returned error: %s", name, err)
+		}
+
+		if parsed != mode || parsed.String() != name {
+			t.Errorf("ParseRoundingMode(%q) = %s, want %s", name, parsed, name)
+		}
+	}
+
+	if _, err := ParseRoundingMode("sideways"); err == nil {
+		t.Errorf("expected unknown rounding mode error, got none")
+	}
+}
+
+func TestCompareAndNormalize(t *testing.T) {
+	if MustParse("1.50").Cmp(MustParse("1.5")) != 0 {
+		t.Errorf("expected 1.50 to equal 1.5")
+	}
+
+	if MustParse("-0.1").Cmp(MustParse("0.01")) != -1 {
+		t.Errorf("expected -0.1 to be less than 0.01")
+	}
+
+	if normalized := MustParse("12.3400").Normalize().String(); normalized != "12.34" {
+		t.Errorf("expected 12.3400 to normalize to 12.34, got %s", normalized)
+	}
+
+	if normalized := MustParse("100.00").Normalize().String(); normalized != "100" {
+		t.Errorf("expected 100.00 to normalize to 100, got %s", normalized)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	d := MustParse("-19.99")
+
+	if d.Float64() != -19.99 {
+		t.Errorf("expected -19.99 as float, got %f", d.Float64())
+	}
+
+	if d.Truncate().Int64() != -19 {
+		t.Errorf("expected -19 when truncated, got %s", d.Truncate())
+	}
+}
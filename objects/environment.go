@@ -11,6 +11,7 @@ type Environment struct {
 	exports map[string]Object
 	outer   *Environment
 	file    *FileDescriptorContext
+	yield   func(val Object)
 }
 
 type EnvironmentStateItem struct {
@@ -61,8 +62,10 @@ func (e *Environment) GetStateItem(name string) (EnvironmentStateItem, bool) {
 	return val, ok
 }
 
+// Set defines the variable in the environment, shadowing any variables with
+// the same name from the outer environments.
 func (e *Environment) Set(node ast.Node, name string, val Object, mutable bool) Object {
-	item, ok := e.GetStateItem(name)
+	item, ok := e.store[name]
 	if ok {
 		if !item.mutable {
 			return NewError(
@@ -138,6 +141,28 @@ func (e *Environment) GetExports() map[string]Object {
 	return e.exports
 }
 
+// SetYieldHandler marks the environment as the body of a generator, values
+// yielded within it, or any environment enclosed by it, are passed to fn.
+func (e *Environment) SetYieldHandler(fn func(val Object)) {
+	e.yield = fn
+}
+
+// Yield passes the value to the closest generator, returning false if the
+// environment isn't within the body of a generator.
+func (e *Environment) Yield(val Object) bool {
+	if e.yield == nil && e.outer != nil {
+		return e.outer.Yield(val)
+	}
+
+	if e.yield == nil {
+		return false
+	}
+
+	e.yield(val)
+
+	return true
+}
+
 func (e *Environment) GetFileDescriptorContext() *FileDescriptorContext {
 	if e.file == nil && e.outer != nil {
 		return e.outer.GetFileDescriptorContext()
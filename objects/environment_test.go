@@ -130,6 +130,29 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestSetShadowsOuterVariables(t *testing.T) {
+	outer := NewEnvironment(nil)
+	outer.Set(nil, "var1", &Integer{Value: 1}, false)
+
+	inner := NewEnclosedEnvironment(outer)
+	val := &Integer{Value: 2}
+
+	result := inner.Set(nil, "var1", val, true)
+	if result != val {
+		t.Fatalf("Expected Set to shadow the immutable outer variable 'var1', got %s", result.Inspect())
+	}
+
+	innerVal, _ := inner.GetStateItem("var1")
+	if innerVal.value != val || !innerVal.mutable {
+		t.Fatal("Expected the inner environment to have its own mutable 'var1'")
+	}
+
+	outerVal, _ := outer.GetStateItem("var1")
+	if outerVal.value.(*Integer).Value != 1 || outerVal.mutable {
+		t.Fatal("Expected the outer 'var1' to be left untouched")
+	}
+}
+
 func TestAssignWithMutable(t *testing.T) {
 	env := NewEnvironment(nil)
 	val := &Integer{Value: 20}
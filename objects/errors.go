@@ -17,6 +17,10 @@ func NewWrongNumberOfArgumentsWantAtLeastError(name string, expected int, got in
 	return fmt.Errorf("wrong number of arguments to `%s`: got %d, want at least %d", name, got, expected)
 }
 
+func NewWrongNumberOfArgumentsWantAtMostError(name string, expected int, got int) error {
+	return fmt.Errorf("wrong number of arguments to `%s`: got %d, want at most %d", name, got, expected)
+}
+
 func NewInvalidArgumentTypeError(name string, expected ObjectType, index int, args []Object) error {
 	return fmt.Errorf(
 		"argument %d to `%s` has invalid type: got %s, want %s",
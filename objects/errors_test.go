@@ -29,6 +29,15 @@ func TestNewWrongNumberOfArgumentsWantAtLeastError(t *testing.T) {
 	}
 }
 
+func TestNewWrongNumberOfArgumentsWantAtMostError(t *testing.T) {
+	err := NewWrongNumberOfArgumentsWantAtMostError("myFunc", 2, 3)
+	expected := "wrong number of arguments to `myFunc`: got 3, want at most 2"
+
+	if err.Error() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, err.Error())
+	}
+}
+
 func TestNewInvalidArgumentTypeError(t *testing.T) {
 	args := []Object{&Integer{Value: 42}}
 	err := NewInvalidArgumentTypeError("myFunc", STRING_OBJ, 0, args)
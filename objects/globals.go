@@ -77,6 +77,20 @@ var Globals = []struct {
 				},
 				Builtin: &Builtin{Fn: globalStringsTrim},
 			},
+			{
+				Name: "bytes",
+				Schema: BuiltinSchema{
+					NewRequiredArgument(STRING_OBJ),
+				},
+				Builtin: &Builtin{Fn: globalStringsBytes},
+			},
+			{
+				Name: "runes",
+				Schema: BuiltinSchema{
+					NewRequiredArgument(STRING_OBJ),
+				},
+				Builtin: &Builtin{Fn: globalStringsRunes},
+			},
 		},
 	},
 	{
@@ -227,6 +241,8 @@ var Globals = []struct {
 				Name: "round",
 				Schema: BuiltinSchema{
 					NewRequiredArgument(GetNumberTypes()...),
+					NewOptionalArgument(INTEGER_OBJ),
+					NewOptionalArgument(STRING_OBJ),
 				},
 				Builtin: &Builtin{Fn: globalMathRound},
 			},
@@ -299,6 +315,59 @@ var Globals = []struct {
 				},
 				Builtin: &Builtin{Fn: globalTimeScheduleTimer},
 			},
+			{
+				Name:    "clearTimer",
+				Schema:  BuiltinSchema{NewRequiredArgument(STRING_OBJ, IMMUTABLE_HASH_OBJ)},
+				Builtin: &Builtin{Fn: globalTimeClearTimer},
+			},
+		},
+	},
+	{
+		Name: "channels",
+		Builtins: []*BuiltinDefinition{
+			{
+				Name:    "make",
+				Schema:  BuiltinSchema{NewOptionalArgument(INTEGER_OBJ)},
+				Builtin: &Builtin{Fn: globalChannelsMake},
+			},
+			{
+				Name: "send",
+				Schema: BuiltinSchema{
+					NewRequiredArgument(CHANNEL_OBJ),
+					NewRequiredArgument(),
+				},
+				Builtin: &Builtin{Fn: globalChannelsSend},
+			},
+			{
+				Name:    "receive",
+				Schema:  BuiltinSchema{NewRequiredArgument(CHANNEL_OBJ)},
+				Builtin: &Builtin{Fn: globalChannelsReceive},
+			},
+			{
+				Name:    "close",
+				Schema:  BuiltinSchema{NewRequiredArgument(CHANNEL_OBJ)},
+				Builtin: &Builtin{Fn: globalChannelsClose},
+			},
+			{
+				Name:    "select",
+				Schema:  BuiltinSchema{NewRequiredArgument(ARRAY_OBJ)},
+				Builtin: &Builtin{Fn: globalChannelsSelect},
+			},
+		},
+	},
+	{
+		Name: "promises",
+		Builtins: []*BuiltinDefinition{
+			{
+				Name:    "all",
+				Schema:  BuiltinSchema{NewRequiredArgument(ARRAY_OBJ)},
+				Builtin: &Builtin{Fn: globalPromisesAll},
+			},
+			{
+				Name:    "race",
+				Schema:  BuiltinSchema{NewRequiredArgument(ARRAY_OBJ)},
+				Builtin: &Builtin{Fn: globalPromisesRace},
+			},
 		},
 	},
 	{
@@ -0,0 +1,103 @@
+package objects
+
+func globalChannelsMake(args ...Object) (Object, error) {
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsWantAtMostError("make", 1, len(args))
+	}
+
+	capacity := int64(0)
+	if len(args) == 1 {
+		capacityObj, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewInvalidArgumentTypeError("make", INTEGER_OBJ, 0, args)
+		}
+
+		capacity = capacityObj.Value
+	}
+
+	if capacity < 0 {
+		return nil, NewErrorf("make", "channel capacity must be non-negative")
+	}
+
+	return NewChannel(int(capacity)), nil
+}
+
+func globalChannelsSend(args ...Object) (Object, error) {
+	if len(args) != 2 {
+		return nil, NewWrongNumberOfArgumentsError("send", 2, len(args))
+	}
+
+	channel, ok := args[0].(*Channel)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("send", CHANNEL_OBJ, 0, args)
+	}
+
+	err := channel.Send(args[1])
+	if err != nil {
+		return nil, NewErrorf("send", "%s", err.Error())
+	}
+
+	return NULL, nil
+}
+
+func globalChannelsReceive(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("receive", 1, len(args))
+	}
+
+	channel, ok := args[0].(*Channel)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("receive", CHANNEL_OBJ, 0, args)
+	}
+
+	value, _ := channel.Receive()
+
+	return value, nil
+}
+
+func globalChannelsClose(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("close", 1, len(args))
+	}
+
+	channel, ok := args[0].(*Channel)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("close", CHANNEL_OBJ, 0, args)
+	}
+
+	err := channel.Close()
+	if err != nil {
+		return nil, NewErrorf("close", "%s", err.Error())
+	}
+
+	return NULL, nil
+}
+
+func globalChannelsSelect(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("select", 1, len(args))
+	}
+
+	arr, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("select", ARRAY_OBJ, 0, args)
+	}
+
+	if len(arr.Elements) == 0 {
+		return nil, NewErrorf("select", "cannot select from an empty array of channels")
+	}
+
+	channels := make([]*Channel, len(arr.Elements))
+	for i, element := range arr.Elements {
+		channel, ok := element.(*Channel)
+		if !ok {
+			return nil, NewErrorf("select", "element %d is not a channel, got %s", i, element.Type())
+		}
+
+		channels[i] = channel
+	}
+
+	index, value, _ := SelectChannel(channels)
+
+	return &Array{Elements: []Object{&Integer{Value: int64(index)}, value}}, nil
+}
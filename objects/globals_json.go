@@ -151,6 +151,10 @@ func objectToNativeValue(obj Object) any {
 		return val.Value
 	case *Integer:
 		return int64(val.Value)
+	case *BigInt:
+		return json.Number(val.Value.String())
+	case *Decimal:
+		return json.Number(val.Value.String())
 	case *Float:
 		return val.Value
 	case *Boolean:
@@ -2,6 +2,8 @@ package objects
 
 import (
 	"math"
+
+	"github.com/senither/zen-lang/objects/decimal"
 )
 
 func globalMathMin(args ...Object) (Object, error) {
@@ -17,6 +19,14 @@ func globalMathMin(args ...Object) (Object, error) {
 		return nil, NewInvalidArgumentTypesError("min", GetNumberTypes(), 1, args)
 	}
 
+	if isExactNumberPair(args[0], args[1]) {
+		if cmp, _ := CompareNumbers(args[0], args[1]); cmp <= 0 {
+			return args[0], nil
+		}
+
+		return args[1], nil
+	}
+
 	return WrapNumberValue(math.Min(
 		UnwrapNumberValue(args[0]),
 		UnwrapNumberValue(args[1]),
@@ -36,12 +46,26 @@ func globalMathMax(args ...Object) (Object, error) {
 		return nil, NewInvalidArgumentTypesError("max", GetNumberTypes(), 1, args)
 	}
 
+	if isExactNumberPair(args[0], args[1]) {
+		if cmp, _ := CompareNumbers(args[0], args[1]); cmp >= 0 {
+			return args[0], nil
+		}
+
+		return args[1], nil
+	}
+
 	return WrapNumberValue(math.Max(
 		UnwrapNumberValue(args[0]),
 		UnwrapNumberValue(args[1]),
 	), args[0], args[1]), nil
 }
 
+// isExactNumberPair checks if the numbers can be compared exactly, which is the
+// case for integers, or if either of the numbers is a decimal.
+func isExactNumberPair(left, right Object) bool {
+	return IsInteger(left) && IsInteger(right) || left.Type() == DECIMAL_OBJ || right.Type() == DECIMAL_OBJ
+}
+
 func globalMathCeil(args ...Object) (Object, error) {
 	if len(args) != 1 {
 		return nil, NewWrongNumberOfArgumentsError("ceil", 1, len(args))
@@ -51,6 +75,10 @@ func globalMathCeil(args ...Object) (Object, error) {
 		return nil, NewInvalidArgumentTypesError("ceil", GetNumberTypes(), 0, args)
 	}
 
+	if value, ok := args[0].(*Decimal); ok {
+		return &Decimal{Value: value.Value.Round(0, decimal.Ceiling)}, nil
+	}
+
 	return &Float{Value: math.Ceil(UnwrapNumberValue(args[0]))}, nil
 }
 
@@ -63,19 +91,81 @@ func globalMathFloor(args ...Object) (Object, error) {
 		return nil, NewInvalidArgumentTypesError("floor", GetNumberTypes(), 0, args)
 	}
 
+	if value, ok := args[0].(*Decimal); ok {
+		return &Decimal{Value: value.Value.Round(0, decimal.Floor)}, nil
+	}
+
 	return &Float{Value: math.Floor(UnwrapNumberValue(args[0]))}, nil
 }
 
 func globalMathRound(args ...Object) (Object, error) {
-	if len(args) != 1 {
-		return nil, NewWrongNumberOfArgumentsError("round", 1, len(args))
+	if len(args) == 0 {
+		return nil, NewWrongNumberOfArgumentsWantAtLeastError("round", 1, len(args))
+	}
+
+	if len(args) > 3 {
+		return nil, NewWrongNumberOfArgumentsWantAtMostError("round", 3, len(args))
 	}
 
 	if !IsNumber(args[0].Type()) {
 		return nil, NewInvalidArgumentTypesError("round", GetNumberTypes(), 0, args)
 	}
 
-	return &Float{Value: math.Round(UnwrapNumberValue(args[0]))}, nil
+	places, mode, err := parseRoundingArguments("round", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := args[0].(*Decimal); ok {
+		return &Decimal{Value: value.Value.Round(places, mode)}, nil
+	}
+
+	if len(args) == 1 {
+		return &Float{Value: math.Round(UnwrapNumberValue(args[0]))}, nil
+	}
+
+	// Floats are rounded as decimals so the rounding isn't affected by the
+	// binary representation, like 2.675 being stored as 2.67499999...
+	value, err := UnwrapDecimalValue(args[0])
+	if err != nil {
+		return &Float{Value: UnwrapNumberValue(args[0])}, nil
+	}
+
+	return &Float{Value: value.Round(places, mode).Float64()}, nil
+}
+
+// parseRoundingArguments parses the optional number of decimal places and the
+// name of the rounding mode starting at the given argument index, defaulting
+// to rounding to whole numbers using the half up rounding mode.
+func parseRoundingArguments(name string, args []Object, index int) (int32, decimal.RoundingMode, error) {
+	places := int64(0)
+	if len(args) > index {
+		placesObj, ok := args[index].(*Integer)
+		if !ok {
+			return 0, decimal.HalfUp, NewInvalidArgumentTypeError(name, INTEGER_OBJ, index, args)
+		}
+
+		if placesObj.Value < math.MinInt16 || placesObj.Value > math.MaxInt16 {
+			return 0, decimal.HalfUp, NewErrorf(name, "decimal places must be between %d and %d", math.MinInt16, math.MaxInt16)
+		}
+
+		places = placesObj.Value
+	}
+
+	mode := decimal.HalfUp
+	if len(args) > index+1 {
+		modeObj, ok := args[index+1].(*String)
+		if !ok {
+			return 0, decimal.HalfUp, NewInvalidArgumentTypeError(name, STRING_OBJ, index+1, args)
+		}
+
+		var err error
+		if mode, err = decimal.ParseRoundingMode(modeObj.Value); err != nil {
+			return 0, decimal.HalfUp, NewErrorf(name, "%s", err)
+		}
+	}
+
+	return int32(places), mode, nil
 }
 
 func globalMathLog(args ...Object) (Object, error) {
@@ -0,0 +1,66 @@
+package objects
+
+func globalPromisesAll(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("all", 1, len(args))
+	}
+
+	array, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("all", ARRAY_OBJ, 0, args)
+	}
+
+	promise := NewPromise()
+	results := make([]Object, len(array.Elements))
+	remaining := len(array.Elements)
+
+	if remaining == 0 {
+		promise.Resolve(&Array{Elements: results})
+		return promise, nil
+	}
+
+	for i, element := range array.Elements {
+		other := PromiseOf(element)
+		other.OnSettled(func() {
+			if other.State == PromiseRejected {
+				promise.Reject(other.Result().(*Error))
+				return
+			}
+
+			results[i] = other.Result()
+
+			remaining--
+			if remaining == 0 {
+				promise.Resolve(&Array{Elements: results})
+			}
+		})
+	}
+
+	return promise, nil
+}
+
+func globalPromisesRace(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("race", 1, len(args))
+	}
+
+	array, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("race", ARRAY_OBJ, 0, args)
+	}
+
+	if len(array.Elements) == 0 {
+		return nil, NewErrorf("race", "cannot race an empty array of promises")
+	}
+
+	promise := NewPromise()
+
+	for _, element := range array.Elements {
+		other := PromiseOf(element)
+		other.OnSettled(func() {
+			promise.Resolve(other)
+		})
+	}
+
+	return promise, nil
+}
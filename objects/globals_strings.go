@@ -207,6 +207,42 @@ func globalStringsToLower(args ...Object) (Object, error) {
 	return &String{Value: strings.ToLower(str.Value)}, nil
 }
 
+func globalStringsBytes(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("bytes", 1, len(args))
+	}
+
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("bytes", STRING_OBJ, 0, args)
+	}
+
+	elements := make([]Object, len(str.Value))
+	for i := 0; i < len(str.Value); i++ {
+		elements[i] = &Integer{Value: int64(str.Value[i])}
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+func globalStringsRunes(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("runes", 1, len(args))
+	}
+
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, NewInvalidArgumentTypeError("runes", STRING_OBJ, 0, args)
+	}
+
+	elements := []Object{}
+	for _, r := range str.Value {
+		elements = append(elements, &Integer{Value: int64(r)})
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
 func globalStringsTrim(args ...Object) (Object, error) {
 	if len(args) == 0 {
 		return nil, NewWrongNumberOfArgumentsWantAtLeastError("trim", 1, len(args))
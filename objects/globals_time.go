@@ -135,7 +135,7 @@ func globalTimeDelayTimer(args ...Object) (Object, error) {
 		HashPair{
 			Key: &String{Value: "stop"},
 			Value: &Builtin{Fn: func(args ...Object) (Object, error) {
-				if timer.StopDelayedTimer(time) {
+				if timer.StopTimer(time) {
 					return TRUE, nil
 				}
 
@@ -144,7 +144,7 @@ func globalTimeDelayTimer(args ...Object) (Object, error) {
 		},
 		HashPair{
 			Key:   &String{Value: "timer"},
-			Value: &String{Value: fmt.Sprintf("%p", time)},
+			Value: &String{Value: time.ID()},
 		},
 	), nil
 }
@@ -168,8 +168,8 @@ func globalTimeScheduleTimer(args ...Object) (Object, error) {
 		return nil, NewInvalidArgumentTypeError("scheduleTimer", INTEGER_OBJ, 1, args)
 	}
 
-	if intervalTime.Value < 0 {
-		return nil, NewErrorf("scheduleTimer", "interval time must be non-negative")
+	if intervalTime.Value <= 0 {
+		return nil, NewErrorf("scheduleTimer", "interval time must be positive")
 	}
 
 	ticker := timer.StartScheduledTimer(func() {
@@ -182,13 +182,46 @@ func globalTimeScheduleTimer(args ...Object) (Object, error) {
 		HashPair{
 			Key: &String{Value: "stop"},
 			Value: &Builtin{Fn: func(args ...Object) (Object, error) {
-				timer.StopScheduledTimer(ticker)
+				timer.StopTimer(ticker)
 				return TRUE, nil
 			}},
 		},
 		HashPair{
 			Key:   &String{Value: "timer"},
-			Value: &String{Value: fmt.Sprintf("%p", ticker)},
+			Value: &String{Value: ticker.ID()},
 		},
 	), nil
 }
+
+func globalTimeClearTimer(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError("clearTimer", 1, len(args))
+	}
+
+	var id string
+	switch v := args[0].(type) {
+	case *String:
+		id = v.Value
+	case *ImmutableHash:
+		pair, ok := v.Value.Pairs[(&String{Value: "timer"}).HashKey()]
+		if !ok {
+			return nil, NewErrorf("clearTimer", "hash passed to `clearTimer` is not a timer")
+		}
+
+		value, ok := pair.Value.(*String)
+		if !ok {
+			return nil, NewErrorf("clearTimer", "hash passed to `clearTimer` is not a timer")
+		}
+
+		id = value.Value
+
+	default:
+		return nil, NewInvalidArgumentTypesError("clearTimer", []ObjectType{STRING_OBJ, IMMUTABLE_HASH_OBJ}, 0, args)
+	}
+
+	if timer.StopTimer(timer.FindTimer(id)) {
+		return TRUE, nil
+	}
+
+	return FALSE, nil
+}
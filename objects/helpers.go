@@ -1,9 +1,16 @@
 package objects
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/senither/zen-lang/objects/decimal"
 	"github.com/senither/zen-lang/objects/process"
 	"github.com/senither/zen-lang/objects/timer"
 	"github.com/senither/zen-lang/tokens"
@@ -33,9 +40,82 @@ func NewFileDescriptorContext(path string) *FileDescriptorContext {
 }
 
 func NativeErrorToErrorObject(err error) *Error {
+	var thrown *ThrownError
+	if errors.As(err, &thrown) {
+		return &Error{Message: err.Error(), Thrown: thrown.Value}
+	}
+
 	return &Error{Message: err.Error()}
 }
 
+// ThrownError carries a value thrown within the VM until it is either caught
+// or reported, so the catch block can be given the value that was thrown.
+type ThrownError struct {
+	Value Object
+}
+
+func (e *ThrownError) Error() string { return ThrownErrorMessage(e.Value) }
+
+// ErrorToHash converts the error into a hash that can be handed to a catch
+// block, the hash always has a message, file, line and column, where the parts
+// that aren't known are null. Thrown hashes keep their own keys, so a caught
+// error can be thrown again and custom errors can carry extra information.
+func ErrorToHash(err *Error) *Hash {
+	for err.Parent != nil {
+		err = err.Parent
+	}
+
+	hash := &Hash{Pairs: make(map[HashKey]HashPair)}
+	if thrown, ok := err.Thrown.(*Hash); ok {
+		for key, pair := range thrown.Pairs {
+			hash.Pairs[key] = pair
+		}
+	}
+
+	var message Object = &String{Value: err.Message}
+	if _, ok := err.Thrown.(*Hash); ok {
+		message = NULL
+	}
+
+	var file, line, column Object = NULL, NULL, NULL
+	if err.File != "" {
+		file = &String{Value: err.File}
+	}
+
+	// Errors raised by the VM don't know where in the source they happened,
+	// so their line and column are left as null.
+	if err.Line > 0 {
+		line = &Integer{Value: int64(err.Line)}
+		column = &Integer{Value: int64(err.Column)}
+	}
+
+	for _, pair := range []HashPair{
+		{Key: &String{Value: "message"}, Value: message},
+		{Key: &String{Value: "file"}, Value: file},
+		{Key: &String{Value: "line"}, Value: line},
+		{Key: &String{Value: "column"}, Value: column},
+	} {
+		key := pair.Key.(Hashable).HashKey()
+		if _, ok := hash.Pairs[key]; !ok {
+			hash.Pairs[key] = pair
+		}
+	}
+
+	return hash
+}
+
+// ThrownErrorMessage returns the error message for a thrown value, caught
+// errors keep their original message so they can be thrown again.
+func ThrownErrorMessage(obj Object) string {
+	if hash, ok := obj.(*Hash); ok {
+		if pair, ok := hash.Pairs[(&String{Value: "message"}).HashKey()]; ok {
+			return StringifyObject(pair.Value)
+		}
+	}
+
+	return StringifyObject(obj)
+}
+
 func NewError(token tokens.Token, fileCtx *FileDescriptorContext, format string, a ...any) *Error {
 	err := Error{
 		Message: fmt.Sprintf(format, a...),
@@ -85,7 +165,18 @@ func IsNumber(t ObjectType) bool {
 }
 
 func GetNumberTypes() []ObjectType {
-	return []ObjectType{INTEGER_OBJ, FLOAT_OBJ}
+	return []ObjectType{INTEGER_OBJ, BIGINT_OBJ, FLOAT_OBJ, DECIMAL_OBJ}
+}
+
+// IsInteger checks if the object is an integer, either an Integer or a BigInt.
+func IsInteger(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *BigInt:
+		return true
+
+	default:
+		return false
+	}
 }
 
 func WrapNumberValue(value float64, left, right Object) Object {
@@ -104,17 +195,489 @@ func UnwrapNumberValue(obj Object) float64 {
 	switch n := obj.(type) {
 	case *Integer:
 		return float64(n.Value)
+	case *BigInt:
+		value, _ := new(big.Float).SetInt(n.Value).Float64()
+		return value
 	case *Float:
 		return n.Value
+	case *Decimal:
+		return n.Value.Float64()
 
 	default:
 		return 0
 	}
 }
 
+// UnwrapDecimalValue converts the number to a decimal, floats are converted
+// using the shortest decimal that represents them, so 0.1 becomes exactly 0.1.
+func UnwrapDecimalValue(obj Object) (decimal.Decimal, error) {
+	switch n := obj.(type) {
+	case *Integer:
+		return decimal.FromInt64(n.Value), nil
+	case *BigInt:
+		return decimal.FromInt(n.Value), nil
+	case *Float:
+		return decimal.FromFloat(n.Value)
+	case *Decimal:
+		return n.Value, nil
+
+	default:
+		return decimal.Decimal{}, fmt.Errorf("cannot convert %s to a decimal", obj.Type())
+	}
+}
+
+// WrapBigIntValue returns the value as an Integer if it fits in an int64,
+// otherwise the value is returned as a BigInt.
+func WrapBigIntValue(value *big.Int) Object {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+
+	return &BigInt{Value: value}
+}
+
+func UnwrapBigIntValue(obj Object) *big.Int {
+	switch n := obj.(type) {
+	case *Integer:
+		return big.NewInt(n.Value)
+	case *BigInt:
+		return n.Value
+
+	default:
+		return new(big.Int)
+	}
+}
+
+// parseIntegerString parses integers written in any of the forms supported by
+// integer literals, like 42, 0xFF, 0b1010, 0o755 and 1_000_000, integers too
+// large to fit in an int64 are returned as a BigInt.
+func parseIntegerString(value string) (Object, bool) {
+	text := strings.TrimSpace(value)
+
+	result, err := strconv.ParseInt(text, 0, 64)
+	if err == nil {
+		return &Integer{Value: result}, true
+	}
+
+	if !errors.Is(err, strconv.ErrRange) {
+		return nil, false
+	}
+
+	bigValue, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return nil, false
+	}
+
+	return WrapBigIntValue(bigValue), true
+}
+
+// MAX_BIGINT_BITS limits the size of the integers created by exponents and
+// shifts, which can otherwise create integers too large to fit in memory.
+const MAX_BIGINT_BITS = 1 << 20
+
+// ApplyNumberOperator applies the arithmetic operator to the two numbers, the
+// operations between integers are exact and are promoted to a BigInt when the
+// result doesn't fit in an int64, operations involving a decimal are exact and
+// return a decimal, anything else is calculated using floats.
+func ApplyNumberOperator(operator string, left, right Object) (Object, error) {
+	if left.Type() == DECIMAL_OBJ || right.Type() == DECIMAL_OBJ {
+		return applyDecimalOperator(operator, left, right)
+	}
+
+	if IsInteger(left) && IsInteger(right) {
+		result, ok, err := applyIntegerOperator(operator, left, right)
+		if err != nil || ok {
+			return result, err
+		}
+	}
+
+	leftValue := UnwrapNumberValue(left)
+	rightValue := UnwrapNumberValue(right)
+
+	var result float64
+
+	switch operator {
+	case "+":
+		result = leftValue + rightValue
+	case "-":
+		result = leftValue - rightValue
+	case "*":
+		result = leftValue * rightValue
+	case "/":
+		result = leftValue / rightValue
+	case "^":
+		result = math.Pow(leftValue, rightValue)
+	case "%":
+		result = math.Mod(leftValue, rightValue)
+
+	default:
+		return nil, fmt.Errorf("unknown number operator: %s", operator)
+	}
+
+	// Integer divisions only get here if they aren't exact, so the result is
+	// kept as a float even if it's too large for the float to have a fraction.
+	if operator == "/" && IsInteger(left) && IsInteger(right) {
+		return &Float{Value: result}, nil
+	}
+
+	return WrapNumberValue(result, left, right), nil
+}
+
+// applyIntegerOperator applies the operator to the two integers, returning
+// false if the result isn't an integer, like dividing by zero or raising to
+// a negative power, in which case the result should be calculated as floats.
+func applyIntegerOperator(operator string, left, right Object) (Object, bool, error) {
+	leftInt, leftOk := left.(*Integer)
+	rightInt, rightOk := right.(*Integer)
+
+	if leftOk && rightOk && operator != "^" {
+		result, ok, overflow := applyInt64Operator(operator, leftInt.Value, rightInt.Value)
+		if !overflow {
+			if !ok {
+				return nil, false, nil
+			}
+
+			return &Integer{Value: result}, true, nil
+		}
+	}
+
+	leftValue := UnwrapBigIntValue(left)
+	rightValue := UnwrapBigIntValue(right)
+
+	switch operator {
+	case "+":
+		return WrapBigIntValue(new(big.Int).Add(leftValue, rightValue)), true, nil
+	case "-":
+		return WrapBigIntValue(new(big.Int).Sub(leftValue, rightValue)), true, nil
+	case "*":
+		return WrapBigIntValue(new(big.Int).Mul(leftValue, rightValue)), true, nil
+	case "/":
+		if rightValue.Sign() == 0 {
+			return nil, false, nil
+		}
+
+		quotient, remainder := new(big.Int).QuoRem(leftValue, rightValue, new(big.Int))
+		if remainder.Sign() != 0 {
+			return nil, false, nil
+		}
+
+		return WrapBigIntValue(quotient), true, nil
+	case "%":
+		if rightValue.Sign() == 0 {
+			return nil, false, nil
+		}
+
+		return WrapBigIntValue(new(big.Int).Rem(leftValue, rightValue)), true, nil
+	case "^":
+		if rightValue.Sign() < 0 {
+			return nil, false, nil
+		}
+
+		if leftValue.CmpAbs(big.NewInt(1)) > 0 &&
+			(!rightValue.IsInt64() || rightValue.Int64() > MAX_BIGINT_BITS/int64(leftValue.BitLen()-1)) {
+			return nil, false, fmt.Errorf("integer overflow: %s ^ %s is too large", leftValue, rightValue)
+		}
+
+		return WrapBigIntValue(new(big.Int).Exp(leftValue, rightValue, nil)), true, nil
+
+	default:
+		return nil, false, fmt.Errorf("unknown number operator: %s", operator)
+	}
+}
+
+// applyDecimalOperator applies the operator to the two numbers as decimals,
+// exponents must be integers since the result would otherwise be inexact.
+func applyDecimalOperator(operator string, left, right Object) (Object, error) {
+	leftValue, err := UnwrapDecimalValue(left)
+	if err != nil {
+		return nil, err
+	}
+
+	rightValue, err := UnwrapDecimalValue(right)
+	if err != nil {
+		return nil, err
+	}
+
+	var result decimal.Decimal
+
+	switch operator {
+	case "+":
+		result = leftValue.Add(rightValue)
+	case "-":
+		result = leftValue.Sub(rightValue)
+	case "*":
+		result = leftValue.Mul(rightValue)
+	case "/":
+		result, err = leftValue.Div(rightValue)
+	case "%":
+		result, err = leftValue.Mod(rightValue)
+	case "^":
+		exponent := rightValue.Normalize()
+		if exponent.Scale() != 0 || !exponent.Coefficient().IsInt64() {
+			return nil, fmt.Errorf("decimal exponents must be integers, got %s", rightValue)
+		}
+
+		result, err = leftValue.Pow(exponent.Coefficient().Int64())
+
+	default:
+		return nil, fmt.Errorf("unknown number operator: %s", operator)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decimal{Value: result}, nil
+}
+
+// applyInt64Operator applies the operator to the two int64 values, reporting
+// if the operation overflowed so it can be done again using big integers,
+// unknown operators are reported as overflowing to be handled there too.
+func applyInt64Operator(operator string, left, right int64) (result int64, ok, overflow bool) {
+	switch operator {
+	case "+":
+		result = left + right
+		return result, true, (left^result)&(right^result) < 0
+	case "-":
+		result = left - right
+		return result, true, (left^right)&(left^result) < 0
+	case "*":
+		if left == 0 || right == 0 {
+			return 0, true, false
+		}
+
+		result = left * right
+		return result, true, result/right != left || (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64)
+	case "/":
+		if right == 0 || left%right != 0 {
+			return 0, false, false
+		}
+
+		if left == math.MinInt64 && right == -1 {
+			return 0, true, true
+		}
+
+		return left / right, true, false
+	case "%":
+		if right == 0 {
+			return 0, false, false
+		}
+
+		if right == -1 {
+			return 0, true, false
+		}
+
+		return left % right, true, false
+
+	default:
+		return 0, true, true
+	}
+}
+
+// CompareNumbers compares the two numbers, returning -1, 0 or 1 if the left
+// number is less than, equal to or greater than the right number, integers
+// and decimals are compared exactly, the numbers are unordered if either of
+// them is NaN.
+func CompareNumbers(left, right Object) (int, bool) {
+	leftInt, leftOk := left.(*Integer)
+	rightInt, rightOk := right.(*Integer)
+
+	switch {
+	case leftOk && rightOk:
+		return cmp.Compare(leftInt.Value, rightInt.Value), true
+	case IsInteger(left) && IsInteger(right):
+		return UnwrapBigIntValue(left).Cmp(UnwrapBigIntValue(right)), true
+	case left.Type() == DECIMAL_OBJ || right.Type() == DECIMAL_OBJ:
+		leftValue, leftErr := UnwrapDecimalValue(left)
+		rightValue, rightErr := UnwrapDecimalValue(right)
+
+		// Floats that can't be converted are NaN or infinite, those are
+		// compared as floats instead.
+		if leftErr == nil && rightErr == nil {
+			return leftValue.Cmp(rightValue), true
+		}
+	}
+
+	leftValue := UnwrapNumberValue(left)
+	rightValue := UnwrapNumberValue(right)
+
+	if math.IsNaN(leftValue) || math.IsNaN(rightValue) {
+		return 0, false
+	}
+
+	return cmp.Compare(leftValue, rightValue), true
+}
+
+// NegateNumber returns the negated number, negating the smallest int64 value
+// is promoted to a BigInt since the result doesn't fit in an int64.
+func NegateNumber(obj Object) Object {
+	switch n := obj.(type) {
+	case *Integer:
+		if n.Value == math.MinInt64 {
+			return WrapBigIntValue(new(big.Int).Neg(big.NewInt(n.Value)))
+		}
+
+		return &Integer{Value: -n.Value}
+	case *BigInt:
+		return WrapBigIntValue(new(big.Int).Neg(n.Value))
+	case *Decimal:
+		return &Decimal{Value: n.Value.Neg()}
+
+	default:
+		return &Float{Value: -UnwrapNumberValue(obj)}
+	}
+}
+
+// ApplyBitwiseOperator applies the given bitwise or shift operator to the two
+// integers, shifting by a negative amount is reported as an error since there
+// is no sensible result for it, shifts that overflow are promoted to a BigInt.
+func ApplyBitwiseOperator(operator string, left, right Object) (Object, error) {
+	leftValue := UnwrapBigIntValue(left)
+	rightValue := UnwrapBigIntValue(right)
+
+	switch operator {
+	case "&":
+		return WrapBigIntValue(new(big.Int).And(leftValue, rightValue)), nil
+	case "|":
+		return WrapBigIntValue(new(big.Int).Or(leftValue, rightValue)), nil
+	case "~":
+		return WrapBigIntValue(new(big.Int).Xor(leftValue, rightValue)), nil
+	case "<<", ">>":
+		if rightValue.Sign() < 0 {
+			return nil, fmt.Errorf("negative shift count: %s", rightValue)
+		}
+
+		// Shifting right by more than the length of the integer always ends
+		// up with the same result, so the shift can be capped to the length.
+		if operator == ">>" {
+			shift := uint(leftValue.BitLen())
+			if rightValue.IsInt64() && rightValue.Int64() < int64(shift) {
+				shift = uint(rightValue.Int64())
+			}
+
+			return WrapBigIntValue(new(big.Int).Rsh(leftValue, shift)), nil
+		}
+
+		if leftValue.Sign() == 0 {
+			return &Integer{Value: 0}, nil
+		}
+
+		if !rightValue.IsInt64() || rightValue.Int64() > MAX_BIGINT_BITS-int64(leftValue.BitLen()) {
+			return nil, fmt.Errorf("integer overflow: %s << %s is too large", leftValue, rightValue)
+		}
+
+		return WrapBigIntValue(new(big.Int).Lsh(leftValue, uint(rightValue.Int64()))), nil
+
+	default:
+		return nil, fmt.Errorf("unknown bitwise operator: %s", operator)
+	}
+}
+
+// BitwiseNot returns the bitwise complement of the integer.
+func BitwiseNot(obj Object) Object {
+	if integer, ok := obj.(*Integer); ok {
+		return &Integer{Value: ^integer.Value}
+	}
+
+	return WrapBigIntValue(new(big.Int).Not(UnwrapBigIntValue(obj)))
+}
+
+func NewRange(start, end Object, inclusive bool) (Object, error) {
+	startInt, startOk := start.(*Integer)
+	endInt, endOk := end.(*Integer)
+
+	if !startOk || !endOk {
+		return nil, fmt.Errorf("range bounds must be integers, got %s and %s", start.Type(), end.Type())
+	}
+
+	return &Range{Start: startInt.Value, End: endInt.Value, Inclusive: inclusive}, nil
+}
+
+// Slice returns a new array or string with the elements between the start and
+// end bounds, negative bounds count from the end and null bounds default to
+// the start and end of the value, bounds outside the value are clamped.
+func Slice(obj, start, end Object) (Object, error) {
+	switch obj := obj.(type) {
+	case *Array:
+		from, to, err := resolveSliceBounds(start, end, len(obj.Elements))
+		if err != nil {
+			return nil, err
+		}
+
+		elements := make([]Object, to-from)
+		copy(elements, obj.Elements[from:to])
+
+		return &Array{Elements: elements}, nil
+	case *String:
+		runes := []rune(obj.Value)
+
+		from, to, err := resolveSliceBounds(start, end, len(runes))
+		if err != nil {
+			return nil, err
+		}
+
+		return &String{Value: string(runes[from:to])}, nil
+
+	default:
+		return nil, fmt.Errorf("slice operator not supported: %s", obj.Type())
+	}
+}
+
+// StringIndex returns the character at the index of the string, the index is
+// counted in Unicode code points rather than bytes, so multi-byte characters
+// are returned whole, negative indexes are counted from the end of the string.
+func StringIndex(str *String, index int64) (*String, bool) {
+	runes := []rune(str.Value)
+
+	if index < 0 {
+		index += int64(len(runes))
+	}
+
+	if index < 0 || index >= int64(len(runes)) {
+		return nil, false
+	}
+
+	return &String{Value: string(runes[index])}, true
+}
+
+func resolveSliceBounds(start, end Object, length int) (int, int, error) {
+	from, err := resolveSliceBound(start, 0, length)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to, err := resolveSliceBound(end, length, length)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if from > to {
+		from = to
+	}
+
+	return from, to, nil
+}
+
+func resolveSliceBound(bound Object, fallback, length int) (int, error) {
+	switch bound := bound.(type) {
+	case *Null:
+		return fallback, nil
+	case *Integer:
+		index := bound.Value
+		if index < 0 {
+			index += int64(length)
+		}
+
+		return int(max(0, min(index, int64(length)))), nil
+
+	default:
+		return 0, fmt.Errorf("slice bounds must be integers, got %s", bound.Type())
+	}
+}
+
 func IsStringable(obj Object) bool {
 	switch obj.(type) {
-	case *Integer, *Float, *Boolean:
+	case *Integer, *BigInt, *Float, *Decimal, *Boolean:
 		return true
 
 	default:
@@ -128,8 +691,12 @@ func StringifyObject(obj Object) string {
 		return obj.Value
 	case *Integer:
 		return fmt.Sprintf("%d", obj.Value)
+	case *BigInt:
+		return obj.Value.String()
 	case *Float:
 		return fmt.Sprintf("%g", obj.Value)
+	case *Decimal:
+		return obj.Value.String()
 	case *Boolean:
 		if obj.Value {
 			return "true"
@@ -167,8 +734,12 @@ func Equals(left, right Object) *Boolean {
 		return NativeBoolToBooleanObject(left.Value == right.(*String).Value)
 	case *Integer:
 		return NativeBoolToBooleanObject(left.Value == right.(*Integer).Value)
+	case *BigInt:
+		return NativeBoolToBooleanObject(left.Value.Cmp(right.(*BigInt).Value) == 0)
 	case *Float:
 		return NativeBoolToBooleanObject(left.Value == right.(*Float).Value)
+	case *Decimal:
+		return NativeBoolToBooleanObject(left.Value.Cmp(right.(*Decimal).Value) == 0)
 	case *Array:
 		rightArr := right.(*Array)
 		if len(left.Elements) != len(rightArr.Elements) {
@@ -197,6 +768,40 @@ func Equals(left, right Object) *Boolean {
 		}
 
 		return TRUE
+	case *StructInstance:
+		rightInstance := right.(*StructInstance)
+		if left.Struct != rightInstance.Struct {
+			return FALSE
+		}
+
+		for field, leftValue := range left.Fields {
+			if Equals(leftValue, rightInstance.Fields[field]) != TRUE {
+				return FALSE
+			}
+		}
+
+		return TRUE
+	case *EnumVariant:
+		rightVariant := right.(*EnumVariant)
+		if left.Enum != rightVariant.Enum || left.Name != rightVariant.Name {
+			return FALSE
+		}
+
+		if len(left.Values) != len(rightVariant.Values) {
+			return FALSE
+		}
+
+		for i, leftValue := range left.Values {
+			if Equals(leftValue, rightVariant.Values[i]) != TRUE {
+				return FALSE
+			}
+		}
+
+		return TRUE
+	case *Range:
+		rightRange := right.(*Range)
+
+		return NativeBoolToBooleanObject(left.Start == rightRange.Start && left.exclusiveEnd() == rightRange.exclusiveEnd())
 	case *Null:
 		return TRUE
 
@@ -205,6 +810,101 @@ func Equals(left, right Object) *Boolean {
 	}
 }
 
+func NewIterator(obj Object) (*Iterator, error) {
+	index := 0
+
+	switch obj := obj.(type) {
+	case *Array:
+		return &Iterator{next: func() (Object, Object, bool) {
+			if index >= len(obj.Elements) {
+				return nil, nil, false
+			}
+
+			index++
+			return &Integer{Value: int64(index - 1)}, obj.Elements[index-1], true
+		}}, nil
+	case *Hash:
+		return newHashIterator(obj), nil
+	case *ImmutableHash:
+		return newHashIterator(&obj.Value), nil
+	case *Range:
+		length := obj.Len()
+
+		return &Iterator{next: func() (Object, Object, bool) {
+			if int64(index) >= length {
+				return nil, nil, false
+			}
+
+			index++
+			return &Integer{Value: int64(index - 1)}, &Integer{Value: obj.Start + int64(index-1)}, true
+		}}, nil
+	case *String:
+		runes := []rune(obj.Value)
+
+		return &Iterator{next: func() (Object, Object, bool) {
+			if index >= len(runes) {
+				return nil, nil, false
+			}
+
+			index++
+			return &Integer{Value: int64(index - 1)}, &String{Value: string(runes[index-1])}, true
+		}}, nil
+	case *Generator:
+		return newGeneratorIterator(obj), nil
+	case *Channel:
+		return &Iterator{next: func() (Object, Object, bool) {
+			value, ok := obj.Receive()
+			if !ok {
+				return nil, nil, false
+			}
+
+			index++
+			return &Integer{Value: int64(index - 1)}, value, true
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", obj.Type())
+	}
+}
+
+// newGeneratorIterator creates an iterator that resumes the generator for
+// every element, any errors raised by the generator stops the iterator.
+func newGeneratorIterator(generator *Generator) *Iterator {
+	index := 0
+	iterator := &Iterator{}
+
+	iterator.next = func() (Object, Object, bool) {
+		value, ok, err := generator.Next()
+		if err != nil {
+			iterator.err = err
+			return nil, nil, false
+		}
+
+		if !ok {
+			return nil, nil, false
+		}
+
+		index++
+		return &Integer{Value: int64(index - 1)}, value, true
+	}
+
+	return iterator
+}
+
+func newHashIterator(hash *Hash) *Iterator {
+	pairs := hash.OrderedPairs()
+	index := 0
+
+	return &Iterator{next: func() (Object, Object, bool) {
+		if index >= len(pairs) {
+			return nil, nil, false
+		}
+
+		index++
+		return pairs[index-1].Key, pairs[index-1].Value, true
+	}}
+}
+
 func CreateImmutableHashFromEnvExports(env *Environment) *ImmutableHash {
 	hashPairs := []HashPair{}
 
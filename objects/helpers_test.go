@@ -2,10 +2,13 @@ package objects
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/senither/zen-lang/ast"
+	"github.com/senither/zen-lang/objects/decimal"
 	"github.com/senither/zen-lang/tokens"
 )
 
@@ -170,6 +173,44 @@ func TestNewEmptyErrorWithParent(t *testing.T) {
 	}
 }
 
+func TestErrorToHash(t *testing.T) {
+	located := ErrorToHash(&Error{
+		Parent: &Error{Message: "failed", File: "file.zen", Line: 2, Column: 5},
+	})
+
+	for key, expected := range map[string]any{"message": "failed", "file": "file.zen", "line": 2, "column": 5} {
+		pair, ok := located.Pairs[(&String{Value: key}).HashKey()]
+		if !ok {
+			t.Fatalf("expected error hash to have the key %q", key)
+		}
+
+		AssertExpectedObject(t, expected, pair.Value)
+	}
+
+	unlocated := ErrorToHash(NativeErrorToErrorObject(fmt.Errorf("failed")))
+	for key, expected := range map[string]any{"message": "failed", "file": nil, "line": nil, "column": nil} {
+		pair, ok := unlocated.Pairs[(&String{Value: key}).HashKey()]
+		if !ok {
+			t.Fatalf("expected error hash without a position to have the key %q", key)
+		}
+
+		AssertExpectedObject(t, expected, pair.Value)
+	}
+
+	thrown := &Hash{Pairs: make(map[HashKey]HashPair)}
+	thrown.Pairs[(&String{Value: "code"}).HashKey()] = HashPair{Key: &String{Value: "code"}, Value: &Integer{Value: 5}}
+
+	custom := ErrorToHash(NativeErrorToErrorObject(&ThrownError{Value: thrown}))
+	for key, expected := range map[string]any{"code": 5, "message": nil, "file": nil, "line": nil, "column": nil} {
+		pair, ok := custom.Pairs[(&String{Value: key}).HashKey()]
+		if !ok {
+			t.Fatalf("expected error hash for a thrown hash to have the key %q", key)
+		}
+
+		AssertExpectedObject(t, expected, pair.Value)
+	}
+}
+
 func TestIsError(t *testing.T) {
 	if !IsError(&Error{}) {
 		t.Errorf("expected IsError to return true for Error object")
@@ -234,6 +275,7 @@ func TestIsNumber(t *testing.T) {
 		expected bool
 	}{
 		{"integer", INTEGER_OBJ, true},
+		{"big integer", BIGINT_OBJ, true},
 		{"float", FLOAT_OBJ, true},
 		{"string", STRING_OBJ, false},
 		{"boolean", BOOLEAN_OBJ, false},
@@ -290,6 +332,7 @@ func TestUnwrapNumberValue(t *testing.T) {
 		expected float64
 	}{
 		{"integer", &Integer{Value: 10}, 10.0},
+		{"big integer", &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}, 18446744073709551616.0},
 		{"float", &Float{Value: 3.14}, 3.14},
 		{"string", &String{Value: "hello"}, 0.0},
 		{"true", TRUE, 0.0},
@@ -310,6 +353,300 @@ func TestUnwrapNumberValue(t *testing.T) {
 	}
 }
 
+func TestApplyNumberOperator(t *testing.T) {
+	maxInt := &Integer{Value: math.MaxInt64}
+	minInt := &Integer{Value: math.MinInt64}
+
+	tests := []struct {
+		name     string
+		operator string
+		left     Object
+		right    Object
+		expected string
+		err      string
+	}{
+		{"integer addition", "+", &Integer{Value: 2}, &Integer{Value: 3}, "5", ""},
+		{"integer division", "/", &Integer{Value: 6}, &Integer{Value: 3}, "2", ""},
+		{"inexact integer division", "/", &Integer{Value: 7}, &Integer{Value: 2}, "3.500000", ""},
+		{"division by zero", "/", &Integer{Value: 1}, &Integer{Value: 0}, "+Inf", ""},
+		{"negative exponent", "^", &Integer{Value: 2}, &Integer{Value: -1}, "0.500000", ""},
+		{"float addition", "+", &Integer{Value: 2}, &Float{Value: 0.5}, "2.500000", ""},
+		{"addition overflow", "+", maxInt, &Integer{Value: 1}, "9223372036854775808", ""},
+		{"subtraction overflow", "-", minInt, &Integer{Value: 1}, "-9223372036854775809", ""},
+		{"multiplication overflow", "*", maxInt, &Integer{Value: 2}, "18446744073709551614", ""},
+		{"division overflow", "/", minInt, &Integer{Value: -1}, "9223372036854775808", ""},
+		{"exponent overflow", "^", &Integer{Value: 2}, &Integer{Value: 64}, "18446744073709551616", ""},
+		{"big integer back to integer", "-", &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}, &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}, "0", ""},
+		{"big integer modulo", "%", &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}, &Integer{Value: 10}, "6", ""},
+		{"exponent too large", "^", &Integer{Value: 2}, &Integer{Value: math.MaxInt64}, "", "integer overflow: 2 ^ 9223372036854775807 is too large"},
+		{"decimal addition", "+", &Decimal{Value: decimal.MustParse("0.1")}, &Decimal{Value: decimal.MustParse("0.2")}, "0.3", ""},
+		{"decimal and integer", "*", &Decimal{Value: decimal.MustParse("19.99")}, &Integer{Value: 3}, "59.97", ""},
+		{"decimal and float", "+", &Float{Value: 0.1}, &Decimal{Value: decimal.MustParse("0.2")}, "0.3", ""},
+		{"decimal division", "/", &Integer{Value: 1}, &Decimal{Value: decimal.MustParse("3")}, "0.3333333333333333", ""},
+		{"decimal exponent", "^", &Decimal{Value: decimal.MustParse("1.1")}, &Integer{Value: 2}, "1.21", ""},
+		{"decimal division by zero", "/", &Decimal{Value: decimal.MustParse("1")}, &Integer{Value: 0}, "", "division by zero"},
+		{"decimal fractional exponent", "^", &Integer{Value: 2}, &Decimal{Value: decimal.MustParse("0.5")}, "", "decimal exponents must be integers, got 0.5"},
+		{"decimal and not a number", "+", &Decimal{Value: decimal.MustParse("1")}, &Float{Value: math.NaN()}, "", "cannot convert NaN to a decimal"},
+		{"unknown operator", "&", &Integer{Value: 1}, &Integer{Value: 2}, "", "unknown number operator: &"},
+	}
+
+	for _, tt := range tests {
+		t.Run("number operator: "+tt.name, func(t *testing.T) {
+			result, err := ApplyNumberOperator(tt.operator, tt.left, tt.right)
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("ApplyNumberOperator() error = %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyNumberOperator() returned unexpected error: %v", err)
+			}
+
+			if result.Inspect() != tt.expected {
+				t.Errorf("ApplyNumberOperator() = %s, want %s", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	big1 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}
+	big2 := &BigInt{Value: new(big.Int).Add(big1.Value, big.NewInt(1))}
+
+	tests := []struct {
+		name     string
+		left     Object
+		right    Object
+		expected int
+		ordered  bool
+	}{
+		{"integers", &Integer{Value: 1}, &Integer{Value: 2}, -1, true},
+		{"big integers", big2, big1, 1, true},
+		{"integer and big integer", &Integer{Value: math.MaxInt64}, big1, -1, true},
+		{"integer and float", &Integer{Value: 2}, &Float{Value: 2.0}, 0, true},
+		{"not a number", &Float{Value: math.NaN()}, &Integer{Value: 1}, 0, false},
+		{"decimals", &Decimal{Value: decimal.MustParse("1.50")}, &Decimal{Value: decimal.MustParse("1.5")}, 0, true},
+		{"decimal and float", &Decimal{Value: decimal.MustParse("0.3")}, &Float{Value: 0.30000000000000004}, -1, true},
+		{"decimal and big integer", &Decimal{Value: decimal.MustParse("0.5")}, big1, -1, true},
+		{"decimal and not a number", &Decimal{Value: decimal.MustParse("1")}, &Float{Value: math.NaN()}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run("compare numbers: "+tt.name, func(t *testing.T) {
+			result, ordered := CompareNumbers(tt.left, tt.right)
+
+			if result != tt.expected || ordered != tt.ordered {
+				t.Errorf("CompareNumbers() = %d, %t, want %d, %t", result, ordered, tt.expected, tt.ordered)
+			}
+		})
+	}
+}
+
+func TestWrapBigIntValue(t *testing.T) {
+	if result := WrapBigIntValue(big.NewInt(42)); !reflect.DeepEqual(result, &Integer{Value: 42}) {
+		t.Errorf("WrapBigIntValue(42) = %v[%s], want Integer", result.Inspect(), result.Type())
+	}
+
+	value := new(big.Int).Lsh(big.NewInt(1), 64)
+	if result := WrapBigIntValue(value); result.Type() != BIGINT_OBJ || result.Inspect() != value.String() {
+		t.Errorf("WrapBigIntValue(%s) = %v[%s], want BigInt", value, result.Inspect(), result.Type())
+	}
+}
+
+func TestNegateNumber(t *testing.T) {
+	tests := []struct {
+		input    Object
+		expected string
+	}{
+		{&Integer{Value: 5}, "-5"},
+		{&Float{Value: 2.5}, "-2.500000"},
+		{&Integer{Value: math.MinInt64}, "9223372036854775808"},
+		{&BigInt{Value: new(big.Int).Neg(new(big.Int).SetUint64(1 << 63))}, "9223372036854775808"},
+		{&BigInt{Value: new(big.Int).SetUint64(1 << 63)}, "-9223372036854775808"},
+		{&Decimal{Value: decimal.MustParse("19.99")}, "-19.99"},
+	}
+
+	for _, tt := range tests {
+		t.Run("negate number: "+tt.input.Inspect(), func(t *testing.T) {
+			result := NegateNumber(tt.input)
+
+			if result.Inspect() != tt.expected {
+				t.Errorf("NegateNumber(%s) = %s, want %s", tt.input.Inspect(), result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyBitwiseOperator(t *testing.T) {
+	tests := []struct {
+		operator string
+		left     int64
+		right    int64
+		expected string
+		err      string
+	}{
+		{"&", 12, 10, "8", ""},
+		{"|", 12, 10, "14", ""},
+		{"~", 12, 10, "6", ""},
+		{"<<", 1, 4, "16", ""},
+		{">>", 256, 4, "16", ""},
+		{">>", -16, 2, "-4", ""},
+		{">>", -16, 100, "-1", ""},
+		{"<<", 1, 64, "18446744073709551616", ""},
+		{"<<", 1, -1, "", "negative shift count: -1"},
+		{">>", 1, -2, "", "negative shift count: -2"},
+		{"<<", 1, math.MaxInt64, "", "integer overflow: 1 << 9223372036854775807 is too large"},
+		{"^", 1, 2, "", "unknown bitwise operator: ^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("bitwise operator: %d %s %d", tt.left, tt.operator, tt.right), func(t *testing.T) {
+			result, err := ApplyBitwiseOperator(tt.operator, &Integer{Value: tt.left}, &Integer{Value: tt.right})
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("ApplyBitwiseOperator() error = %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyBitwiseOperator() returned unexpected error: %v", err)
+			}
+
+			if result.Inspect() != tt.expected {
+				t.Errorf("ApplyBitwiseOperator() = %s, want %s", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     Object
+		end       Object
+		inclusive bool
+		inspect   string
+		length    int64
+		err       string
+	}{
+		{"inclusive range", &Integer{Value: 1}, &Integer{Value: 5}, true, "1..5", 5, ""},
+		{"exclusive range", &Integer{Value: 1}, &Integer{Value: 5}, false, "1..<5", 4, ""},
+		{"empty inclusive range", &Integer{Value: 5}, &Integer{Value: 1}, true, "5..1", 0, ""},
+		{"empty exclusive range", &Integer{Value: 3}, &Integer{Value: 3}, false, "3..<3", 0, ""},
+		{"float start", &Float{Value: 1.5}, &Integer{Value: 5}, true, "", 0, "range bounds must be integers, got FLOAT and INTEGER"},
+		{"null end", &Integer{Value: 1}, NULL, false, "", 0, "range bounds must be integers, got INTEGER and NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run("new range: "+tt.name, func(t *testing.T) {
+			result, err := NewRange(tt.start, tt.end, tt.inclusive)
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("NewRange() error = %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			rng, ok := result.(*Range)
+			if !ok {
+				t.Fatalf("NewRange() returned %T, want *Range", result)
+			}
+
+			if rng.Inspect() != tt.inspect {
+				t.Errorf("Inspect() = %q, want %q", rng.Inspect(), tt.inspect)
+			}
+
+			if rng.Len() != tt.length || int64(len(rng.Elements())) != tt.length {
+				t.Errorf("Len() = %d with %d elements, want %d", rng.Len(), len(rng.Elements()), tt.length)
+			}
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	array := &Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}, &Integer{Value: 3}}}
+
+	tests := []struct {
+		name     string
+		input    Object
+		start    Object
+		end      Object
+		expected string
+		err      string
+	}{
+		{"array with both bounds", array, &Integer{Value: 1}, &Integer{Value: 2}, "[2]", ""},
+		{"array without bounds", array, NULL, NULL, "[1, 2, 3]", ""},
+		{"array with negative start", array, &Integer{Value: -2}, NULL, "[2, 3]", ""},
+		{"array with bounds out of range", array, &Integer{Value: -10}, &Integer{Value: 10}, "[1, 2, 3]", ""},
+		{"array with start after end", array, &Integer{Value: 2}, &Integer{Value: 0}, "[]", ""},
+		{"string with both bounds", &String{Value: "hello"}, &Integer{Value: 1}, &Integer{Value: 3}, "el", ""},
+		{"string with negative end", &String{Value: "hello"}, NULL, &Integer{Value: -1}, "hell", ""},
+		{"string with multi-byte characters", &String{Value: "héllo"}, &Integer{Value: 1}, &Integer{Value: 3}, "él", ""},
+		{"float bounds", array, &Float{Value: 1.5}, NULL, "", "slice bounds must be integers, got FLOAT"},
+		{"unsupported type", &Integer{Value: 1}, NULL, NULL, "", "slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		t.Run("slice: "+tt.name, func(t *testing.T) {
+			result, err := Slice(tt.input, tt.start, tt.end)
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("Slice() error = %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Slice() returned unexpected error: %v", err)
+			}
+
+			if result.Inspect() != tt.expected {
+				t.Errorf("Slice() = %q, want %q", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestStringIndex(t *testing.T) {
+	str := &String{Value: "añ世"}
+
+	tests := []struct {
+		index    int64
+		expected string
+		ok       bool
+	}{
+		{0, "a", true},
+		{1, "ñ", true},
+		{2, "世", true},
+		{-1, "世", true},
+		{-3, "a", true},
+		{3, "", false},
+		{-4, "", false},
+	}
+
+	for _, tt := range tests {
+		char, ok := StringIndex(str, tt.index)
+		if ok != tt.ok {
+			t.Fatalf("StringIndex(%d) ok = %v, want %v", tt.index, ok, tt.ok)
+		}
+
+		if ok && char.Value != tt.expected {
+			t.Errorf("StringIndex(%d) = %q, want %q", tt.index, char.Value, tt.expected)
+		}
+	}
+}
+
 func TestIsStringable(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -429,6 +766,8 @@ func TestEquals(t *testing.T) {
 	}{
 		{"integer", &Integer{Value: 10}, &Integer{Value: 10}, TRUE},
 		{"float", &Float{Value: 3.14}, &Float{Value: 3.14}, TRUE},
+		{"decimal", &Decimal{Value: decimal.MustParse("1.50")}, &Decimal{Value: decimal.MustParse("1.5")}, TRUE},
+		{"different decimals", &Decimal{Value: decimal.MustParse("1.5")}, &Decimal{Value: decimal.MustParse("1.51")}, FALSE},
 		{"string", &String{Value: "hello"}, &String{Value: "hello"}, TRUE},
 		{
 			"array",
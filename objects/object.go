@@ -2,14 +2,21 @@ package objects
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"math/big"
 	"os"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/senither/zen-lang/ast"
 	"github.com/senither/zen-lang/code"
+	"github.com/senither/zen-lang/objects/decimal"
+	"github.com/senither/zen-lang/objects/scheduler"
+	"github.com/senither/zen-lang/objects/timer"
 )
 
 type ObjectType string
@@ -20,7 +27,9 @@ const (
 
 	STRING_OBJ  = "STRING"
 	INTEGER_OBJ = "INTEGER"
+	BIGINT_OBJ  = "BIGINT"
 	FLOAT_OBJ   = "FLOAT"
+	DECIMAL_OBJ = "DECIMAL"
 	BOOLEAN_OBJ = "BOOLEAN"
 
 	ARRAY_OBJ          = "ARRAY"
@@ -32,11 +41,27 @@ const (
 	BREAK_OBJ    = "BREAK"
 	CONTINUE_OBJ = "CONTINUE"
 
-	FUNCTION_OBJ = "FUNCTION"
-	BUILTIN_OBJ  = "BUILTIN"
+	ITERATOR_OBJ  = "ITERATOR"
+	GENERATOR_OBJ = "GENERATOR"
+	TASK_OBJ      = "TASK"
+	CHANNEL_OBJ   = "CHANNEL"
+	PROMISE_OBJ   = "PROMISE"
+	SPREAD_OBJ    = "SPREAD"
+	RANGE_OBJ     = "RANGE"
+
+	FUNCTION_OBJ     = "FUNCTION"
+	BUILTIN_OBJ      = "BUILTIN"
+	BOUND_METHOD_OBJ = "BOUND_METHOD"
+
+	STRUCT_OBJ          = "STRUCT"
+	STRUCT_INSTANCE_OBJ = "STRUCT_INSTANCE"
+
+	ENUM_OBJ         = "ENUM"
+	ENUM_VARIANT_OBJ = "ENUM_VARIANT"
 
 	COMPILED_FUNCTION_OBJ = "COMPILED_FUNCTION"
 	CLOSURE_OBJ           = "CLOSURE"
+	UPVALUE_OBJ           = "UPVALUE"
 
 	IMPORTED_CLOSURE_OBJ          = "IMPORTED_CLOSURE"
 	COMPILED_ZEN_FILE_IMPORT_OBJ  = "COMPILED_ZEN_FILE_IMPORT"
@@ -95,6 +120,37 @@ func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 func (i *Integer) HashKey() HashKey { return HashKey{Type: i.Type(), Value: uint64(i.Value)} }
 
+// BigInt holds integers that don't fit in an int64, integer operations that
+// overflow are promoted to a BigInt, and are turned back into an Integer as
+// soon as the result fits again, see WrapBigIntValue.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+// Decimal holds exact decimal numbers, like 19.99d, which don't have the
+// rounding errors floats have, making them suitable for money calculations.
+type Decimal struct {
+	Value decimal.Decimal
+}
+
+func (d *Decimal) Type() ObjectType { return DECIMAL_OBJ }
+func (d *Decimal) Inspect() string  { return d.Value.String() }
+func (d *Decimal) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(d.Value.Normalize().String()))
+
+	return HashKey{Type: d.Type(), Value: h.Sum64()}
+}
+
 type Float struct {
 	Value float64
 }
@@ -147,10 +203,9 @@ func (a *Array) Len() int      { return len(a.Elements) }
 func (a *Array) Swap(i, j int) { a.Elements[i], a.Elements[j] = a.Elements[j], a.Elements[i] }
 func (a *Array) Less(i, j int) bool {
 	if IsNumber(a.Elements[i].Type()) && IsNumber(a.Elements[j].Type()) {
-		leftValue := UnwrapNumberValue(a.Elements[i])
-		rightValue := UnwrapNumberValue(a.Elements[j])
+		cmp, ordered := CompareNumbers(a.Elements[i], a.Elements[j])
 
-		return leftValue < rightValue
+		return ordered && cmp < 0
 	}
 
 	return a.Elements[i].Inspect() < a.Elements[j].Inspect()
@@ -169,6 +224,21 @@ func (h *Hash) Type() ObjectType { return HASH_OBJ }
 func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 
+	pairs := []string{}
+	for _, pair := range h.OrderedPairs() {
+		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// OrderedPairs returns the hash pairs sorted by their hash keys, giving
+// a stable order for printing and iterating over the hash.
+func (h *Hash) OrderedPairs() []HashPair {
 	keys := make([]HashKey, 0, len(h.Pairs))
 	for k := range h.Pairs {
 		keys = append(keys, k)
@@ -181,17 +251,12 @@ func (h *Hash) Inspect() string {
 		return keys[i].Type < keys[j].Type
 	})
 
-	pairs := []string{}
-	for _, k := range keys {
-		pair := h.Pairs[k]
-		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	pairs := make([]HashPair, len(keys))
+	for i, k := range keys {
+		pairs[i] = h.Pairs[k]
 	}
 
-	out.WriteString("{")
-	out.WriteString(strings.Join(pairs, ", "))
-	out.WriteString("}")
-
-	return out.String()
+	return pairs
 }
 
 type ImmutableHash struct {
@@ -215,6 +280,7 @@ type Error struct {
 	Line    int
 	Column  int
 	Parent  *Error
+	Thrown  Object
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
@@ -260,11 +326,817 @@ type Continue struct{}
 func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
 func (c *Continue) Inspect() string  { return "continue" }
 
+type Iterator struct {
+	next func() (Object, Object, bool)
+	err  error
+}
+
+func (i *Iterator) Type() ObjectType { return ITERATOR_OBJ }
+func (i *Iterator) Inspect() string  { return fmt.Sprintf("Iterator[%p]", i) }
+
+// Next returns the key and value of the next element, the boolean
+// is false once the iterator has been exhausted.
+func (i *Iterator) Next() (Object, Object, bool) { return i.next() }
+
+// Err returns the error that stopped the iterator, if any.
+func (i *Iterator) Err() error { return i.err }
+
+// Generator is a suspended generator function, calling resume runs the
+// function until it yields its next value, the boolean is false once
+// the function has returned, after which the generator is exhausted.
+type Generator struct {
+	Name   string
+	resume func() (Object, bool, error)
+	done   bool
+}
+
+func NewGenerator(name string, resume func() (Object, bool, error)) *Generator {
+	return &Generator{Name: name, resume: resume}
+}
+
+func (g *Generator) Type() ObjectType { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string {
+	if len(g.Name) > 0 {
+		return fmt.Sprintf("Generator[%s|%p]", g.Name, g)
+	}
+
+	return fmt.Sprintf("Generator[%p]", g)
+}
+
+// Next resumes the generator and returns the next value it yields.
+func (g *Generator) Next() (Object, bool, error) {
+	if g.done {
+		return NULL, false, nil
+	}
+
+	value, ok, err := g.resume()
+	if !ok || err != nil {
+		g.done = true
+		return NULL, false, err
+	}
+
+	return value, true, nil
+}
+
+// NextResult resumes the generator and returns the result as a hash holding
+// the yielded value, and whether the generator has finished.
+func (g *Generator) NextResult() (Object, error) {
+	value, ok, err := g.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := &Hash{Pairs: make(map[HashKey]HashPair)}
+	for _, pair := range []HashPair{
+		{Key: &String{Value: "value"}, Value: value},
+		{Key: &String{Value: "done"}, Value: NativeBoolToBooleanObject(!ok)},
+	} {
+		hash.Pairs[pair.Key.(Hashable).HashKey()] = pair
+	}
+
+	return hash, nil
+}
+
+// Get returns the method with the given name bound to the generator.
+func (g *Generator) Get(name string) (Object, error) {
+	if name != "next" {
+		return nil, fmt.Errorf("generator has no method named %q", name)
+	}
+
+	return &Builtin{Fn: func(args ...Object) (Object, error) {
+		if len(args) != 0 {
+			return nil, NewWrongNumberOfArgumentsError("next", 0, len(args))
+		}
+
+		return g.NextResult()
+	}}, nil
+}
+
+// Task is a function call running concurrently in a goroutine of its own,
+// the result of the call can be collected once it has finished running.
+type Task struct {
+	done   chan struct{}
+	result Object
+}
+
+// NewTask starts running the function in a new goroutine, the goroutine
+// only runs while it holds the interpreter lock.
+func NewTask(fn func() Object) *Task {
+	task := &Task{done: make(chan struct{})}
+
+	scheduler.Go(func() {
+		task.result = fn()
+		close(task.done)
+	})
+
+	return task
+}
+
+func (t *Task) Type() ObjectType { return TASK_OBJ }
+func (t *Task) Inspect() string  { return fmt.Sprintf("Task[%p]", t) }
+
+// Await blocks until the task has finished running and returns its result,
+// errors raised within the task are returned as the result.
+func (t *Task) Await() Object {
+	scheduler.Block(func() {
+		<-t.done
+	})
+
+	return t.result
+}
+
+// Done returns true if the task has finished running.
+func (t *Task) Done() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Get returns the method with the given name bound to the task.
+func (t *Task) Get(name string) (Object, error) {
+	switch name {
+	case "await":
+		return &Builtin{Fn: func(args ...Object) (Object, error) {
+			if len(args) != 0 {
+				return nil, NewWrongNumberOfArgumentsError("await", 0, len(args))
+			}
+
+			result := t.Await()
+			if err, ok := result.(*Error); ok {
+				return nil, errors.New(err.Message)
+			}
+
+			return result, nil
+		}}, nil
+	case "done":
+		return &Builtin{Fn: func(args ...Object) (Object, error) {
+			if len(args) != 0 {
+				return nil, NewWrongNumberOfArgumentsError("done", 0, len(args))
+			}
+
+			return NativeBoolToBooleanObject(t.Done()), nil
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("task has no method named %q", name)
+	}
+}
+
+// Channel is used to pass values between tasks, sending a value blocks until
+// it has been received, unless the channel has room for it in its buffer.
+type Channel struct {
+	values chan Object
+	done   chan struct{}
+	closed bool
+}
+
+func NewChannel(capacity int) *Channel {
+	return &Channel{
+		values: make(chan Object, capacity),
+		done:   make(chan struct{}),
+	}
+}
+
+func (c *Channel) Type() ObjectType { return CHANNEL_OBJ }
+func (c *Channel) Inspect() string  { return fmt.Sprintf("Channel[%p]", c) }
+
+// Send blocks until the value has been sent, or the channel is closed.
+func (c *Channel) Send(value Object) error {
+	if c.closed {
+		return errors.New("cannot send on a closed channel")
+	}
+
+	var err error
+	scheduler.Block(func() {
+		select {
+		case c.values <- value:
+		case <-c.done:
+			err = errors.New("cannot send on a closed channel")
+		}
+	})
+
+	return err
+}
+
+// Receive blocks until a value has been received, the boolean is false
+// once the channel has been closed and every value has been received.
+func (c *Channel) Receive() (Object, bool) {
+	var value Object = NULL
+	ok := false
+
+	scheduler.Block(func() {
+		select {
+		case value = <-c.values:
+			ok = true
+		case <-c.done:
+			value, ok = c.drain()
+		}
+	})
+
+	return value, ok
+}
+
+// drain returns the next value left in the buffer of the closed channel.
+func (c *Channel) drain() (Object, bool) {
+	select {
+	case value := <-c.values:
+		return value, true
+	default:
+		return NULL, false
+	}
+}
+
+// Close closes the channel, values already sent can still be received.
+func (c *Channel) Close() error {
+	if c.closed {
+		return errors.New("channel is already closed")
+	}
+
+	c.closed = true
+	close(c.done)
+
+	return nil
+}
+
+// SelectChannel blocks until one of the channels has a value that can be
+// received, or has been closed, returning the index of the channel.
+func SelectChannel(channels []*Channel) (int, Object, bool) {
+	cases := make([]reflect.SelectCase, 0, len(channels)*2)
+	for _, channel := range channels {
+		cases = append(
+			cases,
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel.values)},
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel.done)},
+		)
+	}
+
+	var chosen int
+	var received reflect.Value
+
+	scheduler.Block(func() {
+		chosen, received, _ = reflect.Select(cases)
+	})
+
+	channel := channels[chosen/2]
+	if chosen%2 == 1 {
+		value, ok := channel.drain()
+		return chosen / 2, value, ok
+	}
+
+	return chosen / 2, received.Interface().(Object), true
+}
+
+type PromiseState int
+
+const (
+	PromisePending PromiseState = iota
+	PromiseFulfilled
+	PromiseRejected
+)
+
+// Promise is the eventual result of an async function, the handlers waiting
+// on the promise are run by the event loop once the promise is settled.
+type Promise struct {
+	State    PromiseState
+	value    Object
+	handlers []func()
+	handled  bool
+}
+
+func NewPromise() *Promise {
+	return &Promise{State: PromisePending}
+}
+
+// NewAsyncPromise runs the function as a coroutine, which is suspended every
+// time it awaits a pending promise, and returns a promise that is settled
+// with the result of the function once it returns.
+func NewAsyncPromise(fn func() Object) *Promise {
+	promise := NewPromise()
+
+	scheduler.StartCoroutine(func() {
+		promise.Resolve(fn())
+	})
+
+	return promise
+}
+
+func (p *Promise) Type() ObjectType { return PROMISE_OBJ }
+func (p *Promise) Inspect() string  { return fmt.Sprintf("Promise[%p]", p) }
+
+// Resolve settles the promise with the value, error values reject the promise,
+// while promises are adopted, settling this promise once the other one is.
+func (p *Promise) Resolve(value Object) {
+	if p.State != PromisePending {
+		return
+	}
+
+	switch value := value.(type) {
+	case *Promise:
+		value.OnSettled(func() {
+			p.settle(value.State, value.value)
+		})
+	case *Error:
+		p.settle(PromiseRejected, value)
+
+	default:
+		p.settle(PromiseFulfilled, value)
+	}
+}
+
+func (p *Promise) Reject(err *Error) {
+	if p.State != PromisePending {
+		return
+	}
+
+	p.settle(PromiseRejected, err)
+}
+
+func (p *Promise) settle(state PromiseState, value Object) {
+	p.State = state
+	p.value = value
+
+	for _, handler := range p.handlers {
+		timer.StartDelayedTimer(handler, 0)
+	}
+
+	p.handlers = nil
+
+	// Rejections are reported like errors raised by timer callbacks if no
+	// handlers have been attached by the time the event loop gets to them,
+	// so handlers attached right after the promise was created still count.
+	if state == PromiseRejected {
+		timer.StartDelayedTimer(func() {
+			if !p.handled {
+				fmt.Fprintf(os.Stdout, "%s\n", p.value.Inspect())
+			}
+		}, 0)
+	}
+}
+
+// OnSettled queues the handler to run on the event loop once the promise
+// has been settled, or right away if the promise is already settled.
+func (p *Promise) OnSettled(handler func()) {
+	p.handled = true
+
+	if p.State != PromisePending {
+		timer.StartDelayedTimer(handler, 0)
+		return
+	}
+
+	p.handlers = append(p.handlers, handler)
+}
+
+func (p *Promise) Settled() bool {
+	return p.State != PromisePending
+}
+
+// Result returns the value the promise was fulfilled with, or the error it
+// was rejected with, pending promises have no result yet.
+func (p *Promise) Result() Object {
+	if p.value == nil {
+		return NULL
+	}
+
+	return p.value
+}
+
+// Await waits for the promise to be settled and returns its result, within a
+// coroutine the coroutine is suspended until then, otherwise the event loop
+// is run until the promise has been settled.
+func (p *Promise) Await() Object {
+	p.handled = true
+
+	if p.Settled() {
+		return p.Result()
+	}
+
+	if co := scheduler.Current(); co != nil {
+		p.OnSettled(co.Resume)
+		scheduler.Suspend()
+
+		return p.Result()
+	}
+
+	if !timer.RunUntil(p.Settled) {
+		return &Error{Message: "cannot await a promise that is never settled"}
+	}
+
+	return p.Result()
+}
+
+// Then returns a new promise that is resolved with the result of calling the
+// matching handler once this promise is settled, if there is no handler for
+// the state the promise was settled with, the result is passed through.
+func (p *Promise) Then(onFulfilled, onRejected Callable) *Promise {
+	next := NewPromise()
+
+	p.OnSettled(func() {
+		handler, value := onFulfilled, p.value
+		if p.State == PromiseRejected {
+			handler, value = onRejected, ErrorToHash(p.value.(*Error))
+		}
+
+		if handler == nil {
+			next.settle(p.State, p.value)
+			return
+		}
+
+		if handler.ParametersCount() == 0 {
+			next.Resolve(handler.Call())
+		} else {
+			next.Resolve(handler.Call(value))
+		}
+	})
+
+	return next
+}
+
+// Get returns the method with the given name bound to the promise.
+func (p *Promise) Get(name string) (Object, error) {
+	switch name {
+	case "then":
+		return &Builtin{Fn: func(args ...Object) (Object, error) {
+			if len(args) < 1 {
+				return nil, NewWrongNumberOfArgumentsWantAtLeastError("then", 1, len(args))
+			}
+
+			if len(args) > 2 {
+				return nil, NewWrongNumberOfArgumentsWantAtMostError("then", 2, len(args))
+			}
+
+			onFulfilled, ok := args[0].(Callable)
+			if !ok {
+				return nil, NewInvalidArgumentTypesError("then", []ObjectType{FUNCTION_OBJ, CLOSURE_OBJ}, 0, args)
+			}
+
+			var onRejected Callable
+			if len(args) == 2 {
+				onRejected, ok = args[1].(Callable)
+				if !ok {
+					return nil, NewInvalidArgumentTypesError("then", []ObjectType{FUNCTION_OBJ, CLOSURE_OBJ}, 1, args)
+				}
+			}
+
+			return p.Then(onFulfilled, onRejected), nil
+		}}, nil
+	case "catch":
+		return &Builtin{Fn: func(args ...Object) (Object, error) {
+			if len(args) != 1 {
+				return nil, NewWrongNumberOfArgumentsError("catch", 1, len(args))
+			}
+
+			onRejected, ok := args[0].(Callable)
+			if !ok {
+				return nil, NewInvalidArgumentTypesError("catch", []ObjectType{FUNCTION_OBJ, CLOSURE_OBJ}, 0, args)
+			}
+
+			return p.Then(nil, onRejected), nil
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("promise has no method named %q", name)
+	}
+}
+
+// PromiseOf returns the value if it's already a promise, other values are
+// wrapped in a promise that is settled with the value right away.
+func PromiseOf(value Object) *Promise {
+	if promise, ok := value.(*Promise); ok {
+		return promise
+	}
+
+	promise := NewPromise()
+	promise.Resolve(value)
+
+	return promise
+}
+
+// Await waits for the value to settle if it's a promise or a task, and returns
+// its result, which is an error if it failed, other values are returned as is.
+func Await(value Object) Object {
+	switch value := value.(type) {
+	case *Promise:
+		return value.Await()
+	case *Task:
+		return value.Await()
+
+	default:
+		return value
+	}
+}
+
+// validateArgumentCount checks the number of arguments given to a function,
+// parameters with default values can be left out, and variadic functions
+// accept any number of extra arguments after their parameters.
+func validateArgumentCount(name string, numParameters, numRequired int, variadic bool, numArgs int) error {
+	optional := variadic || numRequired != numParameters
+
+	if numArgs < numRequired {
+		if optional {
+			return NewWrongNumberOfArgumentsWantAtLeastError(name, numRequired, numArgs)
+		}
+
+		return NewWrongNumberOfArgumentsError(name, numParameters, numArgs)
+	}
+
+	if numArgs > numParameters && !variadic {
+		if optional {
+			return NewWrongNumberOfArgumentsWantAtMostError(name, numParameters, numArgs)
+		}
+
+		return NewWrongNumberOfArgumentsError(name, numParameters, numArgs)
+	}
+
+	return nil
+}
+
+// Spread wraps a value that is being spread into an array, hash or the
+// arguments of a function call, until it has been expanded by the VM.
+type Spread struct {
+	Value Object
+}
+
+func (s *Spread) Type() ObjectType { return SPREAD_OBJ }
+func (s *Spread) Inspect() string  { return "..." + s.Value.Inspect() }
+
+type Range struct {
+	Start     int64
+	End       int64
+	Inclusive bool
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	if r.Inclusive {
+		return fmt.Sprintf("%d..%d", r.Start, r.End)
+	}
+
+	return fmt.Sprintf("%d..<%d", r.Start, r.End)
+}
+
+func (r *Range) Len() int64 {
+	end := r.exclusiveEnd()
+	if end < r.Start {
+		return 0
+	}
+
+	return end - r.Start
+}
+
+// exclusiveEnd returns the end of the range as if it was exclusive, so ranges
+// that go over the same values, like 1..3 and 1..<4, end at the same value.
+func (r *Range) exclusiveEnd() int64 {
+	if r.Inclusive {
+		return r.End + 1
+	}
+
+	return r.End
+}
+
+func (r *Range) Elements() []Object {
+	elements := make([]Object, r.Len())
+	for i := range elements {
+		elements[i] = &Integer{Value: r.Start + int64(i)}
+	}
+
+	return elements
+}
+
+type Struct struct {
+	Name    string
+	Fields  []string
+	Methods map[string]Object
+}
+
+func (s *Struct) Type() ObjectType { return STRUCT_OBJ }
+func (s *Struct) Inspect() string {
+	return fmt.Sprintf("struct %s { %s }", s.Name, strings.Join(s.Fields, ", "))
+}
+
+// HasField returns true if the struct declares a field with the given name.
+func (s *Struct) HasField(name string) bool {
+	return slices.Contains(s.Fields, name)
+}
+
+// NewInstance creates a new instance of the struct, assigning the given values
+// to the fields in the order they were declared, any fields that are left
+// without a value are set to null.
+func (s *Struct) NewInstance(values []Object) (*StructInstance, error) {
+	if len(values) > len(s.Fields) {
+		return nil, NewWrongNumberOfArgumentsWantAtMostError(s.Name, len(s.Fields), len(values))
+	}
+
+	instance := &StructInstance{Struct: s, Fields: make(map[string]Object, len(s.Fields))}
+	for i, field := range s.Fields {
+		var value Object = NULL
+		if i < len(values) {
+			value = values[i]
+		}
+
+		instance.Fields[field] = value
+	}
+
+	return instance, nil
+}
+
+type StructInstance struct {
+	Struct *Struct
+	Fields map[string]Object
+}
+
+func (si *StructInstance) Type() ObjectType { return STRUCT_INSTANCE_OBJ }
+func (si *StructInstance) Inspect() string {
+	fields := []string{}
+	for _, field := range si.Struct.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", field, si.Fields[field].Inspect()))
+	}
+
+	return fmt.Sprintf("%s{%s}", si.Struct.Name, strings.Join(fields, ", "))
+}
+
+// Get returns the value of the given field, or the method with the given name
+// bound to the instance if no field exists with that name.
+func (si *StructInstance) Get(name string) (Object, error) {
+	if value, ok := si.Fields[name]; ok {
+		return value, nil
+	}
+
+	if method, ok := si.Struct.Methods[name]; ok {
+		return &BoundMethod{Receiver: si, Method: method}, nil
+	}
+
+	return nil, fmt.Errorf("struct %s has no field or method named %q", si.Struct.Name, name)
+}
+
+// Set assigns the value to the given field, fields can't be added to a struct
+// instance so only the fields the struct was declared with can be set.
+func (si *StructInstance) Set(name string, value Object) error {
+	if !si.Struct.HasField(name) {
+		return fmt.Errorf("struct %s has no field named %q", si.Struct.Name, name)
+	}
+
+	si.Fields[name] = value
+
+	return nil
+}
+
+type BoundMethod struct {
+	Receiver *StructInstance
+	Method   Object
+}
+
+func (bm *BoundMethod) Type() ObjectType { return BOUND_METHOD_OBJ }
+func (bm *BoundMethod) Inspect() string {
+	return fmt.Sprintf("BoundMethod[%s|%p]", bm.Receiver.Struct.Name, bm)
+}
+
+// ValidateArguments returns an error if the method can't be called with the
+// given number of arguments, the receiver is passed to the method as its
+// first parameter so it isn't included in the counts.
+func (bm *BoundMethod) ValidateArguments(numArgs int) error {
+	switch method := bm.Method.(type) {
+	case *Function:
+		if numArgs+1 > len(method.Parameters) {
+			return nil
+		}
+
+		return validateArgumentCount(
+			method.Name.Value,
+			len(method.Parameters)-1,
+			ast.RequiredParameters(method.Parameters)-1,
+			method.Rest != nil,
+			numArgs,
+		)
+	case *Closure:
+		return validateArgumentCount(
+			method.Fn.Name,
+			method.Fn.NumParameters-1,
+			method.Fn.NumRequiredParameters-1,
+			method.Fn.Variadic,
+			numArgs,
+		)
+
+	default:
+		return nil
+	}
+}
+
+type Enum struct {
+	Name     string
+	Variants []*EnumVariant
+}
+
+func (e *Enum) Type() ObjectType { return ENUM_OBJ }
+func (e *Enum) Inspect() string {
+	variants := []string{}
+	for _, variant := range e.Variants {
+		if len(variant.Fields) == 0 {
+			variants = append(variants, variant.Name)
+			continue
+		}
+
+		variants = append(variants, fmt.Sprintf("%s(%s)", variant.Name, strings.Join(variant.Fields, ", ")))
+	}
+
+	return fmt.Sprintf("enum %s { %s }", e.Name, strings.Join(variants, ", "))
+}
+
+// Variant returns the variant with the given name.
+func (e *Enum) Variant(name string) (*EnumVariant, error) {
+	for _, variant := range e.Variants {
+		if variant.Name == name {
+			return variant, nil
+		}
+	}
+
+	return nil, fmt.Errorf("enum %s has no variant named %q", e.Name, name)
+}
+
+// CheckCoverage returns an error naming the variants that aren't covered by
+// a match on the enum, in the order they're declared in. Matches that use
+// variants the enum doesn't have are left for the match itself to report.
+func (e *Enum) CheckCoverage(covered map[string]bool) error {
+	for name := range covered {
+		if _, err := e.Variant(name); err != nil {
+			return nil
+		}
+	}
+
+	missing := []string{}
+	for _, variant := range e.Variants {
+		if !covered[variant.Name] {
+			missing = append(missing, variant.Name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("non-exhaustive match on enum %s, missing variants: %s", e.Name, strings.Join(missing, ", "))
+}
+
+// EnumVariant is both the definition of a variant on its enum, and the value
+// created from it, variants without any fields are used as values directly,
+// while variants with fields are called to create a value holding them.
+type EnumVariant struct {
+	Enum   *Enum
+	Name   string
+	Fields []string
+	Values []Object
+}
+
+func (ev *EnumVariant) Type() ObjectType { return ENUM_VARIANT_OBJ }
+func (ev *EnumVariant) Inspect() string {
+	name := fmt.Sprintf("%s.%s", ev.Enum.Name, ev.Name)
+	if len(ev.Values) == 0 {
+		return name
+	}
+
+	values := []string{}
+	for _, value := range ev.Values {
+		values = append(values, value.Inspect())
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(values, ", "))
+}
+
+// New creates a value of the variant holding the given values, a value must
+// be given for every field the variant was declared with.
+func (ev *EnumVariant) New(values []Object) (*EnumVariant, error) {
+	if len(ev.Fields) == 0 {
+		return nil, fmt.Errorf("variant %s.%s doesn't take any values", ev.Enum.Name, ev.Name)
+	}
+
+	if len(values) != len(ev.Fields) {
+		return nil, NewWrongNumberOfArgumentsError(ev.Enum.Name+"."+ev.Name, len(ev.Fields), len(values))
+	}
+
+	return &EnumVariant{Enum: ev.Enum, Name: ev.Name, Fields: ev.Fields, Values: slices.Clone(values)}, nil
+}
+
+// Get returns the value of the given field.
+func (ev *EnumVariant) Get(name string) (Object, error) {
+	for i, field := range ev.Fields {
+		if field == name && i < len(ev.Values) {
+			return ev.Values[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("variant %s.%s has no field named %q", ev.Enum.Name, ev.Name, name)
+}
+
 type Function struct {
 	Name       *ast.Identifier
-	Parameters []*ast.Identifier
+	Parameters []ast.Expression
+	Rest       *ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
+	Generator  bool
+	Async      bool
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -276,6 +1148,10 @@ func (f *Function) Inspect() string {
 		params = append(params, p.String())
 	}
 
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
+	}
+
 	if f.Name != nil {
 		out.WriteString(f.Name.String())
 	}
@@ -289,6 +1165,23 @@ func (f *Function) Inspect() string {
 	return out.String()
 }
 
+// ValidateArguments returns an error if the function can't be called with the
+// given number of arguments.
+func (f *Function) ValidateArguments(numArgs int) error {
+	name := "<anonymous>"
+	if f.Name != nil {
+		name = f.Name.Value
+	}
+
+	return validateArgumentCount(
+		name,
+		len(f.Parameters),
+		ast.RequiredParameters(f.Parameters),
+		f.Rest != nil,
+		numArgs,
+	)
+}
+
 type BuiltinFunction func(args ...Object) (Object, error)
 type BuiltinDefinition struct {
 	Name    string
@@ -329,10 +1222,14 @@ func (b *ASTAwareBuiltin) Type() ObjectType { return BUILTIN_OBJ }
 func (b *ASTAwareBuiltin) Inspect() string  { return "builtin function" }
 
 type CompiledFunction struct {
-	Name               string
-	OpcodeInstructions code.Instructions
-	NumLocals          int
-	NumParameters      int
+	Name                  string
+	OpcodeInstructions    code.Instructions
+	NumLocals             int
+	NumParameters         int
+	NumRequiredParameters int
+	Variadic              bool
+	Generator             bool
+	Async                 bool
 }
 
 func (cf *CompiledFunction) Type() ObjectType { return COMPILED_FUNCTION_OBJ }
@@ -345,15 +1242,60 @@ func (cf *CompiledFunction) Inspect() string {
 }
 func (cf *CompiledFunction) Instructions() code.Instructions { return cf.OpcodeInstructions }
 
+// ValidateArguments returns an error if the function can't be called with the
+// given number of arguments.
+func (cf *CompiledFunction) ValidateArguments(numArgs int) error {
+	name := "<anonymous>"
+	if cf.Name != "" {
+		name = cf.Name
+	}
+
+	return validateArgumentCount(name, cf.NumParameters, cf.NumRequiredParameters, cf.Variadic, numArgs)
+}
+
 type Closure struct {
 	Fn   *CompiledFunction
-	Free []Object
+	Free []*Upvalue
 }
 
 func (c *Closure) Type() ObjectType                { return CLOSURE_OBJ }
 func (c *Closure) Inspect() string                 { return fmt.Sprintf("Closure[%p]", c) }
 func (c *Closure) Instructions() code.Instructions { return c.Fn.OpcodeInstructions }
 
+// Upvalue is a variable captured by a closure. While the variable is still
+// open it points to the stack slot the variable lives in, once the frame
+// the variable belongs to returns, the value is moved into the upvalue so
+// every closure that shares it keeps seeing the same variable.
+type Upvalue struct {
+	Slot     int
+	location *Object
+	closed   Object
+}
+
+func NewOpenUpvalue(slot int, location *Object) *Upvalue {
+	return &Upvalue{Slot: slot, location: location}
+}
+
+func NewClosedUpvalue(value Object) *Upvalue {
+	upvalue := &Upvalue{Slot: -1, closed: value}
+	upvalue.location = &upvalue.closed
+
+	return upvalue
+}
+
+func (u *Upvalue) Type() ObjectType { return UPVALUE_OBJ }
+func (u *Upvalue) Inspect() string  { return fmt.Sprintf("Upvalue[%p]", u) }
+
+func (u *Upvalue) Get() Object      { return *u.location }
+func (u *Upvalue) Set(value Object) { *u.location = value }
+
+// Close moves the value out of the stack slot and into the upvalue itself.
+func (u *Upvalue) Close() {
+	u.closed = *u.location
+	u.location = &u.closed
+	u.Slot = -1
+}
+
 type ImportedClosure struct {
 	Closure            *Closure
 	ImportContextIndex int
@@ -367,6 +1309,7 @@ type CompiledZenFileImport struct {
 	Name               string
 	OpcodeInstructions code.Instructions
 	Constants          []Object
+	NumLocals          int
 }
 
 func (cfi *CompiledZenFileImport) Type() ObjectType { return COMPILED_ZEN_FILE_IMPORT_OBJ }
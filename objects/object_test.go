@@ -1,7 +1,14 @@
 package objects
 
 import (
+	"bytes"
+	"math/big"
+	"os"
 	"testing"
+
+	"github.com/senither/zen-lang/objects/decimal"
+	"github.com/senither/zen-lang/objects/scheduler"
+	"github.com/senither/zen-lang/objects/timer"
 )
 
 func TestStringHashKey(t *testing.T) {
@@ -37,6 +44,34 @@ func TestIntegerHashKey(t *testing.T) {
 	}
 }
 
+func TestBigIntHashKey(t *testing.T) {
+	big1 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}
+	big2 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}
+	big3 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 65)}
+
+	if big1.HashKey() != big2.HashKey() {
+		t.Errorf("big integers with same value have different hash keys")
+	}
+
+	if big1.HashKey() == big3.HashKey() {
+		t.Errorf("big integers with different values have same hash keys")
+	}
+}
+
+func TestDecimalHashKey(t *testing.T) {
+	decimal1 := &Decimal{Value: decimal.MustParse("19.99")}
+	decimal2 := &Decimal{Value: decimal.MustParse("19.990")}
+	decimal3 := &Decimal{Value: decimal.MustParse("19.98")}
+
+	if decimal1.HashKey() != decimal2.HashKey() {
+		t.Errorf("decimals with same value have different hash keys")
+	}
+
+	if decimal1.HashKey() == decimal3.HashKey() {
+		t.Errorf("decimals with different values have same hash keys")
+	}
+}
+
 func TestFloatHashKey(t *testing.T) {
 	float1 := &Float{Value: 3.14159}
 	float2 := &Float{Value: 3.14159}
@@ -64,3 +99,360 @@ func TestBooleanHashKey(t *testing.T) {
 		t.Errorf("booleans with different values have same hash keys")
 	}
 }
+
+func TestStructInstance(t *testing.T) {
+	user := &Struct{Name: "User", Fields: []string{"name", "age"}, Methods: map[string]Object{}}
+	user.Methods["greet"] = &Builtin{}
+
+	instance, err := user.NewInstance([]Object{&String{Value: "Alice"}})
+	if err != nil {
+		t.Fatalf("unexpected error creating instance: %s", err)
+	}
+
+	if instance.Inspect() != "User{name: Alice, age: null}" {
+		t.Errorf("instance.Inspect() wrong. got %q", instance.Inspect())
+	}
+
+	err = instance.Set("age", &Integer{Value: 30})
+	if err != nil {
+		t.Fatalf("unexpected error setting field: %s", err)
+	}
+
+	age, err := instance.Get("age")
+	if err != nil {
+		t.Fatalf("unexpected error getting field: %s", err)
+	}
+
+	AssertExpectedObject(t, 30, age)
+
+	method, err := instance.Get("greet")
+	if err != nil {
+		t.Fatalf("unexpected error getting method: %s", err)
+	}
+
+	if bound, ok := method.(*BoundMethod); !ok || bound.Receiver != instance {
+		t.Errorf("method is not bound to the instance. got %T", method)
+	}
+
+	if _, err := instance.Get("email"); err == nil {
+		t.Errorf("expected error getting unknown field, got none")
+	}
+
+	if err := instance.Set("email", NULL); err == nil {
+		t.Errorf("expected error setting unknown field, got none")
+	}
+
+	if _, err := user.NewInstance([]Object{NULL, NULL, NULL}); err == nil {
+		t.Errorf("expected error creating instance with too many values, got none")
+	}
+}
+
+func TestEnumVariant(t *testing.T) {
+	status := &Enum{Name: "Status"}
+	status.Variants = []*EnumVariant{
+		{Enum: status, Name: "Active", Fields: []string{}},
+		{Enum: status, Name: "Banned", Fields: []string{"reason"}},
+	}
+
+	if status.Inspect() != "enum Status { Active, Banned(reason) }" {
+		t.Errorf("status.Inspect() wrong. got %q", status.Inspect())
+	}
+
+	active, err := status.Variant("Active")
+	if err != nil {
+		t.Fatalf("unexpected error getting variant: %s", err)
+	}
+
+	if active.Inspect() != "Status.Active" {
+		t.Errorf("active.Inspect() wrong. got %q", active.Inspect())
+	}
+
+	banned, err := status.Variant("Banned")
+	if err != nil {
+		t.Fatalf("unexpected error getting variant: %s", err)
+	}
+
+	value, err := banned.New([]Object{&String{Value: "spam"}})
+	if err != nil {
+		t.Fatalf("unexpected error creating variant value: %s", err)
+	}
+
+	if value.Inspect() != "Status.Banned(spam)" {
+		t.Errorf("value.Inspect() wrong. got %q", value.Inspect())
+	}
+
+	reason, err := value.Get("reason")
+	if err != nil {
+		t.Fatalf("unexpected error getting field: %s", err)
+	}
+
+	AssertExpectedObject(t, "spam", reason)
+
+	if Equals(active, banned) != FALSE {
+		t.Errorf("expected different variants to not be equal")
+	}
+
+	if Equals(value, &EnumVariant{Enum: status, Name: "Banned", Values: []Object{&String{Value: "spam"}}}) != TRUE {
+		t.Errorf("expected variants with equal values to be equal")
+	}
+
+	if _, err := status.Variant("Deleted"); err == nil {
+		t.Errorf("expected error getting unknown variant, got none")
+	}
+
+	if _, err := active.New([]Object{NULL}); err == nil {
+		t.Errorf("expected error creating value of variant without fields, got none")
+	}
+
+	if _, err := banned.New([]Object{}); err == nil {
+		t.Errorf("expected error creating variant value with too few values, got none")
+	}
+
+	if err := status.CheckCoverage(map[string]bool{"Active": true, "Banned": true}); err != nil {
+		t.Errorf("unexpected error checking covered variants: %s", err)
+	}
+
+	err = status.CheckCoverage(map[string]bool{"Active": false})
+	if err == nil || err.Error() != "non-exhaustive match on enum Status, missing variants: Active, Banned" {
+		t.Errorf("wrong error checking uncovered variants. got %v", err)
+	}
+
+	if err := status.CheckCoverage(map[string]bool{"Deleted": true}); err != nil {
+		t.Errorf("expected unknown variants to be left for the match to report, got %s", err)
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	values := []Object{&Integer{Value: 1}, &Integer{Value: 2}, &Integer{Value: 3}}
+	generator := NewGenerator("numbers", func() (Object, bool, error) {
+		if len(values) == 0 {
+			return NULL, false, nil
+		}
+
+		value := values[0]
+		values = values[1:]
+
+		return value, true, nil
+	})
+
+	value, ok, err := generator.Next()
+	if err != nil || !ok {
+		t.Fatalf("unexpected end of generator: %v", err)
+	}
+
+	AssertExpectedObject(t, 1, value)
+
+	result, err := generator.NextResult()
+	if err != nil {
+		t.Fatalf("unexpected error getting next result: %s", err)
+	}
+
+	if result.Inspect() != "{value: 2, done: false}" {
+		t.Errorf("result.Inspect() wrong. got %q", result.Inspect())
+	}
+
+	iterator, err := NewIterator(generator)
+	if err != nil {
+		t.Fatalf("unexpected error creating iterator: %s", err)
+	}
+
+	_, value, ok = iterator.Next()
+	if !ok {
+		t.Fatalf("expected iterator to have a value, got none")
+	}
+
+	AssertExpectedObject(t, 3, value)
+
+	if _, _, ok := iterator.Next(); ok {
+		t.Errorf("expected iterator to be exhausted")
+	}
+
+	if _, ok, _ := generator.Next(); ok {
+		t.Errorf("expected generator to be done")
+	}
+
+	if _, err := generator.Get("reset"); err == nil {
+		t.Errorf("expected error getting unknown method, got none")
+	}
+}
+
+func TestUpvalue(t *testing.T) {
+	slot := Object(&Integer{Value: 1})
+	upvalue := NewOpenUpvalue(0, &slot)
+
+	upvalue.Set(&Integer{Value: 2})
+	AssertExpectedObject(t, 2, slot)
+
+	upvalue.Close()
+	slot = &Integer{Value: 3}
+
+	AssertExpectedObject(t, 2, upvalue.Get())
+
+	if upvalue.Slot != -1 {
+		t.Errorf("expected closed upvalue to have no slot, got %d", upvalue.Slot)
+	}
+
+	closed := NewClosedUpvalue(&Integer{Value: 5})
+	closed.Set(&Integer{Value: 6})
+
+	AssertExpectedObject(t, 6, closed.Get())
+}
+
+func TestTask(t *testing.T) {
+	task := NewTask(func() Object {
+		return &Integer{Value: 5}
+	})
+
+	AssertExpectedObject(t, 5, task.Await())
+
+	if !task.Done() {
+		t.Errorf("expected task to be done after awaiting it")
+	}
+
+	if task.Type() != TASK_OBJ {
+		t.Errorf("task.Type() wrong. got %q", task.Type())
+	}
+
+	if _, err := task.Get("cancel"); err == nil {
+		t.Errorf("expected error getting unknown method, got none")
+	}
+}
+
+func TestPromise(t *testing.T) {
+	scheduler.Run(func() {
+		testPromise(t)
+	})
+}
+
+func testPromise(t *testing.T) {
+	promise := NewAsyncPromise(func() Object {
+		return &Integer{Value: 5}
+	})
+
+	if !promise.Settled() {
+		t.Errorf("expected promise to be settled when the function never awaits")
+	}
+
+	AssertExpectedObject(t, 5, promise.Await())
+
+	if promise.Type() != PROMISE_OBJ {
+		t.Errorf("promise.Type() wrong. got %q", promise.Type())
+	}
+
+	rejected := NewPromise()
+	rejected.Reject(&Error{Message: "failed"})
+
+	AssertExpectedObject(t, &Error{Message: "failed"}, rejected.Await())
+
+	adopted := NewPromise()
+	adopted.Resolve(promise)
+
+	AssertExpectedObject(t, 5, adopted.Await())
+
+	if _, err := promise.Get("finally"); err == nil {
+		t.Errorf("expected error getting unknown method, got none")
+	}
+}
+
+func TestAwaitPendingPromise(t *testing.T) {
+	scheduler.Run(func() {
+		promise := NewPromise()
+
+		AssertExpectedObject(t, &Error{Message: "cannot await a promise that is never settled"}, promise.Await())
+	})
+}
+
+func TestUnhandledPromiseRejection(t *testing.T) {
+	originalStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	scheduler.Run(func() {
+		unhandled := NewPromise()
+		unhandled.Reject(&Error{Message: "unhandled failure"})
+
+		handled := NewPromise()
+		handled.Reject(&Error{Message: "handled failure"})
+		handled.OnSettled(func() {})
+	})
+
+	timer.RunEventLoop()
+
+	w.Close()
+	os.Stdout = originalStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	expected := (&Error{Message: "unhandled failure"}).Inspect() + "\n"
+	if buf.String() != expected {
+		t.Errorf("wrong output for unhandled rejections.\nwant:\n\t%q\ngot:\n\t%q", expected, buf.String())
+	}
+}
+
+func TestChannel(t *testing.T) {
+	channel := NewChannel(2)
+
+	if err := channel.Send(&Integer{Value: 1}); err != nil {
+		t.Fatalf("unexpected error sending value: %s", err)
+	}
+
+	if err := channel.Send(&Integer{Value: 2}); err != nil {
+		t.Fatalf("unexpected error sending value: %s", err)
+	}
+
+	if err := channel.Close(); err != nil {
+		t.Fatalf("unexpected error closing channel: %s", err)
+	}
+
+	if err := channel.Close(); err == nil {
+		t.Errorf("expected error closing a closed channel, got none")
+	}
+
+	if err := channel.Send(&Integer{Value: 3}); err == nil {
+		t.Errorf("expected error sending on a closed channel, got none")
+	}
+
+	value, ok := channel.Receive()
+	if !ok {
+		t.Fatalf("expected channel to have a value, got none")
+	}
+
+	AssertExpectedObject(t, 1, value)
+
+	iterator, err := NewIterator(channel)
+	if err != nil {
+		t.Fatalf("unexpected error creating iterator: %s", err)
+	}
+
+	_, value, ok = iterator.Next()
+	if !ok {
+		t.Fatalf("expected iterator to have a value, got none")
+	}
+
+	AssertExpectedObject(t, 2, value)
+
+	if _, _, ok := iterator.Next(); ok {
+		t.Errorf("expected iterator to be exhausted")
+	}
+}
+
+func TestSelectChannel(t *testing.T) {
+	first := NewChannel(1)
+	second := NewChannel(1)
+
+	if err := second.Send(&String{Value: "second"}); err != nil {
+		t.Fatalf("unexpected error sending value: %s", err)
+	}
+
+	index, value, ok := SelectChannel([]*Channel{first, second})
+	if !ok {
+		t.Fatalf("expected select to receive a value, got none")
+	}
+
+	if index != 1 {
+		t.Errorf("wrong channel selected. want 1, got %d", index)
+	}
+
+	AssertExpectedObject(t, "second", value)
+}
@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// The number of ticks a goroutine can run for before it hands the
+// interpreter lock over to any other goroutines waiting for it.
+const TICKS_PER_SLICE = 1000
+
+// Only a single goroutine is allowed to run Zen code at any given time,
+// tasks and timers must hold the interpreter lock while running, and
+// release it while they're blocked waiting on something else.
+var lock sync.Mutex
+var held atomic.Bool
+var ticks int
+
+// The stdout the interpreter was started with, builtins capturing their
+// output replace os.Stdout while they run, so it's swapped back while
+// the lock is released to stop other goroutines writing to it.
+var stdout *os.File
+
+// Lock acquires the interpreter lock, blocking until it's available.
+func Lock() {
+	lock.Lock()
+	held.Store(true)
+
+	stdout = os.Stdout
+}
+
+// Unlock releases the interpreter lock.
+func Unlock() {
+	held.Store(false)
+	lock.Unlock()
+}
+
+// Go runs the function in a new goroutine once it holds the interpreter lock.
+func Go(fn func()) {
+	go Run(fn)
+}
+
+// Run runs the function while holding the interpreter lock.
+func Run(fn func()) {
+	Lock()
+
+	previous := current
+	current = nil
+
+	defer func() {
+		current = previous
+		Unlock()
+	}()
+
+	fn()
+}
+
+// Block releases the interpreter lock while running the function, allowing
+// other goroutines to run while the current one is waiting on something.
+func Block(fn func()) {
+	if !held.Load() {
+		fn()
+		return
+	}
+
+	out := os.Stdout
+	os.Stdout = stdout
+	co := current
+
+	Unlock()
+	fn()
+	Lock()
+
+	current = co
+	os.Stdout = out
+}
+
+// Tick is called by the engines as they run loops, once the goroutine has
+// run for long enough the lock is handed over to other waiting goroutines.
+func Tick() {
+	ticks++
+	if ticks < TICKS_PER_SLICE {
+		return
+	}
+
+	ticks = 0
+	Block(runtime.Gosched)
+}
+
+// A coroutine runs a function on a goroutine of its own, but only while the
+// goroutine that resumed it is waiting for it to suspend again, so it never
+// runs alongside the code resuming it.
+type Coroutine struct {
+	resume  chan struct{}
+	suspend chan struct{}
+	done    bool
+}
+
+// The coroutine that is currently running, or nil if none are running.
+var current *Coroutine
+
+// Current returns the coroutine that is currently running, or nil if the
+// code isn't running within a coroutine.
+func Current() *Coroutine {
+	return current
+}
+
+// StartCoroutine runs the function as a coroutine, until the function
+// either suspends the coroutine for the first time or returns.
+func StartCoroutine(fn func()) *Coroutine {
+	co := &Coroutine{
+		resume:  make(chan struct{}),
+		suspend: make(chan struct{}),
+	}
+
+	go func() {
+		<-co.resume
+
+		fn()
+
+		co.done = true
+		co.suspend <- struct{}{}
+	}()
+
+	co.Resume()
+
+	return co
+}
+
+// Resume runs the coroutine until it's suspended again, or until it's done.
+func (co *Coroutine) Resume() {
+	if co.done {
+		return
+	}
+
+	// Builtins capturing their output replace os.Stdout while they run, and
+	// coroutines can be suspended within them, so both sides of the switch
+	// keep the stdout they were using when they hand over control.
+	previous := current
+	out := os.Stdout
+	current = co
+
+	co.resume <- struct{}{}
+	<-co.suspend
+
+	current = previous
+	os.Stdout = out
+}
+
+// Done returns true once the function run by the coroutine has returned.
+func (co *Coroutine) Done() bool {
+	return co.done
+}
+
+// Suspend pauses the current coroutine until it's resumed again, it must
+// only be called from within a coroutine.
+func Suspend() {
+	co := current
+	if co == nil {
+		panic("scheduler: suspend called outside of a coroutine")
+	}
+
+	out := os.Stdout
+
+	co.suspend <- struct{}{}
+	<-co.resume
+
+	os.Stdout = out
+}
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var wg sync.WaitGroup
+	counter := 0
+
+	for range 100 {
+		wg.Add(1)
+		Go(func() {
+			defer wg.Done()
+			counter++
+		})
+	}
+
+	wg.Wait()
+
+	if counter != 100 {
+		t.Errorf("expected counter to be 100, got %d", counter)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	Lock()
+	defer Unlock()
+
+	done := make(chan struct{})
+	Go(func() {
+		close(done)
+	})
+
+	// The spawned goroutine can only run while the lock is released.
+	Block(func() {
+		<-done
+	})
+
+	if !held.Load() {
+		t.Errorf("expected the lock to be held again after blocking")
+	}
+}
+
+func TestBlockWithoutLock(t *testing.T) {
+	ran := false
+	Block(func() {
+		ran = true
+	})
+
+	if !ran {
+		t.Errorf("expected the function to run without holding the lock")
+	}
+}
+
+func TestTick(t *testing.T) {
+	Lock()
+	defer Unlock()
+
+	done := make(chan struct{})
+	Go(func() {
+		close(done)
+	})
+
+	for range TICKS_PER_SLICE * 10 {
+		Tick()
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+
+	<-done
+}
+
+func TestCoroutine(t *testing.T) {
+	order := []string{}
+
+	co := StartCoroutine(func() {
+		order = append(order, "start")
+		Suspend()
+		order = append(order, "resumed")
+	})
+
+	if co.Done() {
+		t.Errorf("expected coroutine to be suspended, not done")
+	}
+
+	order = append(order, "outside")
+	co.Resume()
+
+	if !co.Done() {
+		t.Errorf("expected coroutine to be done after resuming it")
+	}
+
+	if fmt.Sprint(order) != "[start outside resumed]" {
+		t.Errorf("expected coroutine to hand off control in order, got %v", order)
+	}
+
+	if Current() != nil {
+		t.Errorf("expected no current coroutine after it finished")
+	}
+}
+
+func TestCoroutineKeepsStdout(t *testing.T) {
+	original := os.Stdout
+	defer func() { os.Stdout = original }()
+
+	outside, _ := os.CreateTemp(t.TempDir(), "outside")
+	inside, _ := os.CreateTemp(t.TempDir(), "inside")
+
+	os.Stdout = outside
+
+	co := StartCoroutine(func() {
+		os.Stdout = inside
+		Suspend()
+
+		if os.Stdout != inside {
+			t.Errorf("expected coroutine to get its own stdout back when resumed")
+		}
+	})
+
+	if os.Stdout != outside {
+		t.Errorf("expected stdout to be restored once the coroutine was suspended")
+	}
+
+	co.Resume()
+
+	if os.Stdout != outside {
+		t.Errorf("expected stdout to be restored once the coroutine was done")
+	}
+}
+
+func TestSuspendOutsideCoroutine(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected suspending outside of a coroutine to panic")
+		}
+	}()
+
+	Suspend()
+}
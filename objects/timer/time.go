@@ -6,25 +6,26 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/senither/zen-lang/objects/scheduler"
 )
 
-type FakeTime struct {
+// A timer queued on the event loop, delayed timers run their callback
+// once, while scheduled timers have an interval and keep running their
+// callback until they're stopped.
+type Timer struct {
 	callback func()
-	lastCall int64
-	delay    int64
+	due      int64
 	interval int64
-	isTicker bool
 	stopped  bool
-	id       string
 }
 
 var fakeTime *int64 = nil
 var localTimezone *time.Location = time.Local
 
-var timers = map[string]*time.Timer{}
-var tickers = map[string]*time.Ticker{}
-
-var fakeTimers = []*FakeTime{}
+// The timers that are currently waiting to run, in the order they were
+// started, timers that are due at the same time run in this order.
+var timers = []*Timer{}
 
 // Maps formatting tokens to Go time layout equivalents, right now
 // it supports a limited set of tokens from PHP's date function
@@ -63,43 +64,13 @@ func TimeTravel(duration int64) error {
 		return errors.New("cannot time travel outside of testing environments, time must be frozen")
 	}
 
-	for range duration {
-		*fakeTime += 1
-
-		for _, ft := range fakeTimers {
-			if ft.stopped {
-				continue
-			}
-
-			if !ft.isTicker {
-				if (*fakeTime - ft.lastCall) >= ft.delay {
-					ft.callback()
-					ft.stopped = true
-				}
-			} else if ft.interval > 0 {
-				elapsed := *fakeTime - ft.lastCall
-				if elapsed > 0 && (elapsed%ft.interval) == 0 {
-					ft.callback()
-				}
-			}
-		}
-	}
+	runTimersUntil(*fakeTime + duration)
 
 	return nil
 }
 
 func ClearTimers() {
-	for _, timer := range timers {
-		timer.Stop()
-	}
-
-	for _, ticker := range tickers {
-		ticker.Stop()
-	}
-
-	timers = map[string]*time.Timer{}
-	tickers = map[string]*time.Ticker{}
-	fakeTimers = []*FakeTime{}
+	timers = []*Timer{}
 }
 
 func Now() int64 {
@@ -110,13 +81,128 @@ func Now() int64 {
 	return time.Now().UnixMilli()
 }
 
+// Sleep blocks for the given amount of milliseconds, any timers that
+// become due while sleeping are run in order as their time is reached.
+// Within a coroutine, the coroutine is suspended instead and resumed by
+// the event loop, so other work can run while it's sleeping.
 func Sleep(milliseconds int64) {
+	if co := scheduler.Current(); co != nil {
+		StartDelayedTimer(co.Resume, milliseconds)
+		scheduler.Suspend()
+		return
+	}
+
 	if fakeTime != nil {
 		TimeTravel(milliseconds)
 		return
 	}
 
-	time.Sleep(time.Duration(milliseconds) * time.Millisecond)
+	runTimersUntil(Now() + milliseconds)
+}
+
+// RunEventLoop runs the queued timers in order as they become due, and
+// only returns once there are no timers waiting to run anymore.
+func RunEventLoop() {
+	scheduler.Run(func() {
+		RunUntil(func() bool { return false })
+	})
+}
+
+// RunDueTimers runs the queued timers that are already due, without waiting
+// for any of the timers that are due in the future, used by the REPLs to run
+// timers between inputs.
+func RunDueTimers() {
+	scheduler.Run(func() {
+		runTimersUntil(Now())
+	})
+}
+
+// RunUntil runs the queued timers in order as they become due, until the
+// condition is met, returns false if there are no timers left to run
+// before the condition has been met.
+func RunUntil(condition func() bool) bool {
+	for !condition() {
+		timer := nextTimer()
+		if timer == nil {
+			return false
+		}
+
+		waitUntil(timer.due)
+		timer.run()
+	}
+
+	return true
+}
+
+// HasPendingTimers returns true if there are any timers waiting to run.
+func HasPendingTimers() bool {
+	return nextTimer() != nil
+}
+
+func runTimersUntil(deadline int64) {
+	for {
+		timer := nextTimer()
+		if timer == nil || timer.due > deadline {
+			break
+		}
+
+		waitUntil(timer.due)
+		timer.run()
+	}
+
+	waitUntil(deadline)
+}
+
+func waitUntil(deadline int64) {
+	if fakeTime != nil {
+		if deadline > *fakeTime {
+			*fakeTime = deadline
+		}
+
+		return
+	}
+
+	if remaining := deadline - Now(); remaining > 0 {
+		scheduler.Block(func() {
+			time.Sleep(time.Duration(remaining) * time.Millisecond)
+		})
+	}
+}
+
+func nextTimer() *Timer {
+	var next *Timer
+
+	pending := timers[:0]
+	for _, timer := range timers {
+		if timer.stopped {
+			continue
+		}
+
+		pending = append(pending, timer)
+		if next == nil || timer.due < next.due {
+			next = timer
+		}
+	}
+
+	timers = pending
+
+	return next
+}
+
+func (t *Timer) run() {
+	// The timer is rescheduled before running the callback, so timers
+	// started or stopped by the callback itself are handled correctly.
+	if t.interval > 0 {
+		t.due += t.interval
+	} else {
+		t.stopped = true
+	}
+
+	t.callback()
+}
+
+func (t *Timer) ID() string {
+	return fmt.Sprintf("%p", t)
 }
 
 func Parse(dateString, layout string) (int64, error) {
@@ -177,104 +263,56 @@ func SetTimezone(timezone string) error {
 }
 
 func ResetTimezone() {
-	time.Local = localTimezone
+	// Go reads the local timezone in the background for running timers, so
+	// we avoid writing to it when it's already set to what it should be.
+	if time.Local != localTimezone {
+		time.Local = localTimezone
+	}
 }
 
-func StartDelayedTimer(callback func(), delay int64) *time.Timer {
-	if fakeTime != nil {
-		timer := time.AfterFunc(time.Millisecond*time.Duration(delay), func() {})
-
-		fakeTimers = append(fakeTimers, &FakeTime{
-			callback: callback,
-			lastCall: *fakeTime,
-			delay:    delay,
-			isTicker: false,
-			id:       fmt.Sprintf("%p", timer),
-		})
-
-		return timer
+func StartDelayedTimer(callback func(), delay int64) *Timer {
+	timer := &Timer{
+		callback: callback,
+		due:      Now() + delay,
 	}
 
-	timer := time.AfterFunc(time.Millisecond*time.Duration(delay), func() {
-		callback()
-	})
-
-	timers[fmt.Sprintf("%p", timer)] = timer
+	timers = append(timers, timer)
 
 	return timer
 }
 
-func StopDelayedTimer(timer *time.Timer) bool {
-	if timer == nil {
-		return false
-	}
-
-	id := fmt.Sprintf("%p", timer)
-	if fakeTime != nil {
-		for _, ft := range fakeTimers {
-			if ft.id == id && !ft.isTicker {
-				ft.stopped = true
-				break
-			}
-		}
+func StartScheduledTimer(callback func(), interval int64) *Timer {
+	timer := &Timer{
+		callback: callback,
+		due:      Now() + interval,
+		interval: interval,
 	}
 
-	stopped := timer.Stop()
-	delete(timers, id)
+	timers = append(timers, timer)
 
-	return stopped
+	return timer
 }
 
-func StartScheduledTimer(callback func(), interval int64) *time.Ticker {
-	ticker := time.NewTicker(time.Millisecond * time.Duration(interval))
-
-	if fakeTime != nil {
-		fakeTimers = append(fakeTimers, &FakeTime{
-			callback: callback,
-			lastCall: *fakeTime,
-			interval: interval,
-			isTicker: true,
-			id:       fmt.Sprintf("%p", ticker),
-		})
-
-		return ticker
+// StopTimer stops the timer from running again, returning true if the
+// timer was still waiting to run when it was stopped.
+func StopTimer(timer *Timer) bool {
+	if timer == nil || timer.stopped {
+		return false
 	}
 
-	go func() {
-		for range ticker.C {
-			callback()
-		}
-	}()
-
-	tickers[fmt.Sprintf("%p", ticker)] = ticker
+	timer.stopped = true
 
-	return ticker
+	return true
 }
 
-func StopScheduledTimer(ticker *time.Ticker) bool {
-	if ticker == nil {
-		return false
-	}
-
-	id := fmt.Sprintf("%p", ticker)
-
-	if fakeTime != nil {
-		for _, ft := range fakeTimers {
-			if ft.id == id && ft.isTicker {
-				ft.stopped = true
-				return true
-			}
+// FindTimer returns the pending timer with the given ID, or nil if there
+// are no timers waiting to run with that ID.
+func FindTimer(id string) *Timer {
+	for _, timer := range timers {
+		if !timer.stopped && timer.ID() == id {
+			return timer
 		}
-
-		return false
 	}
 
-	if _, exists := tickers[id]; !exists {
-		return false
-	}
-
-	ticker.Stop()
-	delete(tickers, id)
-
-	return true
+	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/senither/zen-lang/objects/scheduler"
 )
 
 func resetTimePackage() {
@@ -84,34 +86,24 @@ func TestTimeTravelWithTimers(t *testing.T) {
 func TestClearTimers(t *testing.T) {
 	defer resetTimePackage()
 
-	timers["testTimer"] = StartDelayedTimer(func() {
+	Freeze(0)
+
+	StartDelayedTimer(func() {
 		panic("This should not be called")
 	}, 10)
-	tickers["testTicker"] = StartScheduledTimer(func() {
+	StartScheduledTimer(func() {
 		panic("This should not be called")
 	}, 10)
-	fakeTimers = append(fakeTimers, &FakeTime{
-		callback: func() {},
-		delay:    10,
-		lastCall: 0,
-	})
 
 	ClearTimers()
 
 	if len(timers) != 0 {
-		t.Errorf("Expected timers map to be empty after ClearTimers")
-	}
-
-	if len(tickers) != 0 {
-		t.Errorf("Expected tickers map to be empty after ClearTimers")
-	}
-
-	if len(fakeTimers) != 0 {
-		t.Errorf("Expected fakeTimers slice to be empty after ClearTimers")
+		t.Errorf("Expected timers slice to be empty after ClearTimers")
 	}
 
 	// Ensure no panics occur when original timers would have fired
-	time.Sleep(15 * time.Millisecond)
+	TimeTravel(15)
+	RunEventLoop()
 }
 
 func TestNow(t *testing.T) {
@@ -193,20 +185,25 @@ func TestStartDelayedTimerWithoutFreezing(t *testing.T) {
 		timesFired++
 	}, 5)
 
-	if _, exists := timers[fmt.Sprintf("%p", timer)]; !exists {
-		t.Errorf("Expected timer to be added to timers map")
+	if FindTimer(timer.ID()) != timer {
+		t.Errorf("Expected timer to be added to the pending timers")
 	}
 
 	time.Sleep(15 * time.Millisecond)
 
+	// Timers only run on the event loop, never in the background.
+	if timesFired != 0 {
+		t.Errorf("Expected timer to not fire outside of the event loop, got %d", timesFired)
+	}
+
+	RunEventLoop()
+
 	if timesFired != 1 {
 		t.Errorf("Expected timer to have fired once, got %d", timesFired)
 	}
 
-	StopDelayedTimer(timer)
-
-	if _, exists := timers[fmt.Sprintf("%p", timer)]; exists {
-		t.Errorf("Expected timer to be removed from timers map after stopping")
+	if FindTimer(timer.ID()) != nil {
+		t.Errorf("Expected timer to be removed from the pending timers after firing")
 	}
 }
 
@@ -219,15 +216,15 @@ func TestStartDelayedTimerWithFreezing(t *testing.T) {
 		timesFired++
 	}, 10)
 
-	if _, exists := timers[fmt.Sprintf("%p", timer)]; exists {
-		t.Errorf("Expected timer to not be in timers map when time is frozen")
+	if FindTimer(timer.ID()) != timer {
+		t.Errorf("Expected timer to be added to the pending timers")
 	}
 
 	TimeTravel(100)
 
-	stopped := StopDelayedTimer(timer)
-	if !stopped {
-		t.Errorf("Expected StopDelayedTimer to return true for valid timer")
+	stopped := StopTimer(timer)
+	if stopped {
+		t.Errorf("Expected StopTimer to return false for a timer that already fired")
 	}
 
 	err := TimeTravel(20)
@@ -244,24 +241,32 @@ func TestStartScheduledTimerWithoutFreezing(t *testing.T) {
 	defer resetTimePackage()
 
 	timesFired := 0
-	ticker := StartScheduledTimer(func() {
+	var ticker *Timer
+	ticker = StartScheduledTimer(func() {
 		timesFired++
+
+		if timesFired == 3 {
+			StopTimer(ticker)
+		}
 	}, 5)
 
-	if _, exists := tickers[fmt.Sprintf("%p", ticker)]; !exists {
-		t.Errorf("Expected ticker to be added to tickers map")
+	if FindTimer(ticker.ID()) != ticker {
+		t.Errorf("Expected ticker to be added to the pending timers")
 	}
 
-	time.Sleep(20 * time.Millisecond)
+	start := Now()
+	RunEventLoop()
 
-	if timesFired < 2 || timesFired > 4 {
-		t.Errorf("Expected ticker to have fired between 2 and 4 times, got %d", timesFired)
+	if timesFired != 3 {
+		t.Errorf("Expected ticker to have fired 3 times, got %d", timesFired)
 	}
 
-	StopScheduledTimer(ticker)
+	if elapsed := Now() - start; elapsed < 15 {
+		t.Errorf("Expected at least 15 milliseconds to have passed running the event loop, got %d", elapsed)
+	}
 
-	if _, exists := tickers[fmt.Sprintf("%p", ticker)]; exists {
-		t.Errorf("Expected ticker to be removed from tickers map after stopping")
+	if FindTimer(ticker.ID()) != nil {
+		t.Errorf("Expected ticker to be removed from the pending timers after stopping")
 	}
 }
 
@@ -274,21 +279,111 @@ func TestStartScheduledTimerWithFreezing(t *testing.T) {
 		timesFired++
 	}, 10)
 
-	if _, exists := tickers[fmt.Sprintf("%p", ticker)]; exists {
-		t.Errorf("Expected ticker to not be in tickers map when time is frozen")
-	}
-
 	err := TimeTravel(50)
 	if err != nil {
 		t.Errorf("Did not expect error when calling TimeTravel after freezing time")
 	}
 
-	stopped := StopScheduledTimer(ticker)
+	stopped := StopTimer(ticker)
 	if !stopped {
-		t.Errorf("Expected StopScheduledTimer to return true for valid ticker")
+		t.Errorf("Expected StopTimer to return true for a pending ticker")
 	}
 
 	if timesFired != 5 {
 		t.Errorf("Expected ticker to have fired 5 times, got %d", timesFired)
 	}
 }
+
+func TestRunEventLoop(t *testing.T) {
+	defer resetTimePackage()
+	Freeze(0)
+
+	order := []string{}
+	StartDelayedTimer(func() {
+		order = append(order, "second")
+	}, 20)
+	StartDelayedTimer(func() {
+		order = append(order, "first")
+
+		StartDelayedTimer(func() {
+			order = append(order, "third")
+		}, 30)
+	}, 10)
+
+	RunEventLoop()
+
+	if fmt.Sprint(order) != "[first second third]" {
+		t.Errorf("Expected timers to run in order, got %v", order)
+	}
+
+	if Now() != 40 {
+		t.Errorf("Expected the event loop to advance frozen time to 40, got %d", Now())
+	}
+
+	if HasPendingTimers() {
+		t.Errorf("Expected no pending timers after running the event loop")
+	}
+}
+
+func TestRunDueTimers(t *testing.T) {
+	defer resetTimePackage()
+	Freeze(0)
+
+	order := []string{}
+	StartDelayedTimer(func() {
+		order = append(order, "later")
+	}, 20)
+	StartDelayedTimer(func() {
+		order = append(order, "due")
+	}, 0)
+
+	RunDueTimers()
+
+	if fmt.Sprint(order) != "[due]" {
+		t.Errorf("Expected only the due timer to run, got %v", order)
+	}
+
+	if Now() != 0 {
+		t.Errorf("Expected frozen time to stay at 0, got %d", Now())
+	}
+
+	TimeTravel(20)
+
+	if fmt.Sprint(order) != "[due later]" {
+		t.Errorf("Expected the remaining timer to run once due, got %v", order)
+	}
+}
+
+func TestSleepWithinCoroutine(t *testing.T) {
+	defer resetTimePackage()
+	Freeze(0)
+
+	order := []string{}
+	scheduler.StartCoroutine(func() {
+		order = append(order, "before")
+		Sleep(50)
+		order = append(order, "after")
+	})
+
+	order = append(order, "outside")
+
+	if !RunUntil(func() bool { return len(order) == 3 }) {
+		t.Errorf("Expected the sleeping coroutine to be resumed")
+	}
+
+	if fmt.Sprint(order) != "[before outside after]" {
+		t.Errorf("Expected the coroutine to resume after sleeping, got %v", order)
+	}
+
+	if Now() != 50 {
+		t.Errorf("Expected frozen time to be advanced to 50, got %d", Now())
+	}
+}
+
+func TestRunUntilWithoutTimers(t *testing.T) {
+	defer resetTimePackage()
+
+	if RunUntil(func() bool { return false }) {
+		t.Errorf("Expected RunUntil to fail when there are no timers left to run")
+	}
+}
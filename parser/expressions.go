@@ -1,20 +1,32 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/senither/zen-lang/ast"
+	"github.com/senither/zen-lang/objects/decimal"
 	"github.com/senither/zen-lang/tokens"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	TERNARY     // x ? y : z
+	COALESCE    // ??
 	LOGICAL_OR  // ||
 	LOGICAL_AND // &&
 	EQUALS      // == or !=
 	LESSGREATER // < or >
+	RANGE       // .. or ..<
+	BITWISE_OR  // |
+	BITWISE_XOR // ~
+	BITWISE_AND // &
+	SHIFT       // << or >>
 	SUM         // + -
 	PRODUCT     // * / %
 	EXPONENT    // ^
@@ -24,22 +36,32 @@ const (
 )
 
 var precedences = map[tokens.TokenType]int{
-	tokens.EQ:       EQUALS,
-	tokens.NOT_EQ:   EQUALS,
-	tokens.LT:       LESSGREATER,
-	tokens.GT:       LESSGREATER,
-	tokens.LT_EQ:    LESSGREATER,
-	tokens.GT_EQ:    LESSGREATER,
-	tokens.OR:       LOGICAL_OR,
-	tokens.AND:      LOGICAL_AND,
-	tokens.PLUS:     SUM,
-	tokens.MINUS:    SUM,
-	tokens.SLASH:    PRODUCT,
-	tokens.ASTERISK: PRODUCT,
-	tokens.MOD:      PRODUCT,
-	tokens.CARET:    EXPONENT,
-	tokens.LPAREN:   CALL,
-	tokens.LBRACKET: INDEX,
+	tokens.EQ:             EQUALS,
+	tokens.NOT_EQ:         EQUALS,
+	tokens.LT:             LESSGREATER,
+	tokens.GT:             LESSGREATER,
+	tokens.LT_EQ:          LESSGREATER,
+	tokens.GT_EQ:          LESSGREATER,
+	tokens.QUESTION:       TERNARY,
+	tokens.NULL_COALESCE:  COALESCE,
+	tokens.OR:             LOGICAL_OR,
+	tokens.AND:            LOGICAL_AND,
+	tokens.RANGE:          RANGE,
+	tokens.RANGE_EX:       RANGE,
+	tokens.BIT_OR:         BITWISE_OR,
+	tokens.TILDE:          BITWISE_XOR,
+	tokens.BIT_AND:        BITWISE_AND,
+	tokens.SHIFT_LEFT:     SHIFT,
+	tokens.SHIFT_RIGHT:    SHIFT,
+	tokens.PLUS:           SUM,
+	tokens.MINUS:          SUM,
+	tokens.SLASH:          PRODUCT,
+	tokens.ASTERISK:       PRODUCT,
+	tokens.MOD:            PRODUCT,
+	tokens.CARET:          EXPONENT,
+	tokens.LPAREN:         CALL,
+	tokens.LBRACKET:       INDEX,
+	tokens.OPTIONAL_INDEX: INDEX,
 }
 
 type (
@@ -109,7 +131,7 @@ func (p *Parser) parseIdentifier() ast.Expression {
 		p.nextToken()
 
 		return p.parseSuffixExpression(ident)
-	} else if p.peekTokenIs(tokens.PERIOD) {
+	} else if p.peekTokenIs(tokens.PERIOD) || p.peekTokenIs(tokens.OPTIONAL_CHAIN) {
 		p.nextToken()
 
 		return p.parseChainExpression(ident)
@@ -119,10 +141,20 @@ func (p *Parser) parseIdentifier() ast.Expression {
 }
 
 func (p *Parser) parseChainExpression(left ast.Expression) ast.Expression {
-	chain := &ast.ChainExpression{Token: p.curToken, Left: left}
+	chain := &ast.ChainExpression{
+		Token:    p.curToken,
+		Left:     left,
+		Optional: p.curTokenIs(tokens.OPTIONAL_CHAIN),
+	}
 
 	p.nextToken()
 
+	// The await and catch keywords are allowed as property names, so
+	// the methods on tasks and promises can be called like any other.
+	if p.curTokenIs(tokens.AWAIT) || p.curTokenIs(tokens.CATCH) {
+		p.curToken.Type = tokens.IDENT
+	}
+
 	switch p.curToken.Type {
 	case tokens.IDENT:
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
@@ -130,19 +162,24 @@ func (p *Parser) parseChainExpression(left ast.Expression) ast.Expression {
 		if p.peekTokenIs(tokens.ASSIGN) {
 			p.nextToken()
 
+			if chain.Optional {
+				p.optionalChainAssignmentError()
+				return nil
+			}
+
 			assign := p.parseAssignmentExpression(ident)
 			chain.Right = &ast.AssignmentExpression{
 				Token: assign.GetToken(),
 				Left:  chain.Left,
 				Right: assign,
 			}
-		} else if p.peekTokenIs(tokens.PERIOD) {
+		} else if p.peekTokenIs(tokens.PERIOD) || p.peekTokenIs(tokens.OPTIONAL_CHAIN) {
 			p.nextToken()
 			chain.Right = p.parseChainExpression(ident)
 		} else if p.peekTokenIs(tokens.LPAREN) {
 			p.nextToken()
 			chain.Right = p.parseCallExpression(ident)
-		} else if p.peekTokenIs(tokens.LBRACKET) {
+		} else if p.peekTokenIs(tokens.LBRACKET) || p.peekTokenIs(tokens.OPTIONAL_INDEX) {
 			p.nextToken()
 			chain.Right = p.parseIndexExpression(ident)
 		} else {
@@ -169,6 +206,14 @@ func (p *Parser) parseChainExpression(left ast.Expression) ast.Expression {
 	}
 }
 
+func (p *Parser) optionalChainAssignmentError() {
+	p.errors = append(p.errors, ParserError{
+		Message:  "invalid assignment target, cannot assign to an optional chain",
+		FilePath: p.filePath,
+		Token:    p.curToken,
+	})
+}
+
 func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
 	expression := &ast.AssignmentExpression{
 		Token: p.curToken,
@@ -185,13 +230,29 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	literal := &ast.IntegerLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		if bigValue, ok := new(big.Int).SetString(p.curToken.Literal, 0); ok {
+			literal.Big = bigValue
+			return literal
+		}
+	}
+
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, ParserError{
-			Message:  msg,
-			FilePath: p.filePath,
-			Token:    p.curToken,
-		})
+		p.numberLiteralError("integer", err)
+		return nil
+	}
+
+	literal.Value = value
+
+	return literal
+}
+
+func (p *Parser) parseDecimalLiteral() ast.Expression {
+	literal := &ast.DecimalLiteral{Token: p.curToken}
+
+	value, err := decimal.Parse(p.curToken.Literal)
+	if err != nil {
+		p.numberLiteralError("decimal", err)
 		return nil
 	}
 
@@ -200,17 +261,71 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return literal
 }
 
+// numberLiteralError adds a parser error for the malformed number literal,
+// describing what's wrong with it when the problem can be pinpointed.
+func (p *Parser) numberLiteralError(kind string, err error) {
+	msg := fmt.Sprintf("could not parse %q as %s", p.curToken.Literal, kind)
+	if reason := describeMalformedNumber(p.curToken.Literal, err); reason != "" {
+		msg += ": " + reason
+	}
+
+	p.errors = append(p.errors, ParserError{
+		Message:  msg,
+		FilePath: p.filePath,
+		Token:    p.curToken,
+	})
+}
+
+func describeMalformedNumber(literal string, err error) string {
+	if strings.Contains(literal, "_") {
+		return "underscores must be placed between digits"
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		return "value out of range"
+	}
+
+	bases := map[byte]struct {
+		name   string
+		digits string
+	}{
+		'x': {"hexadecimal", "0123456789abcdef"},
+		'b': {"binary", "01"},
+		'o': {"octal", "01234567"},
+	}
+
+	if len(literal) > 1 && literal[0] == '0' {
+		if base, ok := bases[literal[1]|0x20]; ok {
+			digits := literal[2:]
+			if digits == "" {
+				return fmt.Sprintf("missing digits after the %s prefix", literal[:2])
+			}
+
+			for _, ch := range digits {
+				if !strings.ContainsRune(base.digits, unicode.ToLower(ch)) {
+					return fmt.Sprintf("invalid digit %q in %s literal", ch, base.name)
+				}
+			}
+
+			return ""
+		}
+	}
+
+	if _, exponent, ok := strings.Cut(strings.ToLower(literal), "e"); ok {
+		if strings.TrimLeft(exponent, "+-") == "" {
+			return "missing digits in the exponent"
+		}
+	}
+
+	return ""
+}
+
 func (p *Parser) parseFloatLiteral() ast.Expression {
 	literal := &ast.FloatLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
-		p.errors = append(p.errors, ParserError{
-			Message:  msg,
-			FilePath: p.filePath,
-			Token:    p.curToken,
-		})
+		p.numberLiteralError("float", err)
 		return nil
 	}
 
@@ -227,6 +342,27 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	template := &ast.TemplateLiteral{Token: p.curToken}
+	template.Parts = []ast.Expression{p.parseStringLiteral()}
+
+	for !p.curTokenIs(tokens.TEMPLATE_END) {
+		p.nextToken()
+
+		template.Parts = append(template.Parts, p.parseExpression(LOWEST))
+
+		if p.peekTokenIs(tokens.TEMPLATE_MIDDLE) {
+			p.nextToken()
+		} else if !p.expectPeek(tokens.TEMPLATE_END) {
+			return nil
+		}
+
+		template.Parts = append(template.Parts, p.parseStringLiteral())
+	}
+
+	return template
+}
+
 func (p *Parser) parseBooleanLiteral() ast.Expression {
 	return &ast.BooleanLiteral{Token: p.curToken, Value: p.curTokenIs(tokens.TRUE)}
 }
@@ -245,6 +381,16 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	for !p.peekTokenIs(tokens.RBRACE) {
 		p.nextToken()
 
+		if p.curTokenIs(tokens.ELLIPSIS) {
+			hash.Spreads = append(hash.Spreads, p.parseSpreadElement())
+
+			if !p.peekTokenIs(tokens.RBRACE) && !p.expectPeek(tokens.COMMA) {
+				return nil
+			}
+
+			continue
+		}
+
 		key := p.parseExpression(LOWEST)
 		if !p.expectPeek(tokens.COLON) {
 			return nil
@@ -292,6 +438,74 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	expression := &ast.SpawnExpression{Token: p.curToken}
+
+	p.nextToken()
+
+	call, ok := p.parseExpression(PREFIX).(*ast.CallExpression)
+	if !ok {
+		p.errors = append(p.errors, ParserError{
+			Message:  "spawn expression must be a function call",
+			FilePath: p.filePath,
+			Token:    expression.Token,
+		})
+
+		return nil
+	}
+
+	expression.Call = call
+
+	return expression
+}
+
+func (p *Parser) parseAsyncFunctionLiteral() ast.Expression {
+	token := p.curToken
+
+	if !p.expectPeek(tokens.FUNCTION) {
+		return nil
+	}
+
+	var funcLiteral *ast.FunctionLiteral
+
+	expression := p.parseFunctionLiteral()
+	switch expression := expression.(type) {
+	case *ast.FunctionLiteral:
+		funcLiteral = expression
+	case *ast.MethodLiteral:
+		funcLiteral = expression.Function
+	default:
+		return nil
+	}
+
+	if funcLiteral.Generator {
+		p.errors = append(p.errors, ParserError{
+			Message:  "generator functions cannot be async",
+			FilePath: p.filePath,
+			Token:    token,
+		})
+
+		return nil
+	}
+
+	funcLiteral.Async = true
+
+	return expression
+}
+
+func (p *Parser) parseAwaitExpression() ast.Expression {
+	expression := &ast.AwaitExpression{Token: p.curToken}
+
+	p.nextToken()
+
+	expression.Value = p.parseExpression(PREFIX)
+	if expression.Value == nil {
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) noPrefixParseFnError(t tokens.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s found", t)
 	p.errors = append(p.errors, ParserError{
@@ -312,15 +526,26 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 		p.nextToken()
 		rhs := p.parseExpression(LOWEST)
 
+		value := &ast.InfixExpression{
+			Token:    opToken,
+			Operator: opSymbol,
+			Left:     left,
+			Right:    rhs,
+		}
+
+		if chain, ok := left.(*ast.ChainExpression); ok {
+			return p.buildChainAssignment(chain, assignToken, value)
+		}
+
+		if index, ok := left.(*ast.IndexExpression); ok && index.Slice {
+			p.sliceAssignmentError(index)
+			return nil
+		}
+
 		return &ast.AssignmentExpression{
 			Token: assignToken,
 			Left:  left,
-			Right: &ast.InfixExpression{
-				Token:    opToken,
-				Operator: opSymbol,
-				Left:     left,
-				Right:    rhs,
-			},
+			Right: value,
 		}
 	}
 
@@ -337,6 +562,67 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// buildChainAssignment rebuilds the chain expression into the same structure
+// that parseChainExpression produces for assignments, so compound assignments
+// to chained properties are compiled and evaluated like normal assignments.
+func (p *Parser) buildChainAssignment(
+	chain *ast.ChainExpression,
+	assignToken tokens.Token,
+	value ast.Expression,
+) ast.Expression {
+	if chain.Optional {
+		p.errors = append(p.errors, ParserError{
+			Message:  fmt.Sprintf("invalid left hand side in compound assignment: %s", chain.String()),
+			FilePath: p.filePath,
+			Token:    assignToken,
+		})
+		return nil
+	}
+
+	result := &ast.ChainExpression{Token: chain.Token, Left: chain.Left}
+
+	switch right := chain.Right.(type) {
+	case *ast.Identifier:
+		result.Right = &ast.AssignmentExpression{
+			Token: assignToken,
+			Left:  chain.Left,
+			Right: &ast.AssignmentExpression{Token: assignToken, Left: right, Right: value},
+		}
+	case *ast.IndexExpression:
+		result.Right = &ast.AssignmentExpression{Token: assignToken, Left: right, Right: value}
+	case *ast.ChainExpression:
+		result.Right = p.buildChainAssignment(right, assignToken, value)
+
+	default:
+		p.errors = append(p.errors, ParserError{
+			Message:  fmt.Sprintf("invalid left hand side in compound assignment: %s", chain.String()),
+			FilePath: p.filePath,
+			Token:    assignToken,
+		})
+		return nil
+	}
+
+	return result
+}
+
+func (p *Parser) parseTernaryExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.TernaryExpression{Token: p.curToken, Condition: condition}
+
+	p.nextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(tokens.COLON) {
+		return nil
+	}
+
+	// The alternative is parsed with the lowest precedence so nested
+	// ternaries are right-associative, i.e. a ? b : c ? d : e
+	p.nextToken()
+	expression.Alternative = p.parseExpression(LOWEST)
+
+	return expression
+}
+
 func (p *Parser) parseSuffixExpression(left ast.Expression) ast.Expression {
 	expression := &ast.SuffixExpression{
 		Token:    p.curToken,
@@ -417,12 +703,63 @@ func (p *Parser) parseWhileExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseForExpression() ast.Expression {
+	expression := &ast.ForExpression{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(tokens.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(tokens.IDENT) {
+		return nil
+	}
+
+	expression.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(tokens.COMMA) {
+		p.nextToken()
+
+		if !p.expectPeek(tokens.IDENT) {
+			return nil
+		}
+
+		expression.Key = expression.Value
+		expression.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(tokens.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(tokens.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	expression.Body = p.parseBlockStatement()
+
+	return expression
+}
+
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	funcLiteral := &ast.FunctionLiteral{
 		Token: p.curToken,
 		Name:  nil,
 	}
 
+	if p.peekTokenIs(tokens.ASTERISK) {
+		p.nextToken()
+		funcLiteral.Generator = true
+	}
+
 	if p.peekTokenIs(tokens.IDENT) {
 		p.nextToken()
 		funcLiteral.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
@@ -432,43 +769,125 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	funcLiteral.Parameters = p.parseFunctionParameters()
+	// An anonymous function whose first parameter is followed by another
+	// identifier is a method declaration, like `func (u User) greet() {}`.
+	if funcLiteral.Name == nil && p.peekTokenIs(tokens.IDENT) {
+		p.nextToken()
+
+		if p.peekTokenIs(tokens.IDENT) {
+			return p.parseMethodLiteral(funcLiteral)
+		}
+
+		funcLiteral.Parameters, funcLiteral.Rest = p.parseFunctionParameterList()
+	} else {
+		funcLiteral.Parameters, funcLiteral.Rest = p.parseFunctionParameters()
+	}
 
 	if !p.expectPeek(tokens.LBRACE) {
 		return nil
 	}
 
-	funcLiteral.Body = p.parseBlockStatement()
+	funcLiteral.Body = p.parseFunctionBody(funcLiteral)
 
 	return funcLiteral
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+func (p *Parser) parseMethodLiteral(funcLiteral *ast.FunctionLiteral) ast.Expression {
+	method := &ast.MethodLiteral{
+		Token:    funcLiteral.Token,
+		Receiver: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+		Function: funcLiteral,
+	}
+
+	p.nextToken()
+	method.Struct = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(tokens.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(tokens.IDENT) {
+		return nil
+	}
 
+	funcLiteral.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(tokens.LPAREN) {
+		return nil
+	}
+
+	funcLiteral.Parameters, funcLiteral.Rest = p.parseFunctionParameters()
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	funcLiteral.Body = p.parseFunctionBody(funcLiteral)
+
+	return method
+}
+
+// parseFunctionBody parses the body of the function, while keeping track of
+// whether the function is a generator so yield statements can be validated.
+func (p *Parser) parseFunctionBody(funcLiteral *ast.FunctionLiteral) *ast.BlockStatement {
+	p.generators = append(p.generators, funcLiteral.Generator)
+	defer func() { p.generators = p.generators[:len(p.generators)-1] }()
+
+	return p.parseBlockStatement()
+}
+
+func (p *Parser) parseFunctionParameters() ([]ast.Expression, *ast.Identifier) {
 	if p.peekTokenIs(tokens.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return []ast.Expression{}, nil
 	}
 
 	p.nextToken()
 
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
+	return p.parseFunctionParameterList()
+}
+
+// parseFunctionParameterList parses the parameters of a function, starting
+// with the current token being the first parameter in the list.
+func (p *Parser) parseFunctionParameterList() ([]ast.Expression, *ast.Identifier) {
+	parameters := []ast.Expression{}
+
+	for {
+		// The rest parameter collects any remaining arguments into an
+		// array, so it must always be the last parameter in the list.
+		if p.curTokenIs(tokens.ELLIPSIS) {
+			if !p.expectPeek(tokens.IDENT) {
+				return nil, nil
+			}
+
+			rest := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.expectPeek(tokens.RPAREN) {
+				return nil, nil
+			}
+
+			return parameters, rest
+		}
+
+		param := p.parseBindingElement()
+		if param == nil {
+			return nil, nil
+		}
+
+		parameters = append(parameters, param)
+
+		if !p.peekTokenIs(tokens.COMMA) {
+			break
+		}
 
-	for p.peekTokenIs(tokens.COMMA) {
 		p.nextToken()
 		p.nextToken()
-
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, ident)
 	}
 
 	if !p.expectPeek(tokens.RPAREN) {
-		return nil
+		return nil, nil
 	}
 
-	return identifiers
+	return parameters, nil
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
@@ -493,10 +912,26 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 }
 
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	exp := &ast.IndexExpression{
+		Token:    p.curToken,
+		Left:     left,
+		Optional: p.curTokenIs(tokens.OPTIONAL_INDEX),
+	}
 
-	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+	if !p.peekTokenIs(tokens.COLON) {
+		p.nextToken()
+		exp.Index = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(tokens.COLON) {
+		p.nextToken()
+		exp.Slice = true
+
+		if !p.peekTokenIs(tokens.RBRACKET) {
+			p.nextToken()
+			exp.End = p.parseExpression(LOWEST)
+		}
+	}
 
 	if !p.expectPeek(tokens.RBRACKET) {
 		return nil
@@ -508,9 +943,41 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 
 	p.nextToken()
 
+	if exp.Optional {
+		p.optionalChainAssignmentError()
+		return nil
+	}
+
+	if exp.Slice {
+		p.sliceAssignmentError(exp)
+		return nil
+	}
+
 	return p.parseAssignmentExpression(exp)
 }
 
+func (p *Parser) sliceAssignmentError(exp *ast.IndexExpression) {
+	p.errors = append(p.errors, ParserError{
+		Message:  fmt.Sprintf("invalid assignment target, cannot assign to a slice: %s", exp.String()),
+		FilePath: p.filePath,
+		Token:    p.curToken,
+	})
+}
+
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	expression := &ast.RangeExpression{
+		Token:     p.curToken,
+		Start:     start,
+		Inclusive: p.curTokenIs(tokens.RANGE),
+	}
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	expression.End = p.parseExpression(precedence)
+
+	return expression
+}
+
 func (p *Parser) parseExpressionList(end tokens.TokenType) []ast.Expression {
 	expressions := []ast.Expression{}
 
@@ -520,7 +987,7 @@ func (p *Parser) parseExpressionList(end tokens.TokenType) []ast.Expression {
 	}
 
 	p.nextToken()
-	expressions = append(expressions, p.parseExpression(LOWEST))
+	expressions = append(expressions, p.parseListElement())
 
 	for p.peekTokenIs(tokens.COMMA) {
 		p.nextToken()
@@ -530,7 +997,7 @@ func (p *Parser) parseExpressionList(end tokens.TokenType) []ast.Expression {
 		}
 
 		p.nextToken()
-		expressions = append(expressions, p.parseExpression(LOWEST))
+		expressions = append(expressions, p.parseListElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -540,9 +1007,27 @@ func (p *Parser) parseExpressionList(end tokens.TokenType) []ast.Expression {
 	return expressions
 }
 
+func (p *Parser) parseListElement() ast.Expression {
+	if p.curTokenIs(tokens.ELLIPSIS) {
+		return p.parseSpreadElement()
+	}
+
+	return p.parseExpression(LOWEST)
+}
+
+func (p *Parser) parseSpreadElement() *ast.SpreadElement {
+	spread := &ast.SpreadElement{Token: p.curToken}
+
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+
+	return spread
+}
+
 func isCompoundOperator(t tokens.TokenType) bool {
 	switch t {
-	case tokens.PLUS, tokens.MINUS, tokens.ASTERISK, tokens.SLASH, tokens.MOD, tokens.CARET:
+	case tokens.PLUS, tokens.MINUS, tokens.ASTERISK, tokens.SLASH, tokens.MOD, tokens.CARET,
+		tokens.BIT_AND, tokens.BIT_OR, tokens.TILDE, tokens.SHIFT_LEFT, tokens.SHIFT_RIGHT:
 		return true
 	}
 
@@ -99,6 +99,131 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestNumberLiteralForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"0xFF", 255},
+		{"0b1010", 10},
+		{"0o755", 493},
+		{"1_000_000", 1000000},
+		{"1.5e-3", 0.0015},
+		{"2E3", 2000.0},
+	}
+
+	for _, tt := range tests {
+		t.Run("number literal: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+			if !ok {
+				t.Fatalf("program.Statements[0] is not an ExpressionStatement, got %T", program.Statements[0])
+			}
+
+			switch expected := tt.expected.(type) {
+			case int:
+				literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+				if !ok {
+					t.Fatalf("stmt.Expression is not ast.IntegerLiteral, got %T", stmt.Expression)
+				}
+
+				if literal.Value != int64(expected) {
+					t.Errorf("literal.Value is not %d, got %d", expected, literal.Value)
+				}
+			case float64:
+				literal, ok := stmt.Expression.(*ast.FloatLiteral)
+				if !ok {
+					t.Fatalf("stmt.Expression is not ast.FloatLiteral, got %T", stmt.Expression)
+				}
+
+				if literal.Value != expected {
+					t.Errorf("literal.Value is not %f, got %f", expected, literal.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestMalformedNumberLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		column   int
+	}{
+		{"var x = 0xZZ", `could not parse "0xZZ" as integer: invalid digit 'Z' in hexadecimal literal`, 9},
+		{"var x = 0b102", `could not parse "0b102" as integer: invalid digit '2' in binary literal`, 9},
+		{"var x = 0o9", `could not parse "0o9" as integer: invalid digit '9' in octal literal`, 9},
+		{"var x = 0x", `could not parse "0x" as integer: missing digits after the 0x prefix`, 9},
+		{"var x = 1e", `could not parse "1e" as float: missing digits in the exponent`, 9},
+		{"var x = 1.5e+", `could not parse "1.5e+" as float: missing digits in the exponent`, 9},
+		{"var x = 1e999", `could not parse "1e999" as float: value out of range`, 9},
+		{"var x = 1__000", `could not parse "1__000" as integer: underscores must be placed between digits`, 9},
+		{"var x = 1_000_", `could not parse "1_000_" as integer: underscores must be placed between digits`, 9},
+		{"var x = 0xFF_", `could not parse "0xFF_" as integer: underscores must be placed between digits`, 9},
+		{"var x = 1._5", `could not parse "1._5" as float: underscores must be placed between digits`, 9},
+		{"var x = 1e_5", `could not parse "1e_5" as float: underscores must be placed between digits`, 9},
+		{"var x = 1.5_d", `could not parse "1.5_" as decimal: underscores must be placed between digits`, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run("malformed number: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			errors := p.Errors()
+			if len(errors) == 0 {
+				t.Fatalf("expected parser errors for malformed number, got none")
+			}
+
+			if errors[0].Message != tt.expected {
+				t.Errorf("wrong error message.\nwant: %s\ngot:  %s", tt.expected, errors[0].Message)
+			}
+
+			if errors[0].Token.Line != 1 || errors[0].Token.Column != tt.column {
+				t.Errorf("wrong error position, want 1:%d, got %d:%d", tt.column, errors[0].Token.Line, errors[0].Token.Column)
+			}
+		})
+	}
+}
+
+func TestBigIntegerLiteralExpression(t *testing.T) {
+	input := "99999999999999999999"
+
+	l := lexer.New(input)
+	p := New(l, nil)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got %d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not an ExpressionStatement, got %T", program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IntegerLiteral, got %T", stmt.Expression)
+	}
+
+	if literal.Big == nil || literal.Big.String() != input {
+		t.Errorf("literal.Big is not %s, got %v", input, literal.Big)
+	}
+
+	if literal.TokenLiteral() != input {
+		t.Errorf("literal.TokenLiteral() is not %q, got %q", input, literal.TokenLiteral())
+	}
+}
+
 func TestFloatLiteralExpression(t *testing.T) {
 	input := "3.14"
 
@@ -131,6 +256,38 @@ func TestFloatLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestDecimalLiteralExpression(t *testing.T) {
+	input := "19.990d"
+
+	l := lexer.New(input)
+	p := New(l, nil)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got %d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not an ExpressionStatement, got %T", program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.DecimalLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.DecimalLiteral, got %T", stmt.Expression)
+	}
+
+	if literal.Value.String() != "19.990" {
+		t.Errorf("literal.Value is not 19.990, got %s", literal.Value)
+	}
+
+	if literal.String() != input {
+		t.Errorf("literal.String() is not %q, got %q", input, literal.String())
+	}
+}
+
 func TestStringLiteralExpression(t *testing.T) {
 	input := `"hello world";`
 
@@ -151,6 +308,50 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestTemplateLiteralExpression(t *testing.T) {
+	input := `"hello ${name}, ${1 + 2}!";`
+
+	l := lexer.New(input)
+	p := New(l, nil)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	template, ok := stmt.Expression.(*ast.TemplateLiteral)
+	if !ok {
+		t.Fatalf("statement expression is not *ast.TemplateLiteral. got %T", stmt.Expression)
+	}
+
+	if len(template.Parts) != 5 {
+		t.Fatalf("template.Parts does not contain 5 parts. got %d", len(template.Parts))
+	}
+
+	expectedStrings := map[int]string{0: "hello ", 2: ", ", 4: "!"}
+	for i, expected := range expectedStrings {
+		str, ok := template.Parts[i].(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("template.Parts[%d] is not *ast.StringLiteral. got %T", i, template.Parts[i])
+		}
+
+		if str.Value != expected {
+			t.Errorf("template.Parts[%d] not %q. got %q", i, expected, str.Value)
+		}
+	}
+
+	if !testIdentifier(t, template.Parts[1], "name") {
+		return
+	}
+
+	if !testInfixExpression(t, template.Parts[3], 1, "+", 2) {
+		return
+	}
+
+	if template.String() != `"hello ${name}, ${(1 + 2)}!"` {
+		t.Errorf("template.String() wrong. got %q", template.String())
+	}
+}
+
 func TestBooleanLiteralExpression(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -378,6 +579,7 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		{"-15", "-", 15},
 		{"!true", "!", true},
 		{"!false", "!", false},
+		{"~5", "~", 5},
 	}
 
 	for _, tt := range tests {
@@ -429,6 +631,11 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 ^ 5", 5, "^", 5},
 		{"5 == 5", 5, "==", 5},
 		{"5 != 5", 5, "!=", 5},
+		{"5 & 5", 5, "&", 5},
+		{"5 | 5", 5, "|", 5},
+		{"5 ~ 5", 5, "~", 5},
+		{"5 << 5", 5, "<<", 5},
+		{"5 >> 5", 5, ">>", 5},
 		{"foobar + barfoo;", "foobar", "+", "barfoo"},
 		{"foobar - barfoo;", "foobar", "-", "barfoo"},
 		{"foobar * barfoo;", "foobar", "*", "barfoo"},
@@ -479,6 +686,11 @@ func TestParsingCompoundInfixAssignments(t *testing.T) {
 		{"i /= 42", "i", "/", 42},
 		{"i %= 42", "i", "%", 42},
 		{"i ^= 42", "i", "^", 42},
+		{"i &= 42", "i", "&", 42},
+		{"i |= 42", "i", "|", 42},
+		{"i ~= 42", "i", "~", 42},
+		{"i <<= 42", "i", "<<", 42},
+		{"i >>= 42", "i", ">>", 42},
 	}
 
 	for _, tt := range tests {
@@ -527,6 +739,39 @@ func TestParsingCompoundInfixAssignments(t *testing.T) {
 	}
 }
 
+func TestParsingCompoundChainAssignments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[0] += 1", "a[0] = a[0] + 1"},
+		{"a.b -= 1", "a.b = a.b - 1"},
+		{"a.b.c *= 2", "a.b.c = a.b.c * 2"},
+		{"a.b[1] /= 2", "a.b[1] = a.b[1] / 2"},
+		{"a.b.c[1] ^= 2", "a.b.c[1] = a.b.c[1] ^ 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run("compound chain assignment: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			expectedLexer := lexer.New(tt.expected)
+			expectedParser := New(expectedLexer, nil)
+
+			expectedProgram := expectedParser.ParseProgram()
+			checkParserErrors(t, expectedParser)
+
+			if program.String() != expectedProgram.String() {
+				t.Errorf("program.String() wrong.\nexpected %q\ngot %q", expectedProgram.String(), program.String())
+			}
+		})
+	}
+}
+
 func TestParsingSuffixExpressions(t *testing.T) {
 	tests := []struct {
 		input string
@@ -705,6 +950,54 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[1], b[0], 2 * [3, 4][1])",
 			"add((a * (b[1])), (b[0]), (2 * ([3, 4][1])))",
 		},
+		{
+			"a > b ? a + 1 : b - 1",
+			"((a > b) ? (a + 1) : (b - 1))",
+		},
+		{
+			"a ? b : c ? d : e",
+			"(a ? b : (c ? d : e))",
+		},
+		{
+			"a || b ? c : d",
+			"((a || b) ? c : d)",
+		},
+		{
+			"a ?? b ?? c",
+			"((a ?? b) ?? c)",
+		},
+		{
+			"a ?? b || c",
+			"(a ?? (b || c))",
+		},
+		{
+			"a ?? b ? c : d",
+			"((a ?? b) ? c : d)",
+		},
+		{
+			"a | b ~ c & d",
+			"(a | (b ~ (c & d)))",
+		},
+		{
+			"a & b << c + d",
+			"(a & (b << (c + d)))",
+		},
+		{
+			"a >> b << c",
+			"((a >> b) << c)",
+		},
+		{
+			"a & b == c | d",
+			"((a & b) == (c | d))",
+		},
+		{
+			"~a & ~b",
+			"((~a) & (~b))",
+		},
+		{
+			"-a << 2 ^ b",
+			"((-a) << (2 ^ b))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -723,6 +1016,51 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 	}
 }
 
+func TestTernaryExpression(t *testing.T) {
+	input := `x < y ? x : y`
+
+	l := lexer.New(input)
+	p := New(l, nil)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got %T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.TernaryExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.TernaryExpression. got %T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	if !testIdentifier(t, exp.Consequence, "x") {
+		return
+	}
+
+	if !testIdentifier(t, exp.Alternative, "y") {
+		return
+	}
+}
+
+func TestTernaryExpressionMissingColon(t *testing.T) {
+	l := lexer.New(`x ? y`)
+	p := New(l, nil)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected parser errors for ternary without an alternative, got none")
+	}
+}
+
 func TestIfExpression(t *testing.T) {
 	input := `if (x < y) { x }`
 
@@ -950,12 +1288,69 @@ func TestWhileExpression(t *testing.T) {
 	}
 }
 
-func TestFunctionLiteralParsing(t *testing.T) {
-	input := "func hello(x, y) { x + y; }"
+func TestForExpression(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedKey   string
+		expectedValue string
+		expectedBody  string
+	}{
+		{"for (v in items) { v }", "", "v", "v"},
+		{"for (k, v in items) { k }", "k", "v", "k"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l, nil)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got %T", program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.ForExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.ForExpression. got %T", stmt.Expression)
+		}
+
+		if tt.expectedKey == "" && exp.Key != nil {
+			t.Errorf("exp.Key is not nil. got %q", exp.Key.Value)
+		}
+
+		if tt.expectedKey != "" && !testIdentifier(t, exp.Key, tt.expectedKey) {
+			return
+		}
+
+		if !testIdentifier(t, exp.Value, tt.expectedValue) {
+			return
+		}
+
+		if !testIdentifier(t, exp.Iterable, "items") {
+			return
+		}
+
+		if len(exp.Body.Statements) != 1 {
+			t.Fatalf("for body is not 1 statement. got %d", len(exp.Body.Statements))
+		}
+
+		if exp.Body.Statements[0].String() != tt.expectedBody {
+			t.Errorf("for body is not %q. got %q", tt.expectedBody, exp.Body.Statements[0].String())
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	input := `match (x) { 1, 2 => "low", [a, _] if a > 5 => a, _ => { null } }`
 
 	l := lexer.New(input)
 	p := New(l, nil)
-
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
 
@@ -968,39 +1363,159 @@ func TestFunctionLiteralParsing(t *testing.T) {
 		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got %T", program.Statements[0])
 	}
 
-	funcLiteral, ok := stmt.Expression.(*ast.FunctionLiteral)
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
 	if !ok {
-		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got %T", stmt.Expression)
+		t.Fatalf("stmt.Expression is not ast.MatchExpression. got %T", stmt.Expression)
 	}
 
-	if funcLiteral.Name == nil {
-		t.Fatalf("function literal name is nil, expected *ast.Identifier, got %T", funcLiteral.Name)
+	if !testIdentifier(t, exp.Subject, "x") {
+		return
 	}
 
-	testLiteralExpression(t, funcLiteral.Name, "hello")
+	if len(exp.Arms) != 3 {
+		t.Fatalf("match expression does not contain 3 arms. got %d", len(exp.Arms))
+	}
 
-	if len(funcLiteral.Parameters) != 2 {
-		t.Errorf("function literal parameters are not 2. got %d", len(funcLiteral.Parameters))
+	if len(exp.Arms[0].Patterns) != 2 {
+		t.Fatalf("first arm does not contain 2 patterns. got %d", len(exp.Arms[0].Patterns))
 	}
 
-	testLiteralExpression(t, funcLiteral.Parameters[0], "x")
-	testLiteralExpression(t, funcLiteral.Parameters[1], "y")
+	testLiteralExpression(t, exp.Arms[0].Patterns[0], 1)
+	testLiteralExpression(t, exp.Arms[0].Patterns[1], 2)
 
-	if len(funcLiteral.Body.Statements) != 1 {
-		t.Errorf("function.Body.Statements does not have 1 statement. got %d", len(funcLiteral.Body.Statements))
+	if exp.Arms[0].Guard != nil {
+		t.Errorf("first arm guard is not nil. got %q", exp.Arms[0].Guard.String())
 	}
 
-	bodyStmt, ok := funcLiteral.Body.Statements[0].(*ast.ExpressionStatement)
+	array, ok := exp.Arms[1].Patterns[0].(*ast.ArrayPattern)
 	if !ok {
-		t.Fatalf("function.Body.Statements[0] is not ast.ExpressionStatement. got %T", funcLiteral.Body.Statements[0])
+		t.Fatalf("second arm pattern is not ast.ArrayPattern. got %T", exp.Arms[1].Patterns[0])
 	}
 
-	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
-}
+	if len(array.Elements) != 2 {
+		t.Fatalf("array pattern does not contain 2 elements. got %d", len(array.Elements))
+	}
 
-func TestNamedFunctionLiteralParsing(t *testing.T) {
-	input := []struct {
-		name string
+	testIdentifier(t, array.Elements[0], "a")
+	testIdentifier(t, array.Elements[1], "_")
+
+	if !testInfixExpression(t, exp.Arms[1].Guard, "a", ">", 5) {
+		return
+	}
+
+	testIdentifier(t, exp.Arms[2].Patterns[0], "_")
+
+	if len(exp.Arms[2].Body.Statements) != 1 {
+		t.Fatalf("last arm body is not 1 statement. got %d", len(exp.Arms[2].Body.Statements))
+	}
+}
+
+func TestMatchExpressionPatternParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 => a }", "match (x) { 1 => { a } }"},
+		{"match (x) { -1, 1.5 => a }", "match (x) { (-1), 1.5 => { a } }"},
+		{`match (x) { "a", true, null => a }`, `match (x) { "a", true, null => { a } }`},
+		{"match (x) { [a, [b, c]] => a }", "match (x) { [a, [b, c]] => { a } }"},
+		{"match (x) { { name, age } => a }", "match (x) { {name, age} => { a } }"},
+		{`match (x) { { "name": n, user: { id } } => n }`, `match (x) { {"name": n, "user": {id}} => { n } }`},
+		{"match (x) { n if n > 1 => n, _ => 0 }", "match (x) { n if (n > 1) => { n }, _ => { 0 } }"},
+		{"match (x) { Status.Active => a }", "match (x) { Status.Active => { a } }"},
+		{"match (x) { Status.Banned(r), Status.Moved([a, b]) => r }", "match (x) { Status.Banned(r), Status.Moved([a, b]) => { r } }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("match expression: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatchExpressionParsingErrors(t *testing.T) {
+	tests := []string{
+		"match (x) { 1 + 1 => a }",
+		"match (x) { 1 a }",
+		`match (x) { { "name" } => a }`,
+		"match (x) { func() {} => a }",
+		"match (x) { Status. => a }",
+		"match (x) { Status.Banned(r => a }",
+	}
+
+	for _, input := range tests {
+		t.Run("match expression: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid match expression, got none")
+			}
+		})
+	}
+}
+
+func TestFunctionLiteralParsing(t *testing.T) {
+	input := "func hello(x, y) { x + y; }"
+
+	l := lexer.New(input)
+	p := New(l, nil)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got %T", program.Statements[0])
+	}
+
+	funcLiteral, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got %T", stmt.Expression)
+	}
+
+	if funcLiteral.Name == nil {
+		t.Fatalf("function literal name is nil, expected *ast.Identifier, got %T", funcLiteral.Name)
+	}
+
+	testLiteralExpression(t, funcLiteral.Name, "hello")
+
+	if len(funcLiteral.Parameters) != 2 {
+		t.Errorf("function literal parameters are not 2. got %d", len(funcLiteral.Parameters))
+	}
+
+	testLiteralExpression(t, funcLiteral.Parameters[0], "x")
+	testLiteralExpression(t, funcLiteral.Parameters[1], "y")
+
+	if len(funcLiteral.Body.Statements) != 1 {
+		t.Errorf("function.Body.Statements does not have 1 statement. got %d", len(funcLiteral.Body.Statements))
+	}
+
+	bodyStmt, ok := funcLiteral.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("function.Body.Statements[0] is not ast.ExpressionStatement. got %T", funcLiteral.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
+
+func TestNamedFunctionLiteralParsing(t *testing.T) {
+	input := []struct {
+		name string
 		expr string
 	}{
 		{
@@ -1095,6 +1610,327 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestFunctionParameterPatternParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"func([a, b]) {};", "func ([a, b]) {  }"},
+		{"func(x, { name, age = 18 }) {};", "func (x, {name, age = 18}) {  }"},
+		{"func name([a, [b, c] = [1, 2]], y) {};", "func name([a, [b, c] = [1, 2]], y) {  }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse function parameters: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFunctionDefaultAndRestParameterParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"func(a, b = 10) {};", "func (a, b = 10) {  }"},
+		{"func(a = 1 + 2) {};", "func (a = (1 + 2)) {  }"},
+		{"func(...rest) {};", "func (...rest) {  }"},
+		{"func name(a, b = 2, ...rest) {};", "func name(a, b = 2, ...rest) {  }"},
+		{"func({ name } = {}, ...rest) {};", "func ({name} = {}, ...rest) {  }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse function parameters: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFunctionRestParameterParsingErrors(t *testing.T) {
+	tests := []string{
+		"func(...rest, a) {};",
+		"func(...[a, b]) {};",
+		"func(...) {};",
+	}
+
+	for _, input := range tests {
+		t.Run("parse function parameters: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid rest parameter, got none")
+			}
+		})
+	}
+}
+
+func TestMethodLiteralParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"func (u User) greet() { u.name }", "func (u User) greet() { (u.name) }"},
+		{"func (u User) rename(name, suffix = \"!\") {}", "func (u User) rename(name, suffix = \"!\") {  }"},
+		{"func (p Point) sum(...rest) {}", "func (p Point) sum(...rest) {  }"},
+		{"func (a, b) { a }", "func (a, b) { a }"},
+		{"func (a = 1) { a }", "func (a = 1) { a }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse method literal: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMethodLiteralParsingErrors(t *testing.T) {
+	tests := []string{
+		"func (u User) () {}",
+		"func (u User greet() {}",
+		"func (u User) greet {}",
+		"func (u User, a) greet() {}",
+	}
+
+	for _, input := range tests {
+		t.Run("parse method literal: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid method literal, got none")
+			}
+		})
+	}
+}
+
+func TestGeneratorFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"func* () { yield 1; }", "func* () { yield 1; }"},
+		{"func* numbers(n) { yield n; yield n + 1; }", "func* numbers(n) { yield n;yield (n + 1); }"},
+		{"func* () { yield; }", "func* () { yield; }"},
+		{"func* () { if (true) { yield 1 } }", "func* () { if (true) { yield 1; } }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse generator function: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestYieldStatementParsingErrors(t *testing.T) {
+	tests := []string{
+		"yield 1;",
+		"func () { yield 1; }",
+		"func* () { var f = func () { yield 1; }; }",
+	}
+
+	for _, input := range tests {
+		t.Run("parse yield statement: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for yield outside of a generator, got none")
+			}
+		})
+	}
+}
+
+func TestSpawnExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"spawn work();", "spawn work()"},
+		{"spawn work(1, 2 + 3);", "spawn work(1, (2 + 3))"},
+		{"var task = spawn work(...args);", "var task = spawn work(...args);"},
+		{"spawn func () { 1 }();", "spawn func () { 1 }()"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse spawn expression: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSpawnExpressionParsingErrors(t *testing.T) {
+	tests := []string{
+		"spawn work;",
+		"spawn 5;",
+		"spawn;",
+	}
+
+	for _, input := range tests {
+		t.Run("parse spawn expression: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid spawn expression, got none")
+			}
+		})
+	}
+}
+
+func TestAsyncFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"async func work() { 1 }", "async func work() { 1 }"},
+		{"var work = async func (a, b) { a + b };", "var work = async func (a, b) { (a + b) };"},
+		{"async func work() { await sleep(10); }", "async func work() { await sleep(10) }"},
+		{"async func (c Counter) next() { 1 }", "async func (c Counter) next() { 1 }"},
+		{"await work();", "await work()"},
+		{"await task.await();", "await (task.await())"},
+		{"promise.catch(handler);", "(promise.catch(handler))"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse async function: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAsyncFunctionParsingErrors(t *testing.T) {
+	tests := []string{
+		"async work();",
+		"async func* work() { yield 1; }",
+		"await;",
+	}
+
+	for _, input := range tests {
+		t.Run("parse async function: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid async function, got none")
+			}
+		})
+	}
+}
+
+func TestSpreadElementParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(...args)", "f(...args)"},
+		{"f(a, ...b, c)", "f(a, ...b, c)"},
+		{"f(...[1, 2])", "f(...[1, 2])"},
+		{"[...a, ...b]", "[...a, ...b]"},
+		{"[1, ...a + b]", "[1, ...(a + b)]"},
+		{"{...base}", "{...base}"},
+		{`{...base, ...other}`, `{...base, ...other}`},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse spread element: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestHashLiteralSpreadParsing(t *testing.T) {
+	l := lexer.New(`{...base, "key": 1}`)
+	p := New(l, nil)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got %T", stmt.Expression)
+	}
+
+	if len(hash.Spreads) != 1 {
+		t.Fatalf("hash.Spreads has wrong length. got %d", len(hash.Spreads))
+	}
+
+	testIdentifier(t, hash.Spreads[0].Value, "base")
+
+	if len(hash.Pairs) != 1 {
+		t.Fatalf("hash.Pairs has wrong length. got %d", len(hash.Pairs))
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -1410,3 +2246,133 @@ func TestChainIndexAssignmentExpressionParsing(t *testing.T) {
 	testLiteralExpression(t, indexExp.Index, 0)
 	testLiteralExpression(t, assignExp.Right, 100)
 }
+
+func TestOptionalChainExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"config?.server", "(config?.server)"},
+		{"config?.server?.port", "(config?.(server?.port))"},
+		{"config.server?.port", "(config.(server?.port))"},
+		{"items?[0]", "(items?[0])"},
+		{"config?.items?[1]", "(config?.(items?[1]))"},
+		{"config?.server ?? 80", "((config?.server) ?? 80)"},
+	}
+
+	for _, tt := range tests {
+		t.Run("optional chain expression: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestOptionalChainAssignmentParsingErrors(t *testing.T) {
+	tests := []string{
+		"config?.server = 1",
+		"items?[0] = 1",
+		"config?.port += 1",
+	}
+
+	for _, input := range tests {
+		t.Run("optional chain assignment: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for assigning to an optional chain, got none")
+			}
+		})
+	}
+}
+
+func TestRangeExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..10", "(1..10)"},
+		{"1..<10", "(1..<10)"},
+		{"a..b + 1", "(a..(b + 1))"},
+		{"0..len(items) - 1", "(0..(len(items) - 1))"},
+		{"1..5 == 1..5", "((1..5) == (1..5))"},
+		{"[...1..3]", "[...(1..3)]"},
+		{"for (i in 0..<3) { i }", "for (i in (0..<3)) { i }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse range expression: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSliceExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"arr[1:3]", "(arr[1:3])"},
+		{"arr[:-1]", "(arr[:(-1)])"},
+		{"str[2:]", "(str[2:])"},
+		{"arr[:]", "(arr[:])"},
+		{"arr[a + 1:b * 2]", "(arr[(a + 1):(b * 2)])"},
+		{"arr?[1:]", "(arr?[1:])"},
+		{"arr[1:][0]", "((arr[1:])[0])"},
+		{"config.items[1:2]", "(config.(items[1:2]))"},
+	}
+
+	for _, tt := range tests {
+		t.Run("parse slice expression: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSliceAssignmentParsingErrors(t *testing.T) {
+	tests := []string{
+		"arr[1:2] = [1]",
+		"arr[:] += [1]",
+		"arr[1:2",
+	}
+
+	for _, input := range tests {
+		t.Run("slice assignment: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid slice, got none")
+			}
+		})
+	}
+}
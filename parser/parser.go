@@ -19,6 +19,10 @@ type Parser struct {
 
 	prefixParseFns map[tokens.TokenType]prefixParseFn
 	infixParseFns  map[tokens.TokenType]infixParseFn
+
+	// generators tracks if each of the functions currently being
+	// parsed is a generator, and can use yield statements.
+	generators []bool
 }
 
 type ParserError struct {
@@ -53,9 +57,12 @@ func New(lexer *lexer.Lexer, filePath interface{}) *Parser {
 	p.registerPrefix(tokens.IDENT, p.parseIdentifier)
 	p.registerPrefix(tokens.INT, p.parseIntegerLiteral)
 	p.registerPrefix(tokens.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(tokens.DECIMAL, p.parseDecimalLiteral)
 	p.registerPrefix(tokens.STRING, p.parseStringLiteral)
+	p.registerPrefix(tokens.TEMPLATE_START, p.parseTemplateLiteral)
 	p.registerPrefix(tokens.BANG, p.parsePrefixExpression)
 	p.registerPrefix(tokens.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(tokens.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(tokens.NULL, p.parseNullLiteral)
 	p.registerPrefix(tokens.TRUE, p.parseBooleanLiteral)
 	p.registerPrefix(tokens.FALSE, p.parseBooleanLiteral)
@@ -64,7 +71,12 @@ func New(lexer *lexer.Lexer, filePath interface{}) *Parser {
 	p.registerPrefix(tokens.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(tokens.IF, p.parseIfExpression)
 	p.registerPrefix(tokens.WHILE, p.parseWhileExpression)
+	p.registerPrefix(tokens.FOR, p.parseForExpression)
+	p.registerPrefix(tokens.MATCH, p.parseMatchExpression)
 	p.registerPrefix(tokens.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(tokens.SPAWN, p.parseSpawnExpression)
+	p.registerPrefix(tokens.ASYNC, p.parseAsyncFunctionLiteral)
+	p.registerPrefix(tokens.AWAIT, p.parseAwaitExpression)
 
 	p.infixParseFns = make(map[tokens.TokenType]infixParseFn)
 	p.registerInfix(tokens.PLUS, p.parseInfixExpression)
@@ -81,8 +93,18 @@ func New(lexer *lexer.Lexer, filePath interface{}) *Parser {
 	p.registerInfix(tokens.GT_EQ, p.parseInfixExpression)
 	p.registerInfix(tokens.AND, p.parseInfixExpression)
 	p.registerInfix(tokens.OR, p.parseInfixExpression)
+	p.registerInfix(tokens.BIT_AND, p.parseInfixExpression)
+	p.registerInfix(tokens.BIT_OR, p.parseInfixExpression)
+	p.registerInfix(tokens.TILDE, p.parseInfixExpression)
+	p.registerInfix(tokens.SHIFT_LEFT, p.parseInfixExpression)
+	p.registerInfix(tokens.SHIFT_RIGHT, p.parseInfixExpression)
+	p.registerInfix(tokens.NULL_COALESCE, p.parseInfixExpression)
+	p.registerInfix(tokens.RANGE, p.parseRangeExpression)
+	p.registerInfix(tokens.RANGE_EX, p.parseRangeExpression)
+	p.registerInfix(tokens.QUESTION, p.parseTernaryExpression)
 	p.registerInfix(tokens.LPAREN, p.parseCallExpression)
 	p.registerInfix(tokens.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(tokens.OPTIONAL_INDEX, p.parseIndexExpression)
 
 	// Read two tokens, so curToken and peekToken are both set
 	p.nextToken()
@@ -135,6 +157,16 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseBreakStatement()
 	case tokens.CONTINUE_LOOP:
 		return p.parseContinueStatement()
+	case tokens.TRY:
+		return p.parseTryStatement()
+	case tokens.THROW:
+		return p.parseThrowStatement()
+	case tokens.YIELD:
+		return p.parseYieldStatement()
+	case tokens.STRUCT:
+		return p.parseStructStatement()
+	case tokens.ENUM:
+		return p.parseEnumStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -0,0 +1,284 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/senither/zen-lang/ast"
+	"github.com/senither/zen-lang/tokens"
+)
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken}
+
+	if !p.expectPeek(tokens.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(tokens.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(tokens.RBRACE) {
+		p.nextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+
+		expression.Arms = append(expression.Arms, arm)
+
+		if !p.peekTokenIs(tokens.RBRACE) && !p.expectPeek(tokens.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.RBRACE) {
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{}
+
+	for {
+		pattern := p.parsePattern()
+		if pattern == nil {
+			return nil
+		}
+
+		arm.Patterns = append(arm.Patterns, pattern)
+
+		if !p.peekTokenIs(tokens.COMMA) {
+			break
+		}
+
+		p.nextToken()
+		p.nextToken()
+	}
+
+	if p.peekTokenIs(tokens.IF) {
+		p.nextToken()
+		p.nextToken()
+
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(tokens.ARROW) {
+		return nil
+	}
+
+	arm.Token = p.curToken
+
+	if p.peekTokenIs(tokens.LBRACE) {
+		p.nextToken()
+		arm.Body = p.parseBlockStatement()
+
+		return arm
+	}
+
+	// Arms with a single expression are wrapped in a block, so both the
+	// compiler and evaluator only have to deal with one kind of body.
+	p.nextToken()
+	arm.Body = &ast.BlockStatement{
+		Token: arm.Token,
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)},
+		},
+	}
+
+	return arm
+}
+
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.curToken.Type {
+	case tokens.IDENT:
+		if p.peekTokenIs(tokens.PERIOD) {
+			return p.parseVariantPattern()
+		}
+
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	case tokens.LBRACKET:
+		return p.parseArrayPattern(p.parsePattern)
+	case tokens.LBRACE:
+		return p.parseHashPattern(p.parsePattern)
+	case tokens.INT, tokens.FLOAT, tokens.DECIMAL, tokens.STRING, tokens.TRUE, tokens.FALSE, tokens.NULL, tokens.MINUS:
+		return p.prefixParseFns[p.curToken.Type]()
+
+	default:
+		p.errors = append(p.errors, ParserError{
+			Message:  fmt.Sprintf("unexpected pattern token: %s", p.curToken.Literal),
+			FilePath: p.filePath,
+			Token:    p.curToken,
+		})
+		return nil
+	}
+}
+
+// parseVariantPattern parses enum variant patterns like `Status.Banned(reason)`,
+// where the optional patterns are matched against the values of the variant.
+func (p *Parser) parseVariantPattern() ast.Expression {
+	pattern := &ast.VariantPattern{
+		Token: p.curToken,
+		Enum:  &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+	}
+
+	p.nextToken()
+
+	if !p.expectPeek(tokens.IDENT) {
+		return nil
+	}
+
+	pattern.Variant = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.peekTokenIs(tokens.LPAREN) {
+		return pattern
+	}
+
+	p.nextToken()
+
+	for !p.peekTokenIs(tokens.RPAREN) {
+		p.nextToken()
+
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+
+		pattern.Patterns = append(pattern.Patterns, element)
+
+		if !p.peekTokenIs(tokens.RPAREN) && !p.expectPeek(tokens.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.RPAREN) {
+		return nil
+	}
+
+	return pattern
+}
+
+// parseBindingPattern parses the patterns used to destructure values in variable
+// statements and function parameters, unlike the patterns used by match
+// expressions they can't contain literals, but the values bound
+// within them are able to fall back to a default value.
+func (p *Parser) parseBindingPattern() ast.Expression {
+	switch p.curToken.Type {
+	case tokens.IDENT:
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	case tokens.LBRACKET:
+		return p.parseArrayPattern(p.parseBindingElement)
+	case tokens.LBRACE:
+		return p.parseHashPattern(p.parseBindingElement)
+
+	default:
+		p.errors = append(p.errors, ParserError{
+			Message:  fmt.Sprintf("unexpected binding pattern token: %s", p.curToken.Literal),
+			FilePath: p.filePath,
+			Token:    p.curToken,
+		})
+		return nil
+	}
+}
+
+func (p *Parser) parseBindingElement() ast.Expression {
+	pattern := p.parseBindingPattern()
+	if pattern == nil || !p.peekTokenIs(tokens.ASSIGN) {
+		return pattern
+	}
+
+	p.nextToken()
+
+	element := &ast.DefaultPattern{Token: p.curToken, Pattern: pattern}
+
+	p.nextToken()
+	element.Default = p.parseExpression(LOWEST)
+
+	return element
+}
+
+func (p *Parser) parseArrayPattern(parseElement func() ast.Expression) ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(tokens.RBRACKET) {
+		p.nextToken()
+
+		element := parseElement()
+		if element == nil {
+			return nil
+		}
+
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(tokens.RBRACKET) && !p.expectPeek(tokens.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (p *Parser) parseHashPattern(parseElement func() ast.Expression) ast.Expression {
+	pattern := &ast.HashPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(tokens.RBRACE) {
+		p.nextToken()
+
+		if !p.curTokenIs(tokens.IDENT) && !p.curTokenIs(tokens.STRING) {
+			p.errors = append(p.errors, ParserError{
+				Message:  fmt.Sprintf("expected identifier or string as hash pattern key, got %s", p.curToken.Literal),
+				FilePath: p.filePath,
+				Token:    p.curToken,
+			})
+			return nil
+		}
+
+		key := &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+		var value ast.Expression
+		if p.peekTokenIs(tokens.COLON) {
+			p.nextToken()
+			p.nextToken()
+
+			value = parseElement()
+			if value == nil {
+				return nil
+			}
+		} else if p.curTokenIs(tokens.IDENT) {
+			value = parseElement()
+			if value == nil {
+				return nil
+			}
+		} else {
+			p.peekError(tokens.COLON)
+			return nil
+		}
+
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(tokens.RBRACE) && !p.expectPeek(tokens.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
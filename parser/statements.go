@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+
 	"github.com/senither/zen-lang/ast"
 	"github.com/senither/zen-lang/tokens"
 )
@@ -13,13 +15,22 @@ func (p *Parser) parseVariableStatement() *ast.VariableStatement {
 		p.nextToken()
 	}
 
-	if !p.expectPeek(tokens.IDENT) {
-		return nil
-	}
+	if p.peekTokenIs(tokens.LBRACKET) || p.peekTokenIs(tokens.LBRACE) {
+		p.nextToken()
 
-	stmt.Name = &ast.Identifier{
-		Token: p.curToken,
-		Value: p.curToken.Literal,
+		stmt.Pattern = p.parseBindingPattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(tokens.IDENT) {
+			return nil
+		}
+
+		stmt.Name = &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		}
 	}
 
 	if !p.expectPeek(tokens.ASSIGN) {
@@ -110,3 +121,223 @@ func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
 
 	return stmt
 }
+
+func (p *Parser) parseTryStatement() *ast.TryStatement {
+	stmt := &ast.TryStatement{Token: p.curToken}
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	stmt.Block = p.parseBlockStatement()
+
+	if !p.expectPeek(tokens.CATCH) {
+		return nil
+	}
+
+	if p.peekTokenIs(tokens.LPAREN) {
+		p.nextToken()
+
+		if !p.expectPeek(tokens.IDENT) {
+			return nil
+		}
+
+		stmt.Parameter = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(tokens.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	stmt.Catch = p.parseBlockStatement()
+
+	return stmt
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(tokens.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseYieldStatement() *ast.YieldStatement {
+	stmt := &ast.YieldStatement{Token: p.curToken}
+
+	if len(p.generators) == 0 || !p.generators[len(p.generators)-1] {
+		p.errors = append(p.errors, ParserError{
+			Message:  "yield statement not within a generator function",
+			FilePath: p.filePath,
+			Token:    p.curToken,
+		})
+
+		return nil
+	}
+
+	if p.peekTokenIs(tokens.SEMICOLON) || p.peekTokenIs(tokens.RBRACE) {
+		if p.peekTokenIs(tokens.SEMICOLON) {
+			p.nextToken()
+		}
+
+		return stmt
+	}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(tokens.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	stmt := &ast.StructStatement{Token: p.curToken}
+
+	if !p.expectPeek(tokens.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	stmt.Fields = []*ast.Identifier{}
+	seen := map[string]bool{}
+
+	for !p.peekTokenIs(tokens.RBRACE) {
+		if !p.expectPeek(tokens.IDENT) {
+			return nil
+		}
+
+		if seen[p.curToken.Literal] {
+			p.errors = append(p.errors, ParserError{
+				Message:  fmt.Sprintf("duplicate field %q in struct %s", p.curToken.Literal, stmt.Name.Value),
+				FilePath: p.filePath,
+				Token:    p.curToken,
+			})
+
+			return nil
+		}
+
+		seen[p.curToken.Literal] = true
+		stmt.Fields = append(stmt.Fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(tokens.RBRACE) && !p.expectPeek(tokens.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(tokens.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseEnumStatement() *ast.EnumStatement {
+	stmt := &ast.EnumStatement{Token: p.curToken}
+
+	if !p.expectPeek(tokens.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(tokens.LBRACE) {
+		return nil
+	}
+
+	stmt.Variants = []*ast.EnumVariant{}
+	seen := map[string]bool{}
+
+	for !p.peekTokenIs(tokens.RBRACE) {
+		if !p.expectPeek(tokens.IDENT) {
+			return nil
+		}
+
+		if seen[p.curToken.Literal] {
+			p.errors = append(p.errors, ParserError{
+				Message:  fmt.Sprintf("duplicate variant %q in enum %s", p.curToken.Literal, stmt.Name.Value),
+				FilePath: p.filePath,
+				Token:    p.curToken,
+			})
+
+			return nil
+		}
+
+		seen[p.curToken.Literal] = true
+		variant := &ast.EnumVariant{
+			Name:   &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			Fields: []*ast.Identifier{},
+		}
+
+		if p.peekTokenIs(tokens.LPAREN) {
+			p.nextToken()
+
+			variant.Fields = p.parseEnumVariantFields()
+			if variant.Fields == nil {
+				return nil
+			}
+		}
+
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.peekTokenIs(tokens.RBRACE) && !p.expectPeek(tokens.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(tokens.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(tokens.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseEnumVariantFields() []*ast.Identifier {
+	fields := []*ast.Identifier{}
+
+	for {
+		if !p.expectPeek(tokens.IDENT) {
+			return nil
+		}
+
+		fields = append(fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(tokens.COMMA) {
+			break
+		}
+
+		p.nextToken()
+	}
+
+	if !p.expectPeek(tokens.RPAREN) {
+		return nil
+	}
+
+	return fields
+}
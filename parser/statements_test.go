@@ -68,6 +68,69 @@ func testVarStatement(t *testing.T, s ast.Statement, name string) bool {
 	return true
 }
 
+func TestVarDestructuringStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var [a, b] = pair;", "var [a, b] = pair;"},
+		{"var mut [a, [b, c]] = pair;", "var mut [a, [b, c]] = pair;"},
+		{"var { name, age } = user;", "var {name, age} = user;"},
+		{`var { "name": n, address: { city } } = user;`, `var {"name": n, "address": {city}} = user;`},
+		{"var [a, b = 2] = pair;", "var [a, b = 2] = pair;"},
+		{"var { name, age = 18, tags: [first] = [] } = user;", "var {name, age = 18, \"tags\": [first] = []} = user;"},
+	}
+
+	for _, tt := range tests {
+		t.Run("var statement: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("program.Statements does not contain 1 statements. got %d", len(program.Statements))
+			}
+
+			stmt, ok := program.Statements[0].(*ast.VariableStatement)
+			if !ok {
+				t.Fatalf("stmt not *ast.VariableStatement. got %T", program.Statements[0])
+			}
+
+			if stmt.Pattern == nil {
+				t.Fatalf("stmt.Pattern is nil")
+			}
+
+			actual := program.String()
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestVarDestructuringStatementParsingErrors(t *testing.T) {
+	tests := []string{
+		"var [1, b] = pair;",
+		"var { \"name\" } = user;",
+		"var [a, b];",
+		"var [a, b = ] = pair;",
+	}
+
+	for _, input := range tests {
+		t.Run("var statement: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid var statement, got none")
+			}
+		})
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -316,3 +379,201 @@ func TestContinueLoopStatement(t *testing.T) {
 		t.Fatalf("continueStmt.TokenLiteral is not 'continue', got %q", continueStmt.TokenLiteral())
 	}
 }
+
+func TestTryStatement(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedParameter string
+	}{
+		{"try { x } catch (err) { y }", "err"},
+		{"try { x } catch { y }", ""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l, nil)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+		}
+
+		tryStmt, ok := program.Statements[0].(*ast.TryStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.TryStatement. got %T", program.Statements[0])
+		}
+
+		if tt.expectedParameter == "" && tryStmt.Parameter != nil {
+			t.Errorf("tryStmt.Parameter is not nil. got %q", tryStmt.Parameter.Value)
+		}
+
+		if tt.expectedParameter != "" && !testIdentifier(t, tryStmt.Parameter, tt.expectedParameter) {
+			return
+		}
+
+		if tryStmt.Block.String() != "x" {
+			t.Errorf("tryStmt.Block is not 'x'. got %q", tryStmt.Block.String())
+		}
+
+		if tryStmt.Catch.String() != "y" {
+			t.Errorf("tryStmt.Catch is not 'y'. got %q", tryStmt.Catch.String())
+		}
+	}
+}
+
+func TestThrowStatement(t *testing.T) {
+	input := `throw "failed";`
+
+	l := lexer.New(input)
+	p := New(l, nil)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+	}
+
+	throwStmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ThrowStatement. got %T", program.Statements[0])
+	}
+
+	if throwStmt.TokenLiteral() != "throw" {
+		t.Fatalf("throwStmt.TokenLiteral is not 'throw', got %q", throwStmt.TokenLiteral())
+	}
+
+	if throwStmt.Value.String() != `"failed"` {
+		t.Errorf("throwStmt.Value is not '\"failed\"'. got %q", throwStmt.Value.String())
+	}
+}
+
+func TestStructStatement(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedName   string
+		expectedFields []string
+	}{
+		{"struct User { name, age }", "User", []string{"name", "age"}},
+		{"struct User { name, age, };", "User", []string{"name", "age"}},
+		{"struct Empty {}", "Empty", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run("struct statement: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+			}
+
+			structStmt, ok := program.Statements[0].(*ast.StructStatement)
+			if !ok {
+				t.Fatalf("program.Statements[0] is not ast.StructStatement. got %T", program.Statements[0])
+			}
+
+			if !testIdentifier(t, structStmt.Name, tt.expectedName) {
+				return
+			}
+
+			if len(structStmt.Fields) != len(tt.expectedFields) {
+				t.Fatalf("structStmt.Fields has wrong length. want %d, got %d", len(tt.expectedFields), len(structStmt.Fields))
+			}
+
+			for i, field := range tt.expectedFields {
+				testIdentifier(t, structStmt.Fields[i], field)
+			}
+		})
+	}
+}
+
+func TestStructStatementParsingErrors(t *testing.T) {
+	tests := []string{
+		"struct { name }",
+		"struct User name",
+		"struct User { name age }",
+		"struct User { \"name\" }",
+		"struct User { name, name }",
+	}
+
+	for _, input := range tests {
+		t.Run("struct statement: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid struct statement, got none")
+			}
+		})
+	}
+}
+
+func TestEnumStatement(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedName string
+		expected     string
+	}{
+		{"enum Status { Active, Disabled }", "Status", "enum Status { Active, Disabled }"},
+		{"enum Status { Active, Banned(reason), };", "Status", "enum Status { Active, Banned(reason) }"},
+		{"enum Shape { Circle(radius), Rect(width, height) }", "Shape", "enum Shape { Circle(radius), Rect(width, height) }"},
+		{"enum Empty {}", "Empty", "enum Empty {  }"},
+	}
+
+	for _, tt := range tests {
+		t.Run("enum statement: "+tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, nil)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("program.Statements does not contain 1 statement. got %d", len(program.Statements))
+			}
+
+			enumStmt, ok := program.Statements[0].(*ast.EnumStatement)
+			if !ok {
+				t.Fatalf("program.Statements[0] is not ast.EnumStatement. got %T", program.Statements[0])
+			}
+
+			if !testIdentifier(t, enumStmt.Name, tt.expectedName) {
+				return
+			}
+
+			if enumStmt.String() != tt.expected {
+				t.Errorf("expected=%q, got %q", tt.expected, enumStmt.String())
+			}
+		})
+	}
+}
+
+func TestEnumStatementParsingErrors(t *testing.T) {
+	tests := []string{
+		"enum { Active }",
+		"enum Status Active",
+		"enum Status { Active Disabled }",
+		"enum Status { Banned(reason }",
+		"enum Status { Banned() }",
+		"enum Status { Active, Active }",
+	}
+
+	for _, input := range tests {
+		t.Run("enum statement: "+input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l, nil)
+			p.ParseProgram()
+
+			if len(p.Errors()) == 0 {
+				t.Fatalf("expected parser errors for invalid enum statement, got none")
+			}
+		})
+	}
+}
@@ -20,8 +20,14 @@ const (
 	IDENT    TokenType = "IDENT"    // add, foobar, x, y, ...
 	INT      TokenType = "INT"      // 1343456
 	FLOAT    TokenType = "FLOAT"    // 3.14
+	DECIMAL  TokenType = "DECIMAL"  // 19.99d
 	STRING   TokenType = "STRING"   // "string"
 
+	// Interpolated string literals
+	TEMPLATE_START  TokenType = "TEMPLATE_START"  // "string ${
+	TEMPLATE_MIDDLE TokenType = "TEMPLATE_MIDDLE" // } string ${
+	TEMPLATE_END    TokenType = "TEMPLATE_END"    // } string"
+
 	// String literals
 	DOUBLE_QUOTE TokenType = "\"" // "string"
 	SINGLE_QUOTE TokenType = "'"  // 'string'
@@ -46,14 +52,33 @@ const (
 	AND    TokenType = "&&"
 	OR     TokenType = "||"
 
+	// Bitwise operators
+	BIT_AND     TokenType = "&"
+	BIT_OR      TokenType = "|"
+	TILDE       TokenType = "~"
+	SHIFT_LEFT  TokenType = "<<"
+	SHIFT_RIGHT TokenType = ">>"
+
+	// Conditional operators
+	QUESTION      TokenType = "?"
+	NULL_COALESCE TokenType = "??"
+
+	// Optional chaining operators
+	OPTIONAL_CHAIN TokenType = "?."
+	OPTIONAL_INDEX TokenType = "?["
+
 	// Increment/Decrement operators
 	INCREMENT TokenType = "++"
 	DECREMENT TokenType = "--"
 
 	// Delimiters
-	COMMA  TokenType = ","
-	COLON  TokenType = ":"
-	PERIOD TokenType = "."
+	COMMA    TokenType = ","
+	COLON    TokenType = ":"
+	PERIOD   TokenType = "."
+	ELLIPSIS TokenType = "..."
+	RANGE    TokenType = ".."
+	RANGE_EX TokenType = "..<"
+	ARROW    TokenType = "=>"
 
 	LPAREN   TokenType = "("
 	RPAREN   TokenType = ")"
@@ -72,11 +97,23 @@ const (
 	ELSE_IF       TokenType = "ELSE_IF"
 	RETURN        TokenType = "RETURN"
 	WHILE         TokenType = "WHILE"
+	FOR           TokenType = "FOR"
+	IN            TokenType = "IN"
 	IMPORT        TokenType = "IMPORT"
 	IMPORT_ALIAS  TokenType = "IMPORT_ALIAS"
 	EXPORT        TokenType = "EXPORT"
 	BREAK_LOOP    TokenType = "BREAK_LOOP"
 	CONTINUE_LOOP TokenType = "CONTINUE_LOOP"
+	TRY           TokenType = "TRY"
+	CATCH         TokenType = "CATCH"
+	THROW         TokenType = "THROW"
+	MATCH         TokenType = "MATCH"
+	STRUCT        TokenType = "STRUCT"
+	ENUM          TokenType = "ENUM"
+	YIELD         TokenType = "YIELD"
+	SPAWN         TokenType = "SPAWN"
+	ASYNC         TokenType = "ASYNC"
+	AWAIT         TokenType = "AWAIT"
 )
 
 var keywords = map[string]TokenType{
@@ -92,12 +129,24 @@ var keywords = map[string]TokenType{
 	"else if":  ELSE_IF,
 	"return":   RETURN,
 	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
 	"import":   IMPORT,
 	"export":   EXPORT,
 	"as":       IMPORT_ALIAS,
 	"AS":       IMPORT_ALIAS,
 	"break":    BREAK_LOOP,
 	"continue": CONTINUE_LOOP,
+	"try":      TRY,
+	"catch":    CATCH,
+	"throw":    THROW,
+	"match":    MATCH,
+	"struct":   STRUCT,
+	"enum":     ENUM,
+	"yield":    YIELD,
+	"spawn":    SPAWN,
+	"async":    ASYNC,
+	"await":    AWAIT,
 }
 
 func LookupIdent(ident string) TokenType {
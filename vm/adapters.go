@@ -19,25 +19,35 @@ func (ca *CompiledClosureAdapter) Inspect() string {
 }
 
 func (ca *CompiledClosureAdapter) Call(args ...objects.Object) objects.Object {
-	if len(args) != ca.Closure.Fn.NumParameters {
-		fnName := "<anonymous>"
-		if ca.Closure.Fn.Name != "" {
-			fnName = ca.Closure.Fn.Name
-		}
+	err := ca.Closure.Fn.ValidateArguments(len(args))
+	if err != nil {
+		return objects.NativeErrorToErrorObject(err)
+	}
+
+	if ca.Closure.Fn.Generator {
+		return ca.VM.newGenerator(ca.Closure, args)
+	}
 
-		return objects.NativeErrorToErrorObject(
-			objects.NewWrongNumberOfArgumentsError(fnName, ca.Closure.Fn.NumParameters, len(args)),
-		)
+	if ca.Closure.Fn.Async {
+		return ca.VM.newAsyncPromise(ca.Closure, args)
 	}
 
+	return ca.call(args)
+}
+
+func (ca *CompiledClosureAdapter) call(args []objects.Object) objects.Object {
 	funcVM := ca.VM.Copy()
+	funcVM.imports = ca.VM.imports
 
 	frame := NewFrame(ca.Closure, 0)
 	funcVM.pushFrame(frame)
 
 	copy(funcVM.stack, args)
+	funcVM.prepareArguments(ca.Closure.Fn, 0, len(args))
 	funcVM.sp = ca.Closure.Fn.NumLocals
 
+	defer funcVM.closeUpvalues(0)
+
 	for funcVM.currentFrame().ip < len(funcVM.currentFrame().Instructions())-1 {
 		funcVM.currentFrame().ip++
 
@@ -50,15 +60,17 @@ func (ca *CompiledClosureAdapter) Call(args ...objects.Object) objects.Object {
 
 		op := code.Opcode(ins[ip])
 
-		switch op {
-		case code.OpReturnValue:
+		// Only returns from the closure itself end the call, returns from any
+		// functions called by the closure are handled by the VM as usual.
+		switch {
+		case op == code.OpReturnValue && funcVM.framesIndex == 1:
 			return funcVM.pop()
-		case code.OpReturn:
+		case op == code.OpReturn && funcVM.framesIndex == 1:
 			return objects.NULL
 		}
 
 		err := funcVM.executeInstructions(op, ins, ip)
-		if err != nil {
+		if err != nil && !funcVM.catchError(err) {
 			return objects.NativeErrorToErrorObject(err)
 		}
 	}
@@ -0,0 +1,17 @@
+package vm
+
+type ErrorHandler struct {
+	catchPos    int
+	framesIndex int
+	sp          int
+	catching    bool
+}
+
+func NewErrorHandler(catchPos, framesIndex, sp int) ErrorHandler {
+	return ErrorHandler{
+		catchPos:    catchPos,
+		framesIndex: framesIndex,
+		sp:          sp,
+		catching:    false,
+	}
+}
@@ -44,7 +44,7 @@ func (s *StandardOut) Mute(fn func() objects.Object) objects.Object {
 func captureStdoutForBuiltin(
 	fn *objects.Builtin,
 	args []objects.Object,
-) objects.Object {
+) (objects.Object, error) {
 	var buf bytes.Buffer
 
 	originalStdout := os.Stdout
@@ -62,9 +62,5 @@ func captureStdoutForBuiltin(
 		Stdout.Write(output)
 	}
 
-	if err != nil {
-		return objects.NativeErrorToErrorObject(err)
-	}
-
-	return rs
+	return rs, err
 }
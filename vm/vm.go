@@ -2,19 +2,39 @@ package vm
 
 import (
 	"fmt"
-	"math"
+	"strings"
 
 	"github.com/senither/zen-lang/code"
 	"github.com/senither/zen-lang/compiler"
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/scheduler"
 )
 
 const (
-	MAX_FRAMES   = 1024
-	STACK_SIZE   = 2048
+	MAX_FRAMES = 1024
+	// STACK_SIZE leaves room for the closure, locals and operands of every
+	// frame, so deep recursion runs into the frame limit before the stack.
+	STACK_SIZE   = MAX_FRAMES * 8
 	GLOBALS_SIZE = 65536
 )
 
+var numberOperators = map[code.Opcode]string{
+	code.OpAdd: "+",
+	code.OpSub: "-",
+	code.OpMul: "*",
+	code.OpDiv: "/",
+	code.OpPow: "^",
+	code.OpMod: "%",
+}
+
+var bitwiseOperators = map[code.Opcode]string{
+	code.OpBitAnd:     "&",
+	code.OpBitOr:      "|",
+	code.OpBitXor:     "~",
+	code.OpShiftLeft:  "<<",
+	code.OpShiftRight: ">>",
+}
+
 type VMSettings struct {
 	CaptureStdout bool
 }
@@ -37,6 +57,12 @@ type VM struct {
 	frames      []*Frame
 	framesIndex int
 
+	// Upvalues that still point to a slot on the stack, these are closed
+	// once the frame that owns the slot returns.
+	openUpvalues []*objects.Upvalue
+
+	handlers []ErrorHandler
+
 	exports map[string]objects.Object
 	imports []ImportedFileContext
 
@@ -44,7 +70,7 @@ type VM struct {
 }
 
 func New(bytecode *compiler.Bytecode) *VM {
-	mainFn := &objects.CompiledFunction{OpcodeInstructions: bytecode.Instructions}
+	mainFn := &objects.CompiledFunction{OpcodeInstructions: bytecode.Instructions, NumLocals: bytecode.NumLocals}
 	mainClosure := &objects.Closure{Fn: mainFn}
 	mainFrame := NewFrame(mainClosure, 0)
 
@@ -59,7 +85,7 @@ func New(bytecode *compiler.Bytecode) *VM {
 		constants: bytecode.Constants,
 
 		stack: make([]objects.Object, STACK_SIZE),
-		sp:    0,
+		sp:    bytecode.NumLocals,
 
 		globals: make([]objects.Object, GLOBALS_SIZE),
 
@@ -113,10 +139,17 @@ func (vm *VM) EnableStdoutCapture() {
 }
 
 func (vm *VM) LastPoppedStackElem() objects.Object {
-	return vm.stack[max(vm.sp-1, 0)]
+	return vm.stack[max(vm.sp-1, vm.frames[0].closure.Fn.NumLocals)]
 }
 
 func (vm *VM) Run() error {
+	scheduler.Lock()
+	defer scheduler.Unlock()
+
+	return vm.run()
+}
+
+func (vm *VM) run() error {
 	var (
 		ip  int
 		ins code.Instructions
@@ -131,7 +164,7 @@ func (vm *VM) Run() error {
 		op = code.Opcode(ins[ip])
 
 		err := vm.executeInstructions(op, ins, ip)
-		if err != nil {
+		if err != nil && !vm.catchError(err) {
 			return err
 		}
 	}
@@ -175,6 +208,11 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 	case code.OpMinus:
 		return vm.executeMinusOperator()
 
+	case code.OpBitAnd, code.OpBitOr, code.OpBitXor, code.OpShiftLeft, code.OpShiftRight:
+		return vm.executeBitwiseOperation(op)
+	case code.OpBitNot:
+		return vm.executeBitwiseNotOperator()
+
 	case code.OpSetGlobal:
 		globalIndex := code.ReadUint16(ins[ip+1:])
 		vm.currentFrame().ip += 2
@@ -205,10 +243,37 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 
 		currentClosure := vm.currentFrame().closure
 
-		err := vm.push(currentClosure.Free[freeIndex])
+		err := vm.push(currentClosure.Free[freeIndex].Get())
 		if err != nil {
 			return err
 		}
+	case code.OpSetFree:
+		freeIndex := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		currentClosure := vm.currentFrame().closure
+		currentClosure.Free[freeIndex].Set(vm.pop())
+	case code.OpCaptureLocal:
+		localIndex := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		frame := vm.currentFrame()
+
+		return vm.push(vm.captureUpvalue(frame.basePointer + int(localIndex)))
+	case code.OpCaptureFree:
+		freeIndex := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		currentClosure := vm.currentFrame().closure
+
+		return vm.push(currentClosure.Free[freeIndex])
+	case code.OpCloseUpvalues:
+		localIndex := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		frame := vm.currentFrame()
+
+		vm.closeUpvalues(frame.basePointer + int(localIndex))
 	case code.OpCurrentClosure:
 		currentClosure := vm.currentFrame().closure
 		err := vm.push(currentClosure)
@@ -220,16 +285,12 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 		globalIndex := code.ReadUint16(ins[ip+1:])
 		vm.currentFrame().ip += 2
 
-		value := vm.globals[globalIndex]
-		numberValue := objects.UnwrapNumberValue(value)
-
-		if op == code.OpIncGlobal {
-			numberValue++
-		} else {
-			numberValue--
+		value, err := stepNumberValue(vm.globals[globalIndex], op == code.OpIncGlobal)
+		if err != nil {
+			return err
 		}
 
-		vm.globals[globalIndex] = objects.WrapNumberValue(numberValue, value, value)
+		vm.globals[globalIndex] = value
 		vm.push(vm.globals[globalIndex])
 	case code.OpIncLocal, code.OpDecLocal:
 		localIndex := code.ReadUint8(ins[ip+1:])
@@ -237,21 +298,32 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 
 		frame := vm.currentFrame()
 
-		value := vm.stack[frame.basePointer+int(localIndex)]
-		numberValue := objects.UnwrapNumberValue(value)
-
-		if op == code.OpIncLocal {
-			numberValue++
-		} else {
-			numberValue--
+		value, err := stepNumberValue(vm.stack[frame.basePointer+int(localIndex)], op == code.OpIncLocal)
+		if err != nil {
+			return err
 		}
 
-		vm.stack[frame.basePointer+int(localIndex)] = objects.WrapNumberValue(numberValue, value, value)
+		vm.stack[frame.basePointer+int(localIndex)] = value
 		vm.push(vm.stack[frame.basePointer+int(localIndex)])
+	case code.OpIncFree, code.OpDecFree:
+		freeIndex := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		upvalue := vm.currentFrame().closure.Free[freeIndex]
+
+		value, err := stepNumberValue(upvalue.Get(), op == code.OpIncFree)
+		if err != nil {
+			return err
+		}
+
+		upvalue.Set(value)
+		vm.push(upvalue.Get())
 
 	case code.OpJump:
 		pos := int(code.ReadUint16(ins[ip+1:]))
 		vm.currentFrame().ip = pos - 1
+
+		scheduler.Tick()
 	case code.OpJumpNotTruthy:
 		pos := int(code.ReadUint16(ins[ip+1:]))
 		vm.currentFrame().ip += 2
@@ -260,10 +332,43 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 		if !objects.IsTruthy(condition) {
 			vm.currentFrame().ip = pos - 1
 		}
+	case code.OpJumpNotNull:
+		pos := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		// Non-null values are left on the stack as the result of
+		// the expression, null values are replaced by the right side.
+		value := vm.pop()
+		if value.Type() != objects.NULL_OBJ {
+			vm.currentFrame().ip = pos - 1
+
+			return vm.push(value)
+		}
+	case code.OpJumpNull:
+		pos := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		// The value is left on the stack either way, if it's null it
+		// becomes the result of the optional chain we're jumping out of.
+		if vm.stack[vm.sp-1].Type() == objects.NULL_OBJ {
+			vm.currentFrame().ip = pos - 1
+		}
 	case code.OpLoopEnd:
 		// Nothing needs to happen here, this is simply a marker for
 		// the end of loops that Jump operands are able to point
 		// to, so we don't pop the result off the stack.
+	case code.OpIterator:
+		iterator, err := objects.NewIterator(vm.pop())
+		if err != nil {
+			return err
+		}
+
+		return vm.push(iterator)
+	case code.OpIterNext:
+		pos := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		return vm.executeIteratorNext(pos)
 
 	case code.OpIndex:
 		index := vm.pop()
@@ -280,7 +385,11 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 		numElements := int(code.ReadUint16(ins[ip+1:]))
 		vm.currentFrame().ip += 2
 
-		array := vm.buildArray(vm.sp-numElements, vm.sp)
+		array, err := vm.buildArray(vm.sp-numElements, vm.sp)
+		if err != nil {
+			return err
+		}
+
 		vm.sp -= numElements
 
 		return vm.push(array)
@@ -297,6 +406,74 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 
 		return vm.push(hash)
 
+	case code.OpConcat:
+		numParts := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		str := vm.buildConcatenatedString(vm.sp-numParts, vm.sp)
+		vm.sp -= numParts
+
+		return vm.push(str)
+	case code.OpSpread:
+		return vm.push(&objects.Spread{Value: vm.pop()})
+	case code.OpRange:
+		inclusive := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		end := vm.pop()
+		start := vm.pop()
+
+		rng, err := objects.NewRange(start, end, inclusive == 1)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(rng)
+	case code.OpSlice:
+		end := vm.pop()
+		start := vm.pop()
+		left := vm.pop()
+
+		slice, err := objects.Slice(left, start, end)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(slice)
+
+	case code.OpStruct:
+		nameIndex := code.ReadUint16(ins[ip+1:])
+		numFields := int(code.ReadUint8(ins[ip+3:]))
+		vm.currentFrame().ip += 3
+
+		structObj := vm.buildStruct(vm.constants[nameIndex], vm.sp-numFields, vm.sp)
+		vm.sp -= numFields
+
+		return vm.push(structObj)
+	case code.OpMethod:
+		nameIndex := code.ReadUint16(ins[ip+1:])
+		vm.currentFrame().ip += 2
+
+		method := vm.pop()
+		structObj := vm.pop()
+
+		return vm.executeMethodDefinition(structObj, vm.constants[nameIndex], method)
+	case code.OpGetField:
+		nameIndex := code.ReadUint16(ins[ip+1:])
+		vm.currentFrame().ip += 2
+
+		left := vm.pop()
+
+		return vm.executeFieldAccess(left, vm.constants[nameIndex])
+	case code.OpSetField:
+		nameIndex := code.ReadUint16(ins[ip+1:])
+		vm.currentFrame().ip += 2
+
+		value := vm.pop()
+		left := vm.pop()
+
+		return vm.executeFieldAssignment(left, vm.constants[nameIndex], value)
+
 	case code.OpNull:
 		return vm.push(objects.NULL)
 
@@ -305,18 +482,88 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 		vm.currentFrame().ip += 1
 
 		return vm.executeCall(int(numArgs))
+	case code.OpTailCall:
+		numArgs := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		return vm.executeTailCall(int(numArgs))
 	case code.OpReturnValue:
 		returnValue := vm.pop()
 
+		vm.removeFrameErrorHandlers()
 		frame := vm.popFrame()
 		vm.sp = frame.basePointer - 1
 
 		return vm.push(returnValue)
 	case code.OpReturn:
+		vm.removeFrameErrorHandlers()
 		frame := vm.popFrame()
 		vm.sp = frame.basePointer - 1
 
 		return vm.push(objects.NULL)
+	case code.OpYield:
+		return fmt.Errorf("yield statement not within a generator function")
+	case code.OpSpawn:
+		numArgs := code.ReadUint8(ins[ip+1:])
+		vm.currentFrame().ip += 1
+
+		return vm.executeSpawn(int(numArgs))
+	case code.OpAwait:
+		result := objects.Await(vm.pop())
+		if err, ok := result.(*objects.Error); ok {
+			return fmt.Errorf("%s", err.Message)
+		}
+
+		return vm.push(result)
+
+	// Exceptions
+	case code.OpTry:
+		pos := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		vm.handlers = append(vm.handlers, NewErrorHandler(pos, vm.framesIndex, vm.sp))
+	case code.OpEndTry:
+		vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	case code.OpThrow:
+		return &objects.ThrownError{Value: vm.pop()}
+
+	case code.OpMatchArray:
+		length := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		array, ok := vm.pop().(*objects.Array)
+
+		return vm.push(objects.NativeBoolToBooleanObject(ok && len(array.Elements) == length))
+	case code.OpMatchHash:
+		numKeys := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		keys := vm.stack[vm.sp-numKeys : vm.sp]
+		vm.sp = vm.sp - numKeys
+
+		return vm.push(objects.NativeBoolToBooleanObject(vm.executeHashHasKeys(vm.pop(), keys)))
+	case code.OpMatchVariant:
+		numValues := int(code.ReadUint16(ins[ip+1:]))
+		vm.currentFrame().ip += 2
+
+		pattern := vm.pop()
+		matched, err := vm.executeMatchVariant(vm.pop(), pattern, numValues)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(objects.NativeBoolToBooleanObject(matched))
+	case code.OpDestructureArray:
+		value := vm.pop()
+		if value.Type() != objects.ARRAY_OBJ {
+			return fmt.Errorf("cannot destructure %s as an array", value.Type())
+		}
+	case code.OpDestructureHash:
+		value := vm.pop()
+		if value.Type() != objects.HASH_OBJ && value.Type() != objects.IMMUTABLE_HASH_OBJ {
+			return fmt.Errorf("cannot destructure %s as a hash", value.Type())
+		}
+
 	case code.OpGetBuiltin:
 		builtinIndex := code.ReadUint8(ins[ip+1:])
 		vm.currentFrame().ip += 1
@@ -351,6 +598,36 @@ func (vm *VM) executeInstructions(op code.Opcode, ins code.Instructions, ip int)
 	return nil
 }
 
+// catchError unwinds the stack and frames to the closest error handler that
+// isn't already handling an error and jumps to its catch block, returning
+// false if there are no handlers left that can catch the error.
+func (vm *VM) catchError(err error) bool {
+	for i := len(vm.handlers) - 1; i >= 0; i-- {
+		handler := vm.handlers[i]
+		if handler.catching {
+			continue
+		}
+
+		vm.handlers = vm.handlers[:i+1]
+		vm.handlers[i].catching = true
+
+		vm.framesIndex = handler.framesIndex
+		vm.sp = handler.sp
+		vm.closeUpvalues(handler.sp)
+		vm.currentFrame().ip = handler.catchPos - 1
+
+		return vm.push(objects.ErrorToHash(objects.NativeErrorToErrorObject(err))) == nil
+	}
+
+	return false
+}
+
+func (vm *VM) removeFrameErrorHandlers() {
+	for len(vm.handlers) > 0 && vm.handlers[len(vm.handlers)-1].framesIndex >= vm.framesIndex {
+		vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	}
+}
+
 func (vm *VM) push(obj objects.Object) error {
 	if vm.sp >= STACK_SIZE {
 		return fmt.Errorf("stack overflow")
@@ -369,9 +646,17 @@ func (vm *VM) pushClosure(constIndex int, numFree int) error {
 		return fmt.Errorf("not a function: %T", constant)
 	}
 
-	free := make([]objects.Object, numFree)
+	// Captured variables are pushed as upvalues, any other value (such as the
+	// enclosing closure itself) can never be reassigned so it's closed over.
+	free := make([]*objects.Upvalue, numFree)
 	for i := 0; i < numFree; i++ {
-		free[i] = vm.stack[vm.sp-numFree+i]
+		value := vm.stack[vm.sp-numFree+i]
+
+		if upvalue, ok := value.(*objects.Upvalue); ok {
+			free[i] = upvalue
+		} else {
+			free[i] = objects.NewClosedUpvalue(value)
+		}
 	}
 
 	vm.sp = vm.sp - numFree
@@ -403,7 +688,46 @@ func (vm *VM) pushFrame(f *Frame) {
 
 func (vm *VM) popFrame() *Frame {
 	vm.framesIndex--
-	return vm.frames[vm.framesIndex]
+
+	frame := vm.frames[vm.framesIndex]
+	vm.closeUpvalues(frame.basePointer)
+
+	return frame
+}
+
+// captureUpvalue returns the open upvalue for the stack slot, reusing the
+// existing upvalue if the slot has already been captured by another closure.
+func (vm *VM) captureUpvalue(slot int) *objects.Upvalue {
+	for _, upvalue := range vm.openUpvalues {
+		if upvalue.Slot == slot {
+			return upvalue
+		}
+	}
+
+	upvalue := objects.NewOpenUpvalue(slot, &vm.stack[slot])
+	vm.openUpvalues = append(vm.openUpvalues, upvalue)
+
+	return upvalue
+}
+
+// closeUpvalues closes all the open upvalues pointing to the stack slot
+// or any slot above it, moving their values off the stack.
+func (vm *VM) closeUpvalues(slot int) {
+	if len(vm.openUpvalues) == 0 {
+		return
+	}
+
+	open := vm.openUpvalues[:0]
+	for _, upvalue := range vm.openUpvalues {
+		if upvalue.Slot >= slot {
+			upvalue.Close()
+			continue
+		}
+
+		open = append(open, upvalue)
+	}
+
+	vm.openUpvalues = open
 }
 
 func (vm *VM) executeBinaryOperation(op code.Opcode) error {
@@ -427,30 +751,27 @@ func (vm *VM) executeBinaryOperation(op code.Opcode) error {
 }
 
 func (vm *VM) executeBinaryNumberOperation(op code.Opcode, left, right objects.Object) error {
-	leftValue := objects.UnwrapNumberValue(left)
-	rightValue := objects.UnwrapNumberValue(right)
+	operator, ok := numberOperators[op]
+	if !ok {
+		return fmt.Errorf("unknown number operator: %d", op)
+	}
 
-	var result float64
+	result, err := objects.ApplyNumberOperator(operator, left, right)
+	if err != nil {
+		return err
+	}
 
-	switch op {
-	case code.OpAdd:
-		result = leftValue + rightValue
-	case code.OpSub:
-		result = leftValue - rightValue
-	case code.OpMul:
-		result = leftValue * rightValue
-	case code.OpDiv:
-		result = leftValue / rightValue
-	case code.OpPow:
-		result = math.Pow(leftValue, rightValue)
-	case code.OpMod:
-		result = math.Mod(leftValue, rightValue)
+	return vm.push(result)
+}
 
-	default:
-		return fmt.Errorf("unknown number operator: %d", op)
+// stepNumberValue increments or decrements the number by one, used by the
+// increment and decrement operators.
+func stepNumberValue(value objects.Object, increment bool) (objects.Object, error) {
+	if increment {
+		return objects.ApplyNumberOperator("+", value, &objects.Integer{Value: 1})
 	}
 
-	return vm.push(objects.WrapNumberValue(result, left, right))
+	return objects.ApplyNumberOperator("-", value, &objects.Integer{Value: 1})
 }
 
 func (vm *VM) executeBinaryStringOperation(op code.Opcode, left, right objects.Object) error {
@@ -513,18 +834,17 @@ func (vm *VM) executeComparison(op code.Opcode) error {
 }
 
 func (vm *VM) executeComparisonNumberOperation(op code.Opcode, left, right objects.Object) error {
-	leftValue := objects.UnwrapNumberValue(left)
-	rightValue := objects.UnwrapNumberValue(right)
+	cmp, ordered := objects.CompareNumbers(left, right)
 
 	switch op {
 	case code.OpEqual:
-		return vm.push(objects.NativeBoolToBooleanObject(leftValue == rightValue))
+		return vm.push(objects.NativeBoolToBooleanObject(ordered && cmp == 0))
 	case code.OpNotEqual:
-		return vm.push(objects.NativeBoolToBooleanObject(leftValue != rightValue))
+		return vm.push(objects.NativeBoolToBooleanObject(!ordered || cmp != 0))
 	case code.OpGreaterThan:
-		return vm.push(objects.NativeBoolToBooleanObject(leftValue > rightValue))
+		return vm.push(objects.NativeBoolToBooleanObject(ordered && cmp > 0))
 	case code.OpGreaterThanOrEqual:
-		return vm.push(objects.NativeBoolToBooleanObject(leftValue >= rightValue))
+		return vm.push(objects.NativeBoolToBooleanObject(ordered && cmp >= 0))
 	default:
 		return fmt.Errorf("unknown number operator: %d", op)
 	}
@@ -553,24 +873,218 @@ func (vm *VM) executeMinusOperator() error {
 		return fmt.Errorf("unknown operator: -%s", operand.Type())
 	}
 
-	return vm.push(objects.WrapNumberValue(-objects.UnwrapNumberValue(operand), operand, operand))
+	return vm.push(objects.NegateNumber(operand))
+}
+
+func (vm *VM) executeBitwiseOperation(op code.Opcode) error {
+	right := vm.pop()
+	left := vm.pop()
+
+	if !objects.IsInteger(left) || !objects.IsInteger(right) {
+		return fmt.Errorf(
+			"unknown operator: %s %s %s",
+			left.Type(), bitwiseOperators[op], right.Type(),
+		)
+	}
+
+	result, err := objects.ApplyBitwiseOperator(bitwiseOperators[op], left, right)
+	if err != nil {
+		return err
+	}
+
+	return vm.push(result)
+}
+
+func (vm *VM) executeBitwiseNotOperator() error {
+	operand := vm.pop()
+
+	if !objects.IsInteger(operand) {
+		return fmt.Errorf("unknown operator: ~%s", operand.Type())
+	}
+
+	return vm.push(objects.BitwiseNot(operand))
+}
+
+func (vm *VM) buildConcatenatedString(startIndex, endIndex int) objects.Object {
+	var out strings.Builder
+
+	for i := startIndex; i < endIndex; i++ {
+		out.WriteString(objects.StringifyObject(vm.stack[i]))
+	}
+
+	return &objects.String{Value: out.String()}
+}
+
+func (vm *VM) executeIteratorNext(endPos int) error {
+	iterator, ok := vm.pop().(*objects.Iterator)
+	if !ok {
+		return fmt.Errorf("expected iterator on the stack")
+	}
+
+	key, value, ok := iterator.Next()
+	if !ok {
+		vm.currentFrame().ip = endPos - 1
+
+		return iterator.Err()
+	}
+
+	err := vm.push(key)
+	if err != nil {
+		return err
+	}
+
+	return vm.push(value)
 }
 
 func (vm *VM) executeIndexExpression(left, index objects.Object) error {
 	switch {
 	case left.Type() == objects.ARRAY_OBJ && index.Type() == objects.INTEGER_OBJ:
 		return vm.executeArrayIndex(left, index)
+	case left.Type() == objects.STRING_OBJ && index.Type() == objects.INTEGER_OBJ:
+		return vm.executeStringIndex(left, index)
 	case left.Type() == objects.HASH_OBJ:
 		return vm.executeHashIndex(left, index)
 	case left.Type() == objects.IMMUTABLE_HASH_OBJ:
 		hash := left.(*objects.ImmutableHash)
 		return vm.executeHashIndex(&hash.Value, index)
+	case left.Type() == objects.STRUCT_INSTANCE_OBJ && index.Type() == objects.STRING_OBJ:
+		return vm.executeFieldAccess(left, index)
+	case left.Type() == objects.GENERATOR_OBJ && index.Type() == objects.STRING_OBJ:
+		method, err := left.(*objects.Generator).Get(index.(*objects.String).Value)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(method)
+	case left.Type() == objects.TASK_OBJ && index.Type() == objects.STRING_OBJ:
+		method, err := left.(*objects.Task).Get(index.(*objects.String).Value)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(method)
+	case left.Type() == objects.PROMISE_OBJ && index.Type() == objects.STRING_OBJ:
+		method, err := left.(*objects.Promise).Get(index.(*objects.String).Value)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(method)
+	case left.Type() == objects.ENUM_OBJ && index.Type() == objects.STRING_OBJ:
+		return vm.executeEnumVariantAccess(left, index)
+	case left.Type() == objects.ENUM_VARIANT_OBJ:
+		return vm.executeEnumValueAccess(left, index)
 
 	default:
 		return fmt.Errorf("index operator not supported: %s", left.Type())
 	}
 }
 
+func (vm *VM) executeEnumVariantAccess(enum, name objects.Object) error {
+	variant, err := enum.(*objects.Enum).Variant(name.(*objects.String).Value)
+	if err != nil {
+		return err
+	}
+
+	return vm.push(variant)
+}
+
+// executeEnumValueAccess looks up the values held by an enum variant, either
+// by the name of the field or by the position it was declared at.
+func (vm *VM) executeEnumValueAccess(variant, index objects.Object) error {
+	variantObj := variant.(*objects.EnumVariant)
+
+	switch index := index.(type) {
+	case *objects.String:
+		value, err := variantObj.Get(index.Value)
+		if err != nil {
+			return err
+		}
+
+		return vm.push(value)
+	case *objects.Integer:
+		if index.Value < 0 || index.Value >= int64(len(variantObj.Values)) {
+			return vm.push(objects.NULL)
+		}
+
+		return vm.push(variantObj.Values[index.Value])
+
+	default:
+		return fmt.Errorf("index operator not supported: %s", variant.Type())
+	}
+}
+
+// executeMatchVariant tests if the subject is the same variant as the one in
+// the pattern, if the pattern has any values to match against, the subject
+// must also hold the same number of values.
+func (vm *VM) executeMatchVariant(subject, pattern objects.Object, numValues int) (bool, error) {
+	patternVariant, ok := pattern.(*objects.EnumVariant)
+	if !ok {
+		return false, fmt.Errorf("cannot match against non-variant type: %s", pattern.Type())
+	}
+
+	variant, ok := subject.(*objects.EnumVariant)
+	if !ok || variant.Enum != patternVariant.Enum || variant.Name != patternVariant.Name {
+		return false, nil
+	}
+
+	return numValues == 0 || numValues == len(variant.Values), nil
+}
+
+func (vm *VM) buildStruct(name objects.Object, startIndex, endIndex int) *objects.Struct {
+	fields := make([]string, 0, endIndex-startIndex)
+	for _, field := range vm.stack[startIndex:endIndex] {
+		fields = append(fields, field.(*objects.String).Value)
+	}
+
+	return &objects.Struct{
+		Name:    name.(*objects.String).Value,
+		Fields:  fields,
+		Methods: map[string]objects.Object{},
+	}
+}
+
+func (vm *VM) executeMethodDefinition(structObj, name, method objects.Object) error {
+	target, ok := structObj.(*objects.Struct)
+	if !ok {
+		return fmt.Errorf("cannot define method on non-struct type: %s", structObj.Type())
+	}
+
+	methodName := name.(*objects.String).Value
+	if target.HasField(methodName) {
+		return fmt.Errorf("method %s conflicts with a field of struct %s", methodName, target.Name)
+	}
+
+	target.Methods[methodName] = method
+
+	return nil
+}
+
+// executeFieldAccess looks up the field or method with the given name on a
+// struct instance, any other values are looked up like a regular index.
+func (vm *VM) executeFieldAccess(left, name objects.Object) error {
+	instance, ok := left.(*objects.StructInstance)
+	if !ok {
+		return vm.executeIndexExpression(left, name)
+	}
+
+	value, err := instance.Get(name.(*objects.String).Value)
+	if err != nil {
+		return err
+	}
+
+	return vm.push(value)
+}
+
+func (vm *VM) executeFieldAssignment(left, name, value objects.Object) error {
+	instance, ok := left.(*objects.StructInstance)
+	if !ok {
+		return vm.executeIndexAssignment(left, name, value)
+	}
+
+	return instance.Set(name.(*objects.String).Value, value)
+}
+
 func (vm *VM) executeArrayIndex(array, index objects.Object) error {
 	arrayObj := array.(*objects.Array)
 	idx := index.(*objects.Integer).Value
@@ -587,6 +1101,15 @@ func (vm *VM) executeArrayIndex(array, index objects.Object) error {
 	return vm.push(arrayObj.Elements[idx])
 }
 
+func (vm *VM) executeStringIndex(str, index objects.Object) error {
+	char, ok := objects.StringIndex(str.(*objects.String), index.(*objects.Integer).Value)
+	if !ok {
+		return vm.push(objects.NULL)
+	}
+
+	return vm.push(char)
+}
+
 func (vm *VM) executeHashIndex(hash, index objects.Object) error {
 	hashObj := hash.(*objects.Hash)
 
@@ -603,6 +1126,31 @@ func (vm *VM) executeHashIndex(hash, index objects.Object) error {
 	return vm.push(pair.Value)
 }
 
+func (vm *VM) executeHashHasKeys(obj objects.Object, keys []objects.Object) bool {
+	var hash *objects.Hash
+	switch obj := obj.(type) {
+	case *objects.Hash:
+		hash = obj
+	case *objects.ImmutableHash:
+		hash = &obj.Value
+	default:
+		return false
+	}
+
+	for _, key := range keys {
+		hashable, ok := key.(objects.Hashable)
+		if !ok {
+			return false
+		}
+
+		if _, ok := hash.Pairs[hashable.HashKey()]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (vm *VM) executeIndexAssignment(left, index, value objects.Object) error {
 	switch obj := left.(type) {
 	case *objects.Array:
@@ -611,11 +1159,16 @@ func (vm *VM) executeIndexAssignment(left, index, value objects.Object) error {
 			return fmt.Errorf("index operator not supported: %T", index)
 		}
 
-		if idx.Value < 0 || idx.Value >= int64(len(obj.Elements)) {
+		position := idx.Value
+		if position < 0 {
+			position += int64(len(obj.Elements))
+		}
+
+		if position < 0 || position >= int64(len(obj.Elements)) {
 			return fmt.Errorf("array index out of bounds: %d", idx.Value)
 		}
 
-		obj.Elements[idx.Value] = value
+		obj.Elements[position] = value
 	case *objects.Hash:
 		key, ok := index.(objects.Hashable)
 		if !ok {
@@ -623,6 +1176,13 @@ func (vm *VM) executeIndexAssignment(left, index, value objects.Object) error {
 		}
 
 		obj.Pairs[key.HashKey()] = objects.HashPair{Key: index, Value: value}
+	case *objects.StructInstance:
+		field, ok := index.(*objects.String)
+		if !ok {
+			return fmt.Errorf("index operator not supported: %T", index)
+		}
+
+		return obj.Set(field.Value, value)
 
 	default:
 		return fmt.Errorf("index assignment not supported: %T", left)
@@ -631,14 +1191,39 @@ func (vm *VM) executeIndexAssignment(left, index, value objects.Object) error {
 	return nil
 }
 
-func (vm *VM) buildArray(startIndex, endIndex int) objects.Object {
-	elements := make([]objects.Object, endIndex-startIndex)
+func (vm *VM) buildArray(startIndex, endIndex int) (objects.Object, error) {
+	elements, err := vm.expandSpreads(vm.stack[startIndex:endIndex])
+	if err != nil {
+		return nil, err
+	}
 
-	for i := startIndex; i < endIndex; i++ {
-		elements[i-startIndex] = vm.stack[i]
+	return &objects.Array{Elements: elements}, nil
+}
+
+// expandSpreads returns a copy of the given objects where the elements of any
+// spread arrays have been expanded into the list in place of the spread.
+func (vm *VM) expandSpreads(objs []objects.Object) ([]objects.Object, error) {
+	elements := make([]objects.Object, 0, len(objs))
+
+	for _, obj := range objs {
+		spread, ok := obj.(*objects.Spread)
+		if !ok {
+			elements = append(elements, obj)
+			continue
+		}
+
+		switch value := spread.Value.(type) {
+		case *objects.Array:
+			elements = append(elements, value.Elements...)
+		case *objects.Range:
+			elements = append(elements, value.Elements()...)
+
+		default:
+			return nil, fmt.Errorf("cannot spread non-array value: %s", spread.Value.Type())
+		}
 	}
 
-	return &objects.Array{Elements: elements}
+	return elements, nil
 }
 
 func (vm *VM) buildHash(startIndex, endIndex int) (objects.Object, error) {
@@ -648,6 +1233,24 @@ func (vm *VM) buildHash(startIndex, endIndex int) (objects.Object, error) {
 		key := vm.stack[i]
 		value := vm.stack[i+1]
 
+		if spread, ok := key.(*objects.Spread); ok {
+			var hash *objects.Hash
+			switch spreadValue := spread.Value.(type) {
+			case *objects.Hash:
+				hash = spreadValue
+			case *objects.ImmutableHash:
+				hash = &spreadValue.Value
+			default:
+				return nil, fmt.Errorf("cannot spread non-hash value: %s", spread.Value.Type())
+			}
+
+			for hashKey, pair := range hash.Pairs {
+				pairs[hashKey] = pair
+			}
+
+			continue
+		}
+
 		pair := objects.HashPair{Key: key, Value: value}
 
 		hashable, ok := key.(objects.Hashable)
@@ -662,6 +1265,11 @@ func (vm *VM) buildHash(startIndex, endIndex int) (objects.Object, error) {
 }
 
 func (vm *VM) executeCall(numArgs int) error {
+	numArgs, err := vm.spreadArguments(numArgs)
+	if err != nil {
+		return err
+	}
+
 	callee := vm.stack[vm.sp-1-numArgs]
 
 	switch callee := callee.(type) {
@@ -671,38 +1279,331 @@ func (vm *VM) executeCall(numArgs int) error {
 		return vm.callImportedClosure(callee, numArgs)
 	case *objects.Builtin:
 		return vm.callBuiltin(callee, numArgs)
+	case *objects.BoundMethod:
+		return vm.callBoundMethod(callee, numArgs)
+	case *objects.Struct:
+		return vm.callStruct(callee, numArgs)
+	case *objects.EnumVariant:
+		return vm.callEnumVariant(callee, numArgs)
 
 	default:
 		return fmt.Errorf("calling non-function and non-builtin")
 	}
 }
 
+// executeTailCall calls the closure on the stack by reusing the current frame,
+// so calls in tail position don't grow the number of frames. Any other callee
+// is called as usual, and returned by the instruction following the call.
+func (vm *VM) executeTailCall(numArgs int) error {
+	numArgs, err := vm.spreadArguments(numArgs)
+	if err != nil {
+		return err
+	}
+
+	frame := vm.currentFrame()
+
+	// Frames created by adapters, generators and tasks start at the bottom of
+	// their own stack, so there's no callee slot below the frame to reuse.
+	cl, ok := vm.stack[vm.sp-1-numArgs].(*objects.Closure)
+	if !ok || cl.Fn.Generator || cl.Fn.Async || frame.closure.Fn.Generator || frame.basePointer == 0 {
+		return vm.executeCall(numArgs)
+	}
+
+	err = cl.Fn.ValidateArguments(numArgs)
+	if err != nil {
+		return err
+	}
+
+	if frame.basePointer+cl.Fn.NumLocals >= STACK_SIZE {
+		return fmt.Errorf("stack overflow")
+	}
+
+	// The locals of the current frame are about to be overwritten, so any
+	// closures that captured them are given their own copy of the values.
+	vm.removeFrameErrorHandlers()
+	vm.closeUpvalues(frame.basePointer)
+
+	copy(vm.stack[frame.basePointer-1:], vm.stack[vm.sp-1-numArgs:vm.sp])
+
+	frame.closure = cl
+	frame.ip = -1
+
+	vm.prepareArguments(cl.Fn, frame.basePointer, numArgs)
+	vm.sp = frame.basePointer + cl.Fn.NumLocals
+
+	return nil
+}
+
+// executeSpawn calls the function on the stack in a task of its own, the
+// function runs in a copy of the VM, so it only shares the globals.
+func (vm *VM) executeSpawn(numArgs int) error {
+	numArgs, err := vm.spreadArguments(numArgs)
+	if err != nil {
+		return err
+	}
+
+	callee := vm.stack[vm.sp-1-numArgs]
+
+	args := make([]objects.Object, numArgs)
+	copy(args, vm.stack[vm.sp-numArgs:vm.sp])
+	vm.sp = vm.sp - numArgs - 1
+
+	var task *objects.Task
+
+	switch callee := callee.(type) {
+	case *objects.Closure:
+		err := callee.Fn.ValidateArguments(numArgs)
+		if err != nil {
+			return err
+		}
+
+		adapter := &CompiledClosureAdapter{Closure: callee, VM: vm}
+		task = objects.NewTask(func() objects.Object {
+			return adapter.Call(args...)
+		})
+	case *objects.BoundMethod:
+		cl, ok := callee.Method.(*objects.Closure)
+		if !ok {
+			return fmt.Errorf("cannot spawn non-function: %s", callee.Method.Type())
+		}
+
+		err := callee.ValidateArguments(numArgs)
+		if err != nil {
+			return err
+		}
+
+		adapter := &CompiledClosureAdapter{Closure: cl, VM: vm}
+		args = append([]objects.Object{callee.Receiver}, args...)
+		task = objects.NewTask(func() objects.Object {
+			return adapter.Call(args...)
+		})
+	case *objects.Builtin:
+		for i, arg := range args {
+			args[i] = WrapClosuresIfNeeded(vm, arg)
+		}
+
+		task = objects.NewTask(func() objects.Object {
+			result, err := callee.Fn(args...)
+			if err != nil {
+				return objects.NativeErrorToErrorObject(err)
+			}
+
+			return result
+		})
+
+	default:
+		return fmt.Errorf("cannot spawn non-function: %s", callee.Type())
+	}
+
+	return vm.push(task)
+}
+
+// spreadArguments expands any spread arrays given as arguments to a function
+// call in place on the stack, returning the new number of arguments.
+func (vm *VM) spreadArguments(numArgs int) (int, error) {
+	startIndex := vm.sp - numArgs
+
+	hasSpread := false
+	for _, arg := range vm.stack[startIndex:vm.sp] {
+		if arg.Type() == objects.SPREAD_OBJ {
+			hasSpread = true
+			break
+		}
+	}
+
+	if !hasSpread {
+		return numArgs, nil
+	}
+
+	args, err := vm.expandSpreads(vm.stack[startIndex:vm.sp])
+	if err != nil {
+		return 0, err
+	}
+
+	if startIndex+len(args) >= STACK_SIZE {
+		return 0, fmt.Errorf("stack overflow")
+	}
+
+	copy(vm.stack[startIndex:], args)
+	vm.sp = startIndex + len(args)
+
+	return len(args), nil
+}
+
+// prepareArguments sets the parameters that weren't given an argument to null,
+// and collects any extra arguments into an array for the rest parameter.
+func (vm *VM) prepareArguments(fn *objects.CompiledFunction, basePointer, numArgs int) {
+	if fn.Variadic {
+		rest := []objects.Object{}
+		if numArgs > fn.NumParameters {
+			rest = append(rest, vm.stack[basePointer+fn.NumParameters:basePointer+numArgs]...)
+		}
+
+		vm.stack[basePointer+fn.NumParameters] = &objects.Array{Elements: rest}
+	}
+
+	for i := numArgs; i < fn.NumParameters; i++ {
+		vm.stack[basePointer+i] = objects.NULL
+	}
+}
+
 func (vm *VM) callClosure(cl *objects.Closure, numArgs int) error {
-	name := "<anonymous>"
-	if cl.Fn.Name != "" {
-		name = cl.Fn.Name
+	err := cl.Fn.ValidateArguments(numArgs)
+	if err != nil {
+		return err
 	}
 
-	if numArgs != cl.Fn.NumParameters {
-		return objects.NewWrongNumberOfArgumentsError(name, cl.Fn.NumParameters, numArgs)
+	return vm.enterClosure(cl, numArgs)
+}
+
+func (vm *VM) enterClosure(cl *objects.Closure, numArgs int) error {
+	if cl.Fn.Generator {
+		generator := vm.newGenerator(cl, vm.stack[vm.sp-numArgs:vm.sp])
+		vm.sp = vm.sp - numArgs - 1
+
+		return vm.push(generator)
+	}
+
+	if cl.Fn.Async {
+		promise := vm.newAsyncPromise(cl, vm.stack[vm.sp-numArgs:vm.sp])
+		vm.sp = vm.sp - numArgs - 1
+
+		return vm.push(promise)
+	}
+
+	// Both the frames and the stack have a fixed size, so calls that would
+	// run out of either are stopped before the frame is pushed.
+	if vm.framesIndex >= MAX_FRAMES {
+		return fmt.Errorf("maximum call depth exceeded")
+	}
+
+	if vm.sp-numArgs+cl.Fn.NumLocals >= STACK_SIZE {
+		return fmt.Errorf("stack overflow")
 	}
 
 	frame := NewFrame(cl, vm.sp-numArgs)
 	vm.pushFrame(frame)
 
+	vm.prepareArguments(cl.Fn, frame.basePointer, numArgs)
+
 	vm.sp = frame.basePointer + cl.Fn.NumLocals
 
 	return nil
 }
 
-func (vm *VM) callImportedClosure(icl *objects.ImportedClosure, numArgs int) error {
-	name := "<anonymous>"
-	if icl.Closure.Fn.Name != "" {
-		name = icl.Closure.Fn.Name
+// newGenerator creates a generator that runs the closure in a VM of its own,
+// so its frame and stack are kept intact while the generator is suspended.
+func (vm *VM) newGenerator(cl *objects.Closure, args []objects.Object) *objects.Generator {
+	genVM := vm.Copy()
+	genVM.imports = vm.imports
+
+	frame := NewFrame(cl, 0)
+	genVM.pushFrame(frame)
+
+	copy(genVM.stack, args)
+	genVM.prepareArguments(cl.Fn, 0, len(args))
+	genVM.sp = cl.Fn.NumLocals
+
+	return objects.NewGenerator(cl.Fn.Name, genVM.resumeGenerator)
+}
+
+// newAsyncPromise runs the async closure as a coroutine in a VM of its own,
+// returning a promise that is settled once the closure has returned.
+func (vm *VM) newAsyncPromise(cl *objects.Closure, args []objects.Object) *objects.Promise {
+	adapter := &CompiledClosureAdapter{Closure: cl, VM: vm}
+	args = append([]objects.Object{}, args...)
+
+	return objects.NewAsyncPromise(func() objects.Object {
+		return adapter.call(args)
+	})
+}
+
+// resumeGenerator runs the generator until it yields its next value, or until
+// the generator function returns, after which the generator is exhausted.
+func (vm *VM) resumeGenerator() (objects.Object, bool, error) {
+	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+		vm.currentFrame().ip++
+
+		ip := vm.currentFrame().ip
+		ins := vm.currentFrame().Instructions()
+		op := code.Opcode(ins[ip])
+
+		switch {
+		case op == code.OpYield:
+			value := vm.pop()
+
+			err := vm.push(objects.NULL)
+			if err != nil {
+				return nil, false, err
+			}
+
+			return value, true, nil
+		case (op == code.OpReturnValue || op == code.OpReturn) && vm.framesIndex == 1:
+			vm.closeUpvalues(0)
+
+			return objects.NULL, false, nil
+		}
+
+		err := vm.executeInstructions(op, ins, ip)
+		if err != nil && !vm.catchError(err) {
+			return nil, false, err
+		}
+	}
+
+	return objects.NULL, false, nil
+}
+
+func (vm *VM) callBoundMethod(bm *objects.BoundMethod, numArgs int) error {
+	cl, ok := bm.Method.(*objects.Closure)
+	if !ok {
+		return fmt.Errorf("calling non-function and non-builtin")
+	}
+
+	err := bm.ValidateArguments(numArgs)
+	if err != nil {
+		return err
+	}
+
+	if vm.sp >= STACK_SIZE {
+		return fmt.Errorf("stack overflow")
+	}
+
+	// The receiver is inserted in front of the arguments, so the
+	// method receives the instance as its first parameter.
+	startIndex := vm.sp - numArgs
+	copy(vm.stack[startIndex+1:vm.sp+1], vm.stack[startIndex:vm.sp])
+	vm.stack[startIndex] = bm.Receiver
+	vm.sp++
+
+	return vm.enterClosure(cl, numArgs+1)
+}
+
+func (vm *VM) callStruct(structObj *objects.Struct, numArgs int) error {
+	instance, err := structObj.NewInstance(vm.stack[vm.sp-numArgs : vm.sp])
+	if err != nil {
+		return err
 	}
 
-	if numArgs != icl.Closure.Fn.NumParameters {
-		return objects.NewWrongNumberOfArgumentsError(name, icl.Closure.Fn.NumParameters, numArgs)
+	vm.sp = vm.sp - numArgs - 1
+
+	return vm.push(instance)
+}
+
+func (vm *VM) callEnumVariant(variant *objects.EnumVariant, numArgs int) error {
+	value, err := variant.New(vm.stack[vm.sp-numArgs : vm.sp])
+	if err != nil {
+		return err
+	}
+
+	vm.sp = vm.sp - numArgs - 1
+
+	return vm.push(value)
+}
+
+func (vm *VM) callImportedClosure(icl *objects.ImportedClosure, numArgs int) error {
+	err := icl.Closure.Fn.ValidateArguments(numArgs)
+	if err != nil {
+		return err
 	}
 
 	importCtx := vm.imports[icl.ImportContextIndex]
@@ -715,10 +1616,13 @@ func (vm *VM) callImportedClosure(icl *objects.ImportedClosure, numArgs int) err
 	funcVM.pushFrame(frame)
 
 	copy(funcVM.stack, vm.stack[vm.sp-numArgs:vm.sp])
+	funcVM.prepareArguments(icl.Closure.Fn, 0, numArgs)
 	funcVM.sp = icl.Closure.Fn.NumLocals
 
 	vm.sp = vm.sp - numArgs - 1
 
+	defer funcVM.closeUpvalues(0)
+
 	for funcVM.currentFrame().ip < len(funcVM.currentFrame().Instructions())-1 {
 		funcVM.currentFrame().ip++
 
@@ -731,10 +1635,10 @@ func (vm *VM) callImportedClosure(icl *objects.ImportedClosure, numArgs int) err
 
 		op := code.Opcode(ins[ip])
 
-		switch op {
-		case code.OpReturnValue:
+		switch {
+		case op == code.OpReturnValue && funcVM.framesIndex == 1:
 			return vm.push(funcVM.pop())
-		case code.OpReturn:
+		case op == code.OpReturn && funcVM.framesIndex == 1:
 			return vm.push(objects.NULL)
 		}
 
@@ -756,17 +1660,35 @@ func (vm *VM) callBuiltin(builtin *objects.Builtin, numArgs int) error {
 	}
 
 	if vm.settings.CaptureStdout {
-		return vm.push(captureStdoutForBuiltin(builtin, args))
+		result, err := captureStdoutForBuiltin(builtin, args)
+		if err != nil {
+			return vm.handleBuiltinError(err)
+		}
+
+		return vm.push(result)
 	}
 
 	result, err := builtin.Fn(args...)
 	if err != nil {
-		result = objects.NativeErrorToErrorObject(err)
+		return vm.handleBuiltinError(err)
 	}
 
 	return vm.push(result)
 }
 
+// handleBuiltinError pushes the builtin error onto the stack as a value,
+// unless we're inside a try block, in which case it's raised so it can
+// be caught by the closest error handler.
+func (vm *VM) handleBuiltinError(err error) error {
+	for _, handler := range vm.handlers {
+		if !handler.catching {
+			return err
+		}
+	}
+
+	return vm.push(objects.NativeErrorToErrorObject(err))
+}
+
 func (vm *VM) executeImport(idx int) error {
 	definition := vm.constants[idx]
 
@@ -785,9 +1707,10 @@ func (vm *VM) executeImportZenFile(cfi *objects.CompiledZenFileImport) error {
 	childVM := NewWithSettings(&compiler.Bytecode{
 		Instructions: cfi.OpcodeInstructions,
 		Constants:    cfi.Constants,
+		NumLocals:    cfi.NumLocals,
 	}, vm.settings)
 
-	if err := childVM.Run(); err != nil {
+	if err := childVM.run(); err != nil {
 		return fmt.Errorf("failed to execute imported file: %w", err)
 	}
 
@@ -1,9 +1,11 @@
 package vm
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/senither/zen-lang/objects"
+	"github.com/senither/zen-lang/objects/decimal"
 	"github.com/senither/zen-lang/objects/timer"
 )
 
@@ -57,6 +59,108 @@ func TestIntegerArithmetic(t *testing.T) {
 	runVmTests(t, tests)
 }
 
+func TestBigIntegers(t *testing.T) {
+	bigInt := func(value string) *big.Int {
+		result, _ := new(big.Int).SetString(value, 10)
+		return result
+	}
+
+	tests := []vmTestCase{
+		{nil, "9223372036854775807 + 1", bigInt("9223372036854775808")},
+		{nil, "-9223372036854775807 - 2", bigInt("-9223372036854775809")},
+		{nil, "9223372036854775807 * 3", bigInt("27670116110564327421")},
+		{nil, "2 ^ 100", bigInt("1267650600228229401496703205376")},
+		{nil, "99999999999999999999", bigInt("99999999999999999999")},
+		{nil, "99999999999999999999 - 99999999999999999998", 1},
+		{nil, "-9223372036854775808", -9223372036854775808},
+		{nil, "2 ^ 64 / 2 ^ 32", 4294967296},
+		{nil, "2 ^ 64 / 3", 6148914691236517205.333333},
+		{nil, "2 ^ 64 % 10", 6},
+		{nil, "2 ^ 64 > 9223372036854775807", true},
+		{nil, "2 ^ 64 == 2 ^ 64", true},
+		{nil, "2 ^ 64 + 0.5", 18446744073709551616.5},
+		{nil, "var mut n = 9223372036854775807; n++; n", bigInt("9223372036854775808")},
+		{nil, "func f() { var mut n = 9223372036854775807; n++; n }; f()", bigInt("9223372036854775808")},
+		{nil, "var mut n = 9223372036854775807; n++; n--; n", 9223372036854775807},
+		{nil, "~(2 ^ 64)", bigInt("-18446744073709551617")},
+		{nil, "(2 ^ 64) >> 60", 16},
+		{
+			"factorial",
+			"func factorial(n) { if (n <= 1) { return 1; }; return n * factorial(n - 1); }; factorial(25)",
+			bigInt("15511210043330985984000000"),
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestBigIntegerErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "exponent too large",
+			input:    "2 ^ 9999999999",
+			expected: "integer overflow: 2 ^ 9999999999 is too large",
+		},
+		{
+			name:     "shift too large",
+			input:    "1 << 9999999999",
+			expected: "integer overflow: 1 << 9999999999 is too large",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func TestDecimals(t *testing.T) {
+	tests := []vmTestCase{
+		{nil, "19.99d", decimal.MustParse("19.99")},
+		{nil, "0.1d + 0.2d", decimal.MustParse("0.3")},
+		{nil, "0.1d + 0.2d == 0.3d", true},
+		{nil, "19.99d * 3", decimal.MustParse("59.97")},
+		{nil, "10.00d / 4", decimal.MustParse("2.50")},
+		{nil, "1d / 3", decimal.MustParse("0.3333333333333333")},
+		{nil, "-7.5d % 2", decimal.MustParse("-1.5")},
+		{nil, "1.1d ^ 2", decimal.MustParse("1.21")},
+		{nil, "-19.99d", decimal.MustParse("-19.99")},
+		{nil, "0.1 + 0.2d", decimal.MustParse("0.3")},
+		{nil, "1.50d == 1.5d", true},
+		{nil, "1d == 1", true},
+		{nil, "2.5d > 2", true},
+		{nil, "var mut n = 1.5d; n++; n", decimal.MustParse("2.5")},
+		{nil, "func f() { var mut n = 1.5d; n--; n }; f()", decimal.MustParse("0.5")},
+		{nil, "decimal('2.675', 2)", decimal.MustParse("2.68")},
+		{nil, "decimal('2.5', 0, 'half_even')", decimal.MustParse("2")},
+		{nil, "math.round(19.995d, 2, 'half_even')", decimal.MustParse("20.00")},
+		{nil, "math.round(2.675, 2)", 2.68},
+		{nil, "int(19.99d)", 19},
+		{nil, "float(19.99d)", 19.99},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkBigIntegers(b *testing.B) {
+	runVmBenchmark(b, `
+		func factorial(n) { if (n <= 1) { return 1; }; return n * factorial(n - 1); }
+		factorial(50)
+	`)
+}
+
 func BenchmarkIntegerArithmeticSimple(b *testing.B) {
 	runVmBenchmark(b, `5 + 10 - 3 * 2 / 4 + 6 ^ 2 % 4`)
 }
@@ -65,6 +169,77 @@ func BenchmarkIntegerArithmeticComplex(b *testing.B) {
 	runVmBenchmark(b, `(5 + 10 - 3 * 2 / 4 + 6 ^ 2 % 4) * (12 - 4 + 3 * 7 / 2 ^ 3 % 5) + (8 ^ 2 % 5 + 14 - 3 * 6 / 2 + 9)`)
 }
 
+func TestBitwiseExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{nil, "12 & 10", 8},
+		{nil, "12 | 10", 14},
+		{nil, "12 ~ 10", 6},
+		{nil, "~0", -1},
+		{nil, "~12", -13},
+		{nil, "~~12", 12},
+		{nil, "1 << 4", 16},
+		{nil, "256 >> 4", 16},
+		{nil, "-16 >> 2", -4},
+		{nil, "1 << 64", new(big.Int).Lsh(big.NewInt(1), 64)},
+		{nil, "1 << 2 + 1", 8},
+		{nil, "240 & 60", 48},
+		{nil, "6 & 3 == 2", true},
+		{nil, "1 | 2 ~ 3 & 4", 3},
+		{nil, "var mut flags = 1; flags |= 4; flags", 5},
+		{nil, "var mut flags = 7; flags &= ~2; flags", 5},
+		{nil, "var mut flags = 5; flags ~= 1; flags", 4},
+		{nil, "var mut n = 1; n <<= 3; n >>= 1; n", 4},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestBitwiseWithWrongTypes(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "bitwise and with floats",
+			input:    "1.5 & 1",
+			expected: "unknown operator: FLOAT & INTEGER",
+		},
+		{
+			name:     "bitwise or with strings",
+			input:    `"a" | "b"`,
+			expected: "unknown operator: STRING | STRING",
+		},
+		{
+			name:     "bitwise not with boolean",
+			input:    "~true",
+			expected: "unknown operator: ~BOOLEAN",
+		},
+		{
+			name:     "negative shift count",
+			input:    "1 << -1",
+			expected: "negative shift count: -1",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkBitwiseExpressions(b *testing.B) {
+	runVmBenchmark(b, `(12 & 10 | 3 ~ 5) << 2 >> 1 & ~1`)
+}
+
 func TestBooleanExpressions(t *testing.T) {
 	tests := []vmTestCase{
 		{nil, "true", true},
@@ -140,6 +315,25 @@ func BenchmarkStringExpressions(b *testing.B) {
 	runVmBenchmark(b, `"hello" + " " + "world"`)
 }
 
+func TestStringInterpolation(t *testing.T) {
+	tests := []vmTestCase{
+		{"interpolated identifier", `var name = "World"; "Hello ${name}!"`, "Hello World!"},
+		{"interpolated expression", `"1 + 2 = ${1 + 2}"`, "1 + 2 = 3"},
+		{"interpolated hash access", `var user = {"name": "Alexis"}; "Hi ${user.name}"`, "Hi Alexis"},
+		{"interpolated function call", `"${len([1, 2, 3])} items"`, "3 items"},
+		{"interpolated nested string", `"a ${"b ${"c"}"}"`, "a b c"},
+		{"interpolated objects", `"${null} ${true} ${1.5} ${[1, 2]}"`, "null true 1.5 [1, 2]"},
+		{"escaped interpolation", `"\${name}"`, "${name}"},
+		{"single quoted string", `'${name}'`, "${name}"},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkStringInterpolation(b *testing.B) {
+	runVmBenchmark(b, `var name = "World"; "Hello ${name}, ${1 + 2} times!"`)
+}
+
 func TestArrayLiterals(t *testing.T) {
 	tests := []vmTestCase{
 		{"empty array", "[]", []int{}},
@@ -213,6 +407,11 @@ func TestIndexExpressions(t *testing.T) {
 		{"array index of 99 out of bounds", "[1, 2, 3][99]", nil},
 		{"array index negative", "[1][-1]", 1},
 		{"array index negative out of bounds", "[1][-2]", nil},
+		{"array index assignment negative", "var x = [1, 2, 3]; x[-1] = 9; x", []any{1, 2, 9}},
+		{"string index", `"zen"[1]`, "e"},
+		{"string index multi-byte character", `"héllo"[1]`, "é"},
+		{"string index negative", `"日本語"[-1]`, "語"},
+		{"string index out of bounds", `"héllo"[5]`, nil},
 		{"hash index of 1", "{1: 1, 2: 2}[1]", 1},
 		{"hash index of 2", "{1: 1, 2: 2}[2]", 2},
 		{"hash index not exists", "{1: 1}[0]", nil},
@@ -226,6 +425,544 @@ func BenchmarkIndexExpressions(b *testing.B) {
 	runVmBenchmark(b, "[1, 2, 3][1]")
 }
 
+func TestRangeExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{"inclusive range", "[...1..5]", []any{1, 2, 3, 4, 5}},
+		{"exclusive range", "[...1..<5]", []any{1, 2, 3, 4}},
+		{"empty range", "[...5..1]", []any{}},
+		{"range with expressions", "var n = 2; [...n - 1..n * 2]", []any{1, 2, 3, 4}},
+		{"range with negative bounds", "[...-2..0]", []any{-2, -1, 0}},
+		{"spreading range into arguments", "func(a, b) { a + b }(...1..2)", 3},
+		{"iterating range", "var mut sum = 0; for (i in 1..4) { sum += i; } sum", 10},
+		{"iterating range with index", "var mut sum = 0; for (i, v in 5..<8) { sum += i * v; } sum", 20},
+		{"range equality", "1..3 == 1..3", true},
+		{"range inequality", "1..3 == 1..<3", false},
+		{"range equality with exclusive end", "1..3 == 1..<4", true},
+		{"empty range inequality", "5..1 == 5..3", false},
+		{"reversed range inequality", "5..1 == 1..5", false},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkRangeExpressions(b *testing.B) {
+	runVmBenchmark(b, "var mut sum = 0; for (i in 1..100) { sum += i; } sum")
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{"slice array with both bounds", "[1, 2, 3, 4, 5][1:3]", []any{2, 3}},
+		{"slice array without start", "[1, 2, 3, 4, 5][:2]", []any{1, 2}},
+		{"slice array without end", "[1, 2, 3, 4, 5][3:]", []any{4, 5}},
+		{"slice array without bounds", "[1, 2, 3][:]", []any{1, 2, 3}},
+		{"slice array with negative end", "[1, 2, 3][:-1]", []any{1, 2}},
+		{"slice array with negative start", "[1, 2, 3][-2:]", []any{2, 3}},
+		{"slice array out of bounds", "[1, 2, 3][1:99]", []any{2, 3}},
+		{"slice array with start after end", "[1, 2, 3][2:1]", []any{}},
+		{"slice array returns a copy", "var a = [1, 2, 3]; var b = a[:]; b[0] = 9; a", []any{1, 2, 3}},
+		{"slice string", `"hello world"[6:]`, "world"},
+		{"slice string with negative bounds", `"hello"[1:-1]`, "ell"},
+		{"slice string out of bounds", `"hello"[-99:99]`, "hello"},
+		{"slice string with multi-byte characters", `"Grüße"[1:4]`, "rüß"},
+		{"slice with null bound", "[1, 2, 3][null:2]", []any{1, 2}},
+		{"slice chained array", `var h = {"items": [1, 2, 3]}; h.items[1:]`, []any{2, 3}},
+		{"slice optional null", "var a = null; a?[1:]", nil},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestRangeAndSliceWithWrongTypes(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "range with float bounds",
+			input:    "1.5..3",
+			expected: "range bounds must be integers, got FLOAT and INTEGER",
+		},
+		{
+			name:     "slice with string bounds",
+			input:    `[1, 2][1:"2"]`,
+			expected: "slice bounds must be integers, got STRING",
+		},
+		{
+			name:     "slice hash",
+			input:    `{"a": 1}[0:1]`,
+			expected: "slice operator not supported: HASH",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkSliceExpressions(b *testing.B) {
+	runVmBenchmark(b, `[1, 2, 3, 4, 5][1:-1]; "hello world"[6:]`)
+}
+
+func TestStructs(t *testing.T) {
+	tests := []vmTestCase{
+		{"struct field access", `struct User { name, age }; var u = User("Alice", 30); u.age`, 30},
+		{"struct missing fields are null", `struct User { name, age }; var u = User("Alice"); u.age`, nil},
+		{"struct field assignment", `struct User { name }; var u = User("Alice"); u.name = "Bob"; u.name`, "Bob"},
+		{"struct index access", `struct User { name }; var u = User("Alice"); u["name"]`, "Alice"},
+		{"struct index assignment", `struct User { name }; var u = User(); u["name"] = "Bob"; u.name`, "Bob"},
+		{"struct field array assignment", `struct List { items }; var l = List([1, 2]); l.items[0] = 5; l.items`, []any{5, 2}},
+		{"struct type", `struct User { name }; type(User("Alice"))`, "User"},
+		{"struct definition type", `struct User { name }; type(User)`, "STRUCT"},
+		{"struct equality", `struct P { x }; P(1) == P(1)`, true},
+		{"struct inequality", `struct P { x }; P(1) == P(2)`, false},
+		{
+			"struct method",
+			`struct User { name }; func (u User) greet(greeting) { greeting + ", " + u.name }; var u = User("Alice"); u.greet("Hi")`,
+			"Hi, Alice",
+		},
+		{
+			"struct method with default parameter",
+			`struct User { name }; func (u User) greet(greeting = "Hello") { greeting + ", " + u.name }; var u = User("Alice"); u.greet()`,
+			"Hello, Alice",
+		},
+		{
+			"struct method modifying receiver",
+			`struct Counter { count }; func (c Counter) inc() { c.count = c.count + 1 }; var c = Counter(0); c.inc(); c.inc(); c.count`,
+			2,
+		},
+		{
+			"struct method calling another method",
+			`struct P { x, y }; func (p P) sum() { p.x + p.y }; func (p P) double() { p.sum() * 2 }; var p = P(2, 3); p.double()`,
+			10,
+		},
+		{
+			"struct method returning new instance",
+			`struct P { x }; func (p P) add(o) { P(p.x + o.x) }; var a = P(1); var b = a.add(P(2)); b.x`,
+			3,
+		},
+		{
+			"struct method captured in closure",
+			`struct P { x }; func (p P) get() { func() { p.x } }; var p = P(7); var get = p.get(); get()`,
+			7,
+		},
+		{
+			"struct bound method",
+			`struct P { x }; func (p P) get() { p.x }; var p = P(4); var get = p.get; get()`,
+			4,
+		},
+		{
+			"struct instances in hash",
+			`struct P { x }; var h = {"p": P(9)}; h.p.x`,
+			9,
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "unknown field",
+			input:    `struct User { name }; var u = User(); u.age`,
+			expected: `struct User has no field or method named "age"`,
+		},
+		{
+			name:     "assigning unknown field",
+			input:    `struct User { name }; var u = User(); u.age = 30`,
+			expected: `struct User has no field named "age"`,
+		},
+		{
+			name:     "too many constructor arguments",
+			input:    `struct User { name }; User("Alice", 30)`,
+			expected: "wrong number of arguments to `User`: got 2, want at most 1",
+		},
+		{
+			name:     "too few method arguments",
+			input:    `struct User { name }; func (u User) greet(greeting) { greeting }; var u = User(); u.greet()`,
+			expected: "wrong number of arguments to `User.greet`: got 0, want 1",
+		},
+		{
+			name:     "method on non-struct",
+			input:    `var User = 1; func (u User) greet() { 1 }`,
+			expected: "cannot define method on non-struct type: INTEGER",
+		},
+		{
+			name:     "method conflicting with field",
+			input:    `struct User { name }; func (u User) name() { 1 }`,
+			expected: "method name conflicts with a field of struct User",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkStructs(b *testing.B) {
+	runVmBenchmark(b, `
+		struct Counter { count }
+		func (c Counter) inc() { c.count = c.count + 1 }
+		var c = Counter(0);
+		for (i in 1..100) { c.inc(); }
+		c.count
+	`)
+}
+
+func TestEnums(t *testing.T) {
+	tests := []vmTestCase{
+		{"enum variant", "enum Status { Active, Disabled }; string(Status.Active)", "Status.Active"},
+		{"enum variant with values", `enum Status { Active, Banned(reason) }; string(Status.Banned("spam"))`, "Status.Banned(spam)"},
+		{"enum definition", "enum Status { Active, Banned(reason) }; string(Status)", "enum Status { Active, Banned(reason) }"},
+		{"enum variant equality", "enum Status { Active, Disabled }; Status.Active == Status.Active", true},
+		{"enum variant inequality", "enum Status { Active, Disabled }; Status.Active == Status.Disabled", false},
+		{"enum variant values equality", "enum Status { Banned(reason) }; Status.Banned(1) == Status.Banned(1)", true},
+		{"enum variant values inequality", "enum Status { Banned(reason) }; Status.Banned(1) != Status.Banned(2)", true},
+		{"enum variants of different enums", "enum A { X }; enum B { X }; A.X == B.X", false},
+		{"enum variant field access", `enum Status { Banned(reason) }; var s = Status.Banned("spam"); s.reason`, "spam"},
+		{"enum variant index access", `enum Status { Banned(reason) }; var s = Status.Banned("spam"); s[0]`, "spam"},
+		{"enum variant type", "enum Status { Active }; type(Status.Active)", "Status"},
+		{"enum definition type", "enum Status { Active }; type(Status)", "ENUM"},
+		{"enum in function", "func f() { enum S { A, B }; S.B }; var s = f(); string(s)", "S.B"},
+		{"match enum variant", "enum S { A, B }; match (S.B) { S.A => 1, S.B => 2 }", 2},
+		{"match enum variant values", "enum S { A(x, y) }; match (S.A(2, 3)) { S.A(x, y) => x * y }", 6},
+		{"match enum variant without values", "enum S { A(x) }; match (S.A(5)) { S.A => 1 }", 1},
+		{"match enum variant literal values", "enum S { A(x) }; match (S.A(5)) { S.A(1) => 1, S.A(5) => 2, S.A(x) => 3 }", 2},
+		{"match enum variant nested pattern", "enum S { A(x) }; match (S.A([1, 2])) { S.A([a, b]) => a + b, S.A(x) => x }", 3},
+		{"match enum variant value count", "enum S { A(x) }; match (S.A(5)) { S.A(x, y) => 1, _ => 2 }", 2},
+		{"match non-variant against variant", "enum S { A }; match (1) { S.A => 1, _ => 2 }", 2},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestEnumErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "unknown variant",
+			input:    "enum Status { Active }; Status.Banned",
+			expected: `enum Status has no variant named "Banned"`,
+		},
+		{
+			name:     "wrong number of variant values",
+			input:    "enum Status { Banned(reason) }; Status.Banned(1, 2)",
+			expected: "wrong number of arguments to `Status.Banned`: got 2, want 1",
+		},
+		{
+			name:     "calling variant without values",
+			input:    "enum Status { Active }; Status.Active(1)",
+			expected: "variant Status.Active doesn't take any values",
+		},
+		{
+			name:     "unknown variant field",
+			input:    "enum Status { Banned(reason) }; var s = Status.Banned(1); s.until",
+			expected: `variant Status.Banned has no field named "until"`,
+		},
+		{
+			name:     "unknown variant in pattern",
+			input:    "enum Status { Active }; match (Status.Active) { Status.Banned => 1 }",
+			expected: `enum Status has no variant named "Banned"`,
+		},
+		{
+			name:     "matching against non-variant",
+			input:    `var S = {"A": 1}; match (1) { S.A => 1 }`,
+			expected: "cannot match against non-variant type: INTEGER",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkEnums(b *testing.B) {
+	runVmBenchmark(b, `
+		enum Shape { Circle(radius), Square(side) }
+		var area = func(shape) {
+			match (shape) {
+				Shape.Circle(r) => r * r * 3,
+				Shape.Square(s) => s * s,
+			}
+		};
+		var mut total = 0;
+		for (i in 1..100) { total = total + area(Shape.Circle(i)) + area(Shape.Square(i)); }
+		total
+	`)
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []vmTestCase{
+		{"generator next value", "func* g() { yield 1; yield 2; }; var it = g(); it.next(); var r = it.next(); r.value", 2},
+		{"generator next done", "func* g() { yield 1; }; var it = g(); it.next(); var r = it.next(); r.done", true},
+		{"generator next not done", "func* g() { yield 1; }; var it = g(); var r = it.next(); r.done", false},
+		{"generator empty yield", "func* g() { yield; }; var it = g(); var r = it.next(); r.value", nil},
+		{"generator arguments", "func* g(a, b = 2) { yield a + b; }; var it = g(1); var r = it.next(); r.value", 3},
+		{"generator for loop", "func* g(n) { var mut i = 0; while (i < n) { yield i; i++; } }; var mut sum = 0; for (v in g(5)) { sum = sum + v; }; sum", 10},
+		{"generator yield in if", "func* g() { for (i in 1..6) { if (i % 2 == 0) { yield i; } } }; var mut sum = 0; for (v in g()) { sum = sum + v; }; sum", 12},
+		{"generator return ends", "func* g() { yield 1; return null; yield 2; }; var mut sum = 0; for (v in g()) { sum = sum + v; }; sum", 1},
+		{"generator is lazy", "var mut count = 0; func* g() { count++; yield 1; }; var it = g(); count", 0},
+		{"generator infinite with break", "func* g() { var mut i = 0; while (true) { yield i; i++; } }; var mut last = 0; for (v in g()) { if (v == 3) { break; }; last = v; }; last", 2},
+		{"generator type", "func* g() { yield 1; }; type(g())", "GENERATOR"},
+		{"generator method", "struct Counter { max }; func* (c Counter) count() { var mut i = 0; while (i < c.max) { yield i; i++; } }; var c = Counter(4); var mut sum = 0; for (v in c.count()) { sum = sum + v; }; sum", 6},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "error thrown in generator",
+			input:    `func* g() { yield 1; throw "out of numbers"; }; for (v in g()) { v }`,
+			expected: "out of numbers",
+		},
+		{
+			name:     "unknown generator method",
+			input:    "func* g() { yield 1; }; var it = g(); it.reset()",
+			expected: `generator has no method named "reset"`,
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkGenerators(b *testing.B) {
+	runVmBenchmark(b, `
+		func* numbers(n) {
+			var mut i = 0;
+			while (i < n) { yield i; i++; }
+		}
+		var mut total = 0;
+		for (v in numbers(100)) { total = total + v; }
+		total
+	`)
+}
+
+func TestTasks(t *testing.T) {
+	tests := []vmTestCase{
+		{"task await", "func f(a, b) { a + b }; var t = spawn f(1, 2); t.await()", 3},
+		{"task await twice", "var t = spawn func() { 5 }(); t.await() + t.await()", 10},
+		{"task default arguments", "func f(a, b = 10) { a + b }; var t = spawn f(1); t.await()", 11},
+		{"task spread arguments", "func f(a, b) { a * b }; var args = [3, 4]; var t = spawn f(...args); t.await()", 12},
+		{"task builtin", `var t = spawn len("hello"); t.await()`, 5},
+		{"task bound method", "struct P { x }; func (p P) double() { p.x * 2 }; var p = P(4); var m = p.double; var t = spawn m(); t.await()", 8},
+		{"task done", "var t = spawn func() { 1 }(); t.await(); t.done()", true},
+		{"task type", "var t = spawn func() { 1 }(); type(t)", "TASK"},
+		{"task shares globals", "var mut n = 0; func f() { n = n + 5 }; var t = spawn f(); t.await(); n", 5},
+		{"task caught error", `var t = spawn func() { throw "boom" }(); var mut r = ""; try { t.await() } catch (e) { r = e.message }; r`, "boom"},
+		{
+			"tasks with channels",
+			`var ch = channels.make(); func f(n) { channels.send(ch, n * n) }; spawn f(2); spawn f(3);
+			channels.receive(ch) + channels.receive(ch)`,
+			13,
+		},
+		{
+			"tasks with shared counter",
+			`var done = channels.make(); var mut count = 0;
+			func f() { for (i in 1..2000) { count++ }; channels.send(done, true) };
+			spawn f(); spawn f(); channels.receive(done); channels.receive(done); count`,
+			4000,
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestTaskErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "spawning non-function",
+			input:    "var x = 5; spawn x()",
+			expected: "cannot spawn non-function: INTEGER",
+		},
+		{
+			name:     "wrong number of arguments",
+			input:    "func f(a) { a }; spawn f(1, 2)",
+			expected: "wrong number of arguments to `f`: got 2, want 1",
+		},
+		{
+			name:     "unknown task method",
+			input:    "var t = spawn func() { 1 }(); t.cancel()",
+			expected: `task has no method named "cancel"`,
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func TestChannels(t *testing.T) {
+	tests := []vmTestCase{
+		{"buffered channel", "var ch = channels.make(2); channels.send(ch, 1); channels.send(ch, 2); channels.receive(ch)", 1},
+		{"closed channel", "var ch = channels.make(); channels.close(ch); channels.receive(ch)", nil},
+		{
+			"loop over channel",
+			`var ch = channels.make(); func f() { for (i in 1..4) { channels.send(ch, i) }; channels.close(ch) }; spawn f();
+			var mut sum = 0; for (v in ch) { sum = sum + v }; sum`,
+			10,
+		},
+		{
+			"select channel",
+			`var a = channels.make(); var b = channels.make(1); channels.send(b, "b"); channels.select([a, b])`,
+			[]any{1, "b"},
+		},
+		{"channel type", "type(channels.make())", "CHANNEL"},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkTasks(b *testing.B) {
+	runVmBenchmark(b, `
+		var results = channels.make(10);
+		func worker(n) {
+			var mut total = 0;
+			for (i in 1..n) { total = total + i; }
+			channels.send(results, total);
+		}
+		for (i in 1..10) { spawn worker(100); }
+		var mut total = 0;
+		for (i in 1..10) { total = total + channels.receive(results); }
+		total
+	`)
+}
+
+func TestAsyncFunctions(t *testing.T) {
+	tests := []vmTestCase{
+		{"async await result", "async func f(a, b) { a + b }; await f(1, 2)", 3},
+		{"async await non-promise", "await 5", 5},
+		{"async nested await", "async func f() { 2 }; async func g() { var v = await f(); v * 3 }; await g()", 6},
+		{"async runs eagerly", "var mut n = 0; async func f() { n = 7; }; f(); n", 7},
+		{"async closure", "var x = 4; var f = async func(y) { x * y }; await f(5)", 20},
+		{"async type", "async func f() { 1 }; type(f())", "PROMISE"},
+		{"async then", "async func f() { 2 }; var p = f(); var q = p.then(func(v) { v * 10 }); await q", 20},
+		{"async catch", `async func f() { throw "failed" }; var p = f(); var q = p.catch(func(err) { err.message }); await q`, "failed"},
+		{"async caught error", `async func f() { throw "boom" }; var mut r = ""; try { await f() } catch (e) { r = e.message }; r`, "boom"},
+		{"async await task", "var t = spawn func() { 9 }(); await t", 9},
+		{
+			"async then without arguments",
+			"async func f() { 1 }; var p = f(); p.then()",
+			&objects.Error{Message: "wrong number of arguments to `then`: got 0, want at least 1"},
+		},
+		{
+			"async then with too many arguments",
+			"async func f() { 1 }; var p = f(); var g = func(v) { v }; p.then(g, g, g)",
+			&objects.Error{Message: "wrong number of arguments to `then`: got 3, want at most 2"},
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestAsyncFunctionErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "uncaught async error",
+			input:    `async func f() { throw "async failure" }; await f()`,
+			expected: "async failure",
+		},
+		{
+			name:     "unknown promise method",
+			input:    "async func f() { 1 }; var p = f(); p.finally()",
+			expected: `promise has no method named "finally"`,
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkAsyncFunctions(b *testing.B) {
+	runVmBenchmark(b, `
+		async func square(n) { n * n }
+		async func total(n) {
+			var mut sum = 0;
+			for (i in 1..n) { sum = sum + await square(i); }
+			sum
+		}
+		await total(100)
+	`)
+}
+
 func TestChainIndexExpressions(t *testing.T) {
 	tests := []vmTestCase{
 		{
@@ -287,6 +1024,32 @@ func BenchmarkChainIndexExpressions(b *testing.B) {
 	`)
 }
 
+func TestOptionalChainExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{"optional key", `var x = {"a": {"b": 5}}; x?.a?.b`, 5},
+		{"optional null value", `var x = null; x?.a`, nil},
+		{"optional null value short-circuits chain", `var x = null; x?.a.b.c`, nil},
+		{"optional missing key", `var x = {"a": 1}; x?.b?.c`, nil},
+		{"optional nested null value", `var x = {"a": null}; x.a?.b`, nil},
+		{"optional index", `var x = [1, 2]; x?[1]`, 2},
+		{"optional index on null", `var x = null; x?[1]`, nil},
+		{"optional index in chain", `var x = {"a": [1, 2]}; x?.a?[0]`, 1},
+		{"optional index in chain on null", `var x = {"a": null}; x.a?[0]`, nil},
+		{"optional call", `var x = {"f": func() { 7 }}; x?.f()`, 7},
+		{"optional call on null", `var x = null; x?.f()`, nil},
+		{"optional chain with fallback", `var x = null; x?.a ?? 10`, 10},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkOptionalChainExpressions(b *testing.B) {
+	runVmBenchmark(b, `
+		var obj = {'a': {'b': 3}};
+		obj?.a?.b
+	`)
+}
+
 func TestChainIndexAssignment(t *testing.T) {
 	tests := []vmTestCase{
 		{
@@ -515,6 +1278,93 @@ func BenchmarkConditionals(b *testing.B) {
 	runVmBenchmark(b, "if (1 < 2) { 10 } else { 20 }")
 }
 
+func TestTernaryExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{"get value from truthy condition", "true ? 10 : 20", 10},
+		{"get value from falsy condition", "false ? 10 : 20", 20},
+		{"get value from integer condition", "1 ? 10 : 20", 10},
+		{"get value from null condition", "null ? 10 : 20", 20},
+		{"get value from comparison condition", "1 > 2 ? 10 : 20", 20},
+		{"get value from nested ternary", "false ? 10 : true ? 20 : 30", 20},
+		{"get value from ternary in function", "var max = func(a, b) { return a > b ? a : b }; max(3, 7);", 7},
+		{"only evaluates the chosen branch", "var mut i = 0; true ? i++ : i--; i;", 1},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkTernaryExpressions(b *testing.B) {
+	runVmBenchmark(b, "1 < 2 ? 10 : 20")
+}
+
+func TestNullCoalescingExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{"get left value when not null", "5 ?? 10", 5},
+		{"get right value when null", "null ?? 10", 10},
+		{"get false when left is false", "false ?? true", false},
+		{"get zero when left is zero", "0 ?? 10", 0},
+		{"get value from chained coalescing", "null ?? null ?? 15", 15},
+		{"get null when both sides are null", "null ?? null", nil},
+		{"get value from missing hash key", `var h = {"a": 1}; h["b"] ?? 2;`, 2},
+		{"does not evaluate right side when not null", "var mut i = 0; 1 ?? i++; i;", 0},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkNullCoalescingExpressions(b *testing.B) {
+	runVmBenchmark(b, "null ?? 10")
+}
+
+func TestMatchExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{"match integer literal", "match (2) { 1 => 10, 2 => 20, _ => 30 }", 20},
+		{"match multiple patterns", "match (3) { 1, 2, 3 => 10, _ => 20 }", 10},
+		{"match float against integer", "match (1.0) { 1 => 10, _ => 20 }", 10},
+		{"match string literal", `match ("b") { "a" => 1, "b" => 2 }`, 2},
+		{"match boolean literal", "match (false) { true => 1, false => 2 }", 2},
+		{"match null literal", "match (null) { 0 => 1, null => 2 }", 2},
+		{"match negative literal", "match (-5) { 5 => 1, -5 => 2 }", 2},
+		{"match wildcard", "match (99) { 1 => 1, _ => 2 }", 2},
+		{"match binding", "match (7) { n => n * 2 }", 14},
+		{"match array pattern", "match ([1, 2]) { [a, b] => a + b }", 3},
+		{"match array pattern length", "match ([1, 2, 3]) { [a, b] => 1, [a, b, c] => c }", 3},
+		{"match nested array pattern", "match ([1, [2, 3]]) { [1, [a, b]] => a * b }", 6},
+		{"match array pattern with literals", "match ([0, 5]) { [1, x] => x, [0, x] => x * 10 }", 50},
+		{"match hash pattern", `match ({"name": "zen"}) { { name } => name }`, "zen"},
+		{"match hash pattern with missing key", `match ({"a": 1}) { { b } => b, { a } => a }`, 1},
+		{"match hash pattern with nested pattern", `match ({"user": {"id": 4}}) { { user: { id } } => id }`, 4},
+		{"match hash pattern with literal value", `match ({"type": "b", "v": 2}) { { type: "a", v } => v, { type: "b", v } => v * 2 }`, 4},
+		{"match array against hash pattern", "match ([1]) { { a } => 1, _ => 2 }", 2},
+		{"match guard", "match (5) { n if n > 10 => 1, n if n > 3 => 2, _ => 3 }", 2},
+		{"match guard with multiple patterns", "match (2) { 1, 2 if false => 1, _ => 2 }", 2},
+		{"match block body", "match (1) { 1 => { var a = 5; a * 2 } }", 10},
+		{"match block body without value", "match (1) { 1 => { var a = 5; } }", nil},
+		{"match in function", `var f = func(x) { return match (x) { 0 => "zero", _ => "other" } }; f(0) + f(1);`, "zeroother"},
+		{"match return from arm", "var f = func(x) { match (x) { 0 => { return 1 }, _ => 2 }; return 3; }; f(0)", 1},
+		{"match nested match", "match (1) { 1 => match (2) { 2 => 3 } }", 3},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkMatchExpressions(b *testing.B) {
+	runVmBenchmark(b, `
+		var describe = func(v) {
+			match (v) {
+				1, 2 => "low",
+				[a, b] => a + b,
+				{ name } if name != "" => name,
+				_ => null,
+			}
+		};
+
+		describe(1);
+		describe([1, 2]);
+		describe({"name": "zen"});
+	`)
+}
+
 func TestGlobalVarStatements(t *testing.T) {
 	tests := []vmTestCase{
 		{"variable declaration and usage", "var a = 1; a;", 1},
@@ -530,6 +1380,78 @@ func BenchmarkGlobalVarStatements(b *testing.B) {
 	runVmBenchmark(b, "var a = 1; var b = a + 1; a + b;")
 }
 
+func TestDestructuringVarStatements(t *testing.T) {
+	tests := []vmTestCase{
+		{"array destructuring", "var [a, b] = [1, 2]; a + b;", 3},
+		{"array destructuring with missing elements", "var [a, b] = [1]; b;", nil},
+		{"array destructuring ignores extra elements", "var [a] = [1, 2, 3]; a;", 1},
+		{"array destructuring with wildcard", "var [_, b] = [1, 2]; b;", 2},
+		{"nested array destructuring", "var [a, [b, c]] = [1, [2, 3]]; a + b + c;", 6},
+		{"hash destructuring", `var { name, age } = {"name": "zen", "age": 3}; name + string(age);`, "zen3"},
+		{"hash destructuring with renamed keys", `var { "name": n, id: i } = {"name": "zen", "id": 1}; n + string(i);`, "zen1"},
+		{"hash destructuring with missing key", `var { name } = {}; name;`, nil},
+		{"nested hash destructuring", `var { user: { tags: [first] } } = {"user": {"tags": ["a", "b"]}}; first;`, "a"},
+		{"destructuring with default values", `var [a, b = 2] = [1]; var { c = 3, d = 4 } = {"d": 5}; a + b + c + d;`, 11},
+		{"destructuring with default for null value", "var [a = 5] = [null]; a;", 5},
+		{"destructuring with nested default pattern", "var [a, [b, c] = [2, 3]] = [1]; a + b + c;", 6},
+		{"destructuring with default referencing earlier binding", "var [a, b = a * 2] = [4]; b;", 8},
+		{"mutable destructuring", "var mut [a, b] = [1, 2]; a = a + b; a;", 3},
+		{"destructuring within function", "var f = func(pair) { var [a, b] = pair; a * b; }; f([3, 4]);", 12},
+		{"function parameter array pattern", "var f = func([a, b]) { a + b }; f([1, 2]);", 3},
+		{"function parameter hash pattern", `var f = func(x, { name, suffix = "!" }) { x + name + suffix }; f("hi ", {"name": "zen"});`, "hi zen!"},
+		{"named function parameter pattern", "func sum([a, b], c) { a + b + c }; sum([1, 2], 3);", 6},
+		{"closure over destructured parameter", "var f = func({ n }) { func() { n * 2 } }; f({\"n\": 4})();", 8},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestDestructuringWithWrongTypes(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "destructuring non array value",
+			input:    "var [a] = 1;",
+			expected: "cannot destructure INTEGER as an array",
+		},
+		{
+			name:     "destructuring non hash value",
+			input:    "var { a } = [1];",
+			expected: "cannot destructure ARRAY as a hash",
+		},
+		{
+			name:     "destructuring non array parameter",
+			input:    "var f = func([a]) { a }; f(true);",
+			expected: "cannot destructure BOOLEAN as an array",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkDestructuringVarStatements(b *testing.B) {
+	runVmBenchmark(b, `
+		var { name, tags: [first, second = "none"] } = {"name": "zen", "tags": ["a"]};
+		var sum = func([a, b], { c = 3 }) { a + b + c };
+
+		sum([1, 2], {});
+	`)
+}
+
 func TestCallingFunctionsWithoutArguments(t *testing.T) {
 	tests := []vmTestCase{
 		{
@@ -826,6 +1748,26 @@ func TestCallingFunctionsWithWrongArguments(t *testing.T) {
 			input:    `func(a, b) { a + b; }(1);`,
 			expected: "wrong number of arguments to `<anonymous>`: got 1, want 2",
 		},
+		{
+			name:     "function call with too few arguments for default parameters",
+			input:    `func(a, b = 1) { a + b; }();`,
+			expected: "wrong number of arguments to `<anonymous>`: got 0, want at least 1",
+		},
+		{
+			name:     "function call with too many arguments for default parameters",
+			input:    `func(a, b = 1) { a + b; }(1, 2, 3);`,
+			expected: "wrong number of arguments to `<anonymous>`: got 3, want at most 2",
+		},
+		{
+			name:     "function call with too few arguments for rest parameter",
+			input:    `func(a, ...b) { a; }();`,
+			expected: "wrong number of arguments to `<anonymous>`: got 0, want at least 1",
+		},
+		{
+			name:     "function call with too many spread arguments",
+			input:    `func(a) { a; }(...[1, 2]);`,
+			expected: "wrong number of arguments to `<anonymous>`: got 2, want 1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -850,6 +1792,89 @@ func BenchmarkCallingFunctionsWithWrongArguments(b *testing.B) {
 	runVmBenchmark(b, "func() { 1; }(1);")
 }
 
+func TestFunctionDefaultAndRestParameters(t *testing.T) {
+	tests := []vmTestCase{
+		{"default parameter", "var f = func(a, b = 10) { a + b }; f(1);", 11},
+		{"default parameter overridden", "var f = func(a, b = 10) { a + b }; f(1, 2);", 3},
+		{"default parameter with null argument", "var f = func(a, b = 10) { a + b }; f(1, null);", 11},
+		{"default parameter referencing earlier parameter", "var f = func(a, b = a * 2) { b }; f(4);", 8},
+		{"multiple default parameters", `var f = func(a = "x", b = "y") { a + b }; f() + f("a") + f("a", "b");`, "xyayab"},
+		{"default destructuring parameter", "var f = func({ n = 1 } = {}) { n }; f() + f({\"n\": 5});", 6},
+		{"rest parameter", "var f = func(...rest) { rest }; f(1, 2, 3);", []int{1, 2, 3}},
+		{"rest parameter without arguments", "var f = func(a, ...rest) { len(rest) }; f(1);", 0},
+		{"rest parameter after defaults", "var f = func(a, b = 2, ...rest) { a + b + len(rest) }; f(1) + f(1, 1, 1, 1);", 7},
+		{"named function with rest parameter", "func count(...items) { len(items) }; count(1, 2, 3);", 3},
+		{"closure with rest parameter", "var f = func(...a) { func() { a } }; f(1, 2)();", []int{1, 2}},
+		{"spread arguments", "var f = func(a, b, c) { a + b + c }; f(...[1, 2, 3]);", 6},
+		{"spread arguments with regular arguments", "var f = func(a, b, c) { a * b + c }; var args = [2]; f(5, ...args, 1);", 11},
+		{"spread arguments into rest parameter", "var f = func(...rest) { rest }; f(0, ...[1, 2], ...[3]);", []int{0, 1, 2, 3}},
+		{"spread empty array", "var f = func(a = 1) { a }; f(...[]);", 1},
+		{"spread arguments to builtin", "len(...[[1, 2, 3]]);", 3},
+		{"array literal spread", "var a = [1, 2]; var b = [4]; [0, ...a, 3, ...b];", []int{0, 1, 2, 3, 4}},
+		{"array literal spread copies elements", "var mut a = [1]; var b = [...a]; a[0] = 2; b;", []int{1}},
+		{"hash literal spread", `var base = {"a": 1, "b": 2}; var h = {...base, "c": 3}; h["a"] + h["b"] + h["c"];`, 6},
+		{"hash literal spread overridden by pairs", `var base = {"a": 1}; {"a": 5, ...base}["a"];`, 5},
+		{"hash literal multiple spreads", `var h = {...{"a": 1, "b": 1}, ...{"b": 2}}; h["a"] + h["b"];`, 3},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestSpreadWithWrongTypes(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "spreading non array into arguments",
+			input:    "func(a) { a }(...1);",
+			expected: "cannot spread non-array value: INTEGER",
+		},
+		{
+			name:     "spreading non array into array",
+			input:    `[..."abc"];`,
+			expected: "cannot spread non-array value: STRING",
+		},
+		{
+			name:     "spreading non hash into hash",
+			input:    "{...[1]};",
+			expected: "cannot spread non-hash value: ARRAY",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkFunctionDefaultAndRestParameters(b *testing.B) {
+	runVmBenchmark(b, `
+		var sum = func(first, second = 0, ...rest) {
+			var mut total = first + second;
+			for (n in rest) {
+				total += n;
+			}
+
+			total
+		};
+
+		sum(1);
+		sum(1, 2, ...[3, 4, 5]);
+		[...[1, 2], ...[3]];
+		{...{"a": 1}, "b": 2};
+	`)
+}
+
 func TestBuiltinFunctions(t *testing.T) {
 	tests := []vmTestCase{
 		{nil, `len("")`, 0},
@@ -1234,6 +2259,135 @@ func TestClosures(t *testing.T) {
 			`,
 			expected: 99,
 		},
+		{
+			name: "closure modifying a captured variable",
+			input: `
+				var newCounter = func() {
+					var mut count = 0
+					var increment = func() { count = count + 1 }
+					increment()
+					increment()
+					count
+				}
+
+				newCounter()
+			`,
+			expected: 2,
+		},
+		{
+			name: "closures sharing a captured variable after returning",
+			input: `
+				var newCounter = func() {
+					var mut count = 0;
+					[func() { count++ }, func() { count }]
+				}
+
+				var counter = newCounter()
+				var increment = counter[0]
+				var get = counter[1]
+				increment()
+				increment()
+				get()
+			`,
+			expected: 2,
+		},
+		{
+			name: "nested closure modifying a captured variable",
+			input: `
+				var outer = func() {
+					var mut total = 1
+					var middle = func() {
+						var inner = func() { total -= 3; total = total * 10 }
+						inner()
+					}
+					middle()
+					total
+				}
+
+				outer()
+			`,
+			expected: -20,
+		},
+		{
+			name: "captured variables are separate between calls",
+			input: `
+				var newCounter = func(start) {
+					var mut count = start;
+					func() { count = count + 1; count }
+				}
+
+				var a = newCounter(10)
+				var b = newCounter(20)
+				a()
+				b()
+				a()
+			`,
+			expected: 12,
+		},
+		{
+			name: "captured variable modified after the closure was created",
+			input: `
+				var newGetter = func() {
+					var mut value = 1
+					var get = func() { value }
+					value = 5
+					get
+				}
+
+				var get = newGetter()
+				get()
+			`,
+			expected: 5,
+		},
+		{
+			name: "captured variable modified in a caught error",
+			input: `
+				var run = func() {
+					var mut state = "start"
+					var fail = func() { state = "failed"; throw "boom" }
+					try { fail() } catch (e) { state = state + ": " + e.message }
+					state
+				}
+
+				run()
+			`,
+			expected: "failed: boom",
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestBlockScopes(t *testing.T) {
+	tests := []vmTestCase{
+		{"global block shadowing", "var a = 1; if (true) { var a = 2; }; a", 1},
+		{"global block value", "var a = 1; if (true) { var a = 2; a }", 2},
+		{
+			"global closures in loop capture each iteration",
+			"var mut f = null; for (i in [1, 2, 3]) { if (i == 1) { f = func() { i }; } }; f()",
+			1,
+		},
+		{
+			"global closures in while loop capture each iteration",
+			"var mut f = null; var mut j = 0; while (j < 3) { var k = j * 10; if (j == 0) { f = func() { k }; }; j++; }; f()",
+			0,
+		},
+		{"local block shadowing", "func f() { var a = 1; if (true) { var a = 2; }; a }; f()", 1},
+		{"while block shadowing", "var mut i = 0; var x = 5; while (i < 3) { var x = i; i++; }; x", 5},
+		{"modifying outer from block", "func f() { var mut a = 1; if (true) { a = a + 10; }; a }; f()", 11},
+		{"else block", "func f() { var a = 1; if (false) { var a = 2; } else { var a = 3; a } }; f()", 3},
+		{"catch block", `func f() { var e = 1; try { throw "x" } catch (e) { var e = 2; }; e }; f()`, 1},
+		{
+			"closures in loop capture each iteration",
+			`func f() { var mut fs = []; for (i in 1..3) { var v = i * 2; fs = [...fs, func() { v }]; }; fs }
+			var fs = f(); var a = fs[0]; var b = fs[2]; a() + b()`,
+			8,
+		},
+		{
+			"reused slot keeps captured value",
+			`func f() { var mut g = null; if (true) { var a = 1; g = func() { a }; }; var b = 2; g() + b }; f()`,
+			3,
+		},
 	}
 
 	runVmTests(t, tests)
@@ -1326,6 +2480,114 @@ func BenchmarkRecursiveFunctions(b *testing.B) {
 	`)
 }
 
+func TestTailCalls(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "deep tail recursion",
+			input:    "func sum(n, acc) { if (n == 0) { return acc; }; return sum(n - 1, acc + n); }; sum(100000, 0)",
+			expected: 5000050000,
+		},
+		{
+			name: "mutual tail recursion",
+			input: `
+				func isOdd(n, isEven) { if (n == 0) { return false; }; return isEven(n - 1, isOdd); }
+				func isEven(n, isOdd) { if (n == 0) { return true; }; return isOdd(n - 1, isEven); }
+				isEven(10001, isOdd)
+			`,
+			expected: false,
+		},
+		{
+			name:     "tail call to a builtin",
+			input:    `func f(s) { return len(s); }; f("hello")`,
+			expected: 5,
+		},
+		{
+			name:     "tail call with fewer locals",
+			input:    "func g(a) { return a * 2; }; func f(a, b) { var c = a + b; return g(c); }; f(1, 2)",
+			expected: 6,
+		},
+		{
+			name:     "tail call inside try statement",
+			input:    `func f(n) { try { if (n == 0) { return 0; }; return f(n - 1); } catch (e) { return -1; } }; f(10)`,
+			expected: 0,
+		},
+		{
+			name: "closures keep captured values across tail calls",
+			input: `
+				func f(n, g) { if (n == 0) { return g(); }; var v = n; return f(n - 1, func() { v }); }
+				f(3, func() { 0 })
+			`,
+			expected: 1,
+		},
+		{
+			name:     "tail call inside a builtin callback",
+			input:    "func isBig(v) { return v > 1; }; arrays.filter([1, 2, 3], func(v) { return isBig(v); })",
+			expected: []any{2, 3},
+		},
+		{
+			name:     "tail recursion inside a builtin callback",
+			input:    "func sum(n, acc) { if (n == 0) { return acc; }; return sum(n - 1, acc + n); }; arrays.filter([10, 1000], func(v) { return sum(v, 0) > 100; })",
+			expected: []any{1000},
+		},
+		{
+			name:     "tail call inside an async function",
+			input:    "func double(v) { return v * 2; }; async func f(v) { return double(v); }; await f(4)",
+			expected: 8,
+		},
+		{
+			name:     "tail call inside a task",
+			input:    "func double(v) { return v * 2; }; func g(v) { return double(v); }; var t = spawn g(5); t.await()",
+			expected: 10,
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestCallDepthErrors(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name:     "unbounded recursion",
+			input:    "func f() { f() }; f()",
+			expected: "maximum call depth exceeded",
+		},
+		{
+			name:     "unbounded recursion with locals",
+			input:    "func f(n) { var a = n; var b = n; 1 + f(n + 1) }; f(0)",
+			expected: "maximum call depth exceeded",
+		},
+		{
+			name:     "unbounded recursion with more locals than the stack can hold",
+			input:    "func f(n) { var [a, b, c, d, e, g, h, i, j, k] = [n, n, n, n, n, n, n, n, n, n]; 1 + f(n + 1) }; f(0)",
+			expected: "stack overflow",
+		},
+	}
+
+	for _, tt := range tests {
+		compiler, err := compile(tt.input)
+		if err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		vm := New(compiler.Bytecode())
+		err = vm.Run()
+		if err == nil {
+			t.Fatalf("expected VM error but resulted in none")
+		}
+
+		if err.Error() != tt.expected {
+			t.Fatalf("wrong VM error:\nwant:\n\t%q\ngot:\n\t%q", tt.expected, err)
+		}
+	}
+}
+
+func BenchmarkTailCalls(b *testing.B) {
+	runVmBenchmark(b, `
+		func sum(n, acc) { if (n == 0) { return acc; }; return sum(n - 1, acc + n); }
+		sum(1000, 0)
+	`)
+}
+
 func TestNamedFunctions(t *testing.T) {
 	tests := []vmTestCase{
 		{
@@ -1508,6 +2770,220 @@ func BenchmarkWhileLoops(b *testing.B) {
 	`)
 }
 
+func TestForLoops(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name: "for loop over array values",
+			input: `
+				var result = [];
+
+				for (v in [1, 2, 3]) {
+					arrays.push(result, v * 2);
+				}
+
+				result;
+			`,
+			expected: []int{2, 4, 6},
+		},
+		{
+			name: "for loop over array indexes",
+			input: `
+				var result = [];
+
+				for (i, v in ["a", "b", "c"]) {
+					arrays.push(result, i);
+				}
+
+				result;
+			`,
+			expected: []int{0, 1, 2},
+		},
+		{
+			name: "for loop over hash values",
+			input: `
+				var mut total = 0;
+
+				for (k, v in {"a": 1, "b": 2, "c": 3}) {
+					total = total + v;
+				}
+
+				total;
+			`,
+			expected: 6,
+		},
+		{
+			name: "for loop over string characters",
+			input: `
+				var mut result = "";
+
+				for (c in "zen") {
+					result = c + result;
+				}
+
+				result;
+			`,
+			expected: "nez",
+		},
+		{
+			name: "for loop with continue and break",
+			input: `
+				var result = [];
+
+				for (v in [1, 2, 3, 4, 5, 6]) {
+					if (v % 2 == 0) {
+						continue;
+					}
+
+					if (v > 4) {
+						break;
+					}
+
+					arrays.push(result, v);
+				}
+
+				result;
+			`,
+			expected: []int{1, 3},
+		},
+		{
+			name: "for loop inside function",
+			input: `
+				func sum(items) {
+					var mut total = 0;
+
+					for (v in items) {
+						total = total + v;
+					}
+
+					return total;
+				}
+
+				sum([1, 2, 3, 4]);
+			`,
+			expected: 10,
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkForLoops(b *testing.B) {
+	runVmBenchmark(b, `
+		var result = [];
+
+		for (v in [1, 2, 3, 4, 5, 6, 7, 8, 9]) {
+			arrays.push(result, v);
+		}
+
+		result;
+	`)
+}
+
+func TestTryStatements(t *testing.T) {
+	tests := []vmTestCase{
+		{
+			name: "catch thrown string",
+			input: `
+				var mut result = "";
+				try { throw "failed"; } catch (e) { result = e.message; }
+				result;
+			`,
+			expected: "failed",
+		},
+		{
+			name: "catch runtime error",
+			input: `
+				var mut result = "";
+				try { 1 + true; } catch (e) { result = e.message; }
+				result;
+			`,
+			expected: "unsupported types for binary operation: INTEGER BOOLEAN",
+		},
+		{
+			name: "catch builtin error",
+			input: `
+				var mut result = "";
+				try { json.parse("{"); } catch (e) { result = e.message; }
+				result;
+			`,
+			expected: "error in `parse`: unexpected end of JSON input",
+		},
+		{
+			name: "catch error thrown from function",
+			input: `
+				func fail() { throw "nested"; }
+				func run() {
+					try { fail(); } catch (e) { return e.message; }
+					return "ok";
+				}
+				run();
+			`,
+			expected: "nested",
+		},
+		{
+			name: "rethrow caught error",
+			input: `
+				var mut result = "";
+				try {
+					try { throw "inner"; } catch (e) { throw e; }
+				} catch (e) {
+					result = "outer " + e.message;
+				}
+				result;
+			`,
+			expected: "outer inner",
+		},
+		{
+			name: "return from within try statement",
+			input: `
+				func value() {
+					try { return 1; } catch { return 2; }
+				}
+				var mut result = 0;
+				try { value(); throw "x"; } catch { result = value(); }
+				result;
+			`,
+			expected: 1,
+		},
+		{
+			name: "break and continue within try statement",
+			input: `
+				var result = [];
+				var mut i = 0;
+				while (i < 10) {
+					i++;
+					try {
+						if (i == 2) { continue; }
+						if (i == 4) { break; }
+						throw i;
+					} catch (e) {
+						arrays.push(result, int(e.message));
+					}
+				}
+				try { throw "after"; } catch { arrays.push(result, 0); }
+				result;
+			`,
+			expected: []int{1, 3, 0},
+		},
+	}
+
+	runVmTests(t, tests)
+}
+
+func BenchmarkTryStatements(b *testing.B) {
+	runVmBenchmark(b, `
+		var mut result = 0;
+		var mut i = 0;
+
+		while (i < 10) {
+			i++;
+			try { throw i; } catch (e) { result = result + 1; }
+		}
+
+		result;
+	`)
+}
+
 func TestAssignmentExpressions(t *testing.T) {
 	tests := []vmTestCase{
 		{"simple assignment", "var mut a = 5; a = 10; a;", 10},
@@ -1540,6 +3016,15 @@ func TestCompoundAssignments(t *testing.T) {
 		{"division by 5", "var mut x = 5; x /= 5;", 1},
 		{"modulus by 5", "var mut x = 5; x %= 5;", 0},
 		{"exponentiation by 5", "var mut x = 5; x ^= 5;", 3125},
+		{"array index", "var x = [1, 2]; x[1] += 5; x[1];", 7},
+		{"nested array index", "var x = [[1, 2]]; x[0][1] *= 5; x[0][1];", 10},
+		{"hash index", `var x = {"a": 5}; x["a"] -= 2; x["a"];`, 3},
+		{"hash chain", `var x = {"a": 5}; x.a -= 2; x.a;`, 3},
+		{"nested hash chain", `var x = {"a": {"b": 3}}; x.a.b ^= 2; x.a.b;`, 9},
+		{"hash chain with index", `var x = {"a": [1, 2]}; x.a[0] += 9; x.a;`, []int{10, 2}},
+		{"nested hash chain in function", `func f() { var x = {"a": {"b": 3}}; x.a.b ^= 2; x.a.b += 1; return x.a.b; }; f();`, 10},
+		{"nested hash chain assignment in function", `func f() { var x = {"a": {"b": 3}}; x.a.b = 4; x.a.b *= 2; return x.a.b; }; f();`, 8},
+		{"string concatenation", `var mut x = "foo"; x += "bar";`, "foobar"},
 	}
 
 	runVmTests(t, tests)